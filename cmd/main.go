@@ -7,13 +7,22 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"time"
 
+	"orderbook/internal/analytics"
+	"orderbook/internal/arbitrage"
 	"orderbook/internal/config"
+	"orderbook/internal/derivatives"
 	"orderbook/internal/exchange"
 	"orderbook/internal/factory"
+	"orderbook/internal/history"
+	"orderbook/internal/instruments"
 	"orderbook/internal/orderbook"
+	"orderbook/internal/recorder"
+	"orderbook/internal/replay"
+	"orderbook/internal/symbols"
 	"orderbook/internal/websocket"
 
 	"github.com/shopspring/decimal"
@@ -21,23 +30,71 @@ import (
 
 func main() {
 	// Parse command line flags
-	var symbol = flag.String("symbol", "BTCUSDT", "Trading symbol to monitor")
+	var symbolList = flag.String("symbol", "BTCUSDT", "Trading symbols to monitor, comma-separated (e.g. BTCUSDT,ETHUSDT,SOLUSDT)")
 	var logInterval = flag.Duration("log-interval", 10*time.Second, "Interval for logging orderbook stats")
+	var recordDir = flag.String("record-dir", "", "Directory to record snapshots and depth updates to (disabled if empty)")
+	var replayDir = flag.String("replay-dir", "", "Replay recordings from this directory instead of connecting to exchanges")
+	var replaySpeed = flag.Float64("replay-speed", 1, "Replay speed multiplier (0 replays as fast as possible)")
 	flag.Parse()
 
 	// Set up signal handling
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
 
-	log.Printf("Starting multi-exchange orderbook monitor for %s", *symbol)
+	var symbolNames []string
+	for _, name := range strings.Split(*symbolList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			symbolNames = append(symbolNames, name)
+		}
+	}
+
+	if *replayDir != "" {
+		if *recordDir != "" {
+			log.Fatal("-record-dir cannot be used together with -replay-dir")
+		}
+		if len(symbolNames) != 1 {
+			log.Fatal("-replay-dir replays a single -symbol")
+		}
+		runReplay(*replayDir, symbolNames[0], *replaySpeed, *logInterval, interrupt)
+		return
+	}
+
+	seen := make(map[string]bool, len(symbolNames))
+	for _, name := range symbolNames {
+		sym, err := symbols.Parse(name)
+		if err != nil {
+			log.Fatalf("Invalid -symbol: %v", err)
+		}
+		if seen[sym.String()] {
+			log.Fatalf("Invalid -symbol: %s is listed twice", sym)
+		}
+		seen[sym.String()] = true
+	}
+	if maxSymbols := config.Default().App.MaxSymbols; len(symbolNames) == 0 || len(symbolNames) > maxSymbols {
+		log.Fatalf("Invalid -symbol: between 1 and %d symbols can be monitored", maxSymbols)
+	}
+
+	log.Printf("Starting multi-exchange orderbook monitor for %s", strings.Join(symbolNames, ", "))
 	log.Printf("Log interval: %v", *logInterval)
 
-	runMultiExchange(*symbol, *logInterval, interrupt)
+	var rec *recorder.Recorder
+	if *recordDir != "" {
+		var err error
+		rec, err = recorder.New(*recordDir)
+		if err != nil {
+			log.Fatalf("Failed to start recorder: %v", err)
+		}
+		defer rec.Close()
+		log.Printf("Recording market data to %s", *recordDir)
+	}
+
+	runMultiExchange(symbolNames, *logInterval, rec, interrupt)
 }
 
 type orderbookWithName struct {
-	name string
-	ob   *orderbook.OrderBook
+	name   string
+	ob     *orderbook.OrderBook
+	volume *analytics.TradeVolume // nil for venues without a trade stream
 }
 
 const (
@@ -56,7 +113,8 @@ func getExchangeNames() []exchange.ExchangeName {
 		exchange.Bybitf,
 		exchange.Bybit,
 		exchange.Kraken,
-		// exchange.OKX, // Disabled: IP blocking on Render (HTTP 403)
+		exchange.OKX,
+		exchange.OKXf,
 		exchange.Coinbase,
 		exchange.Asterdexf,
 		exchange.BingX,
@@ -64,12 +122,23 @@ func getExchangeNames() []exchange.ExchangeName {
 	}
 }
 
-func runMultiExchange(initialSymbol string, logInterval time.Duration, interrupt chan os.Signal) {
+// symbolRuntime is one monitored symbol with its own venue connections
+type symbolRuntime struct {
+	symbol symbols.Symbol
+	done   chan struct{} // Closed to stop the venues and the arbitrage detector
+	exited chan struct{} // Closed once every venue has shut down
+}
+
+// stop shuts the symbol's venues down and waits for them to close
+func (rt *symbolRuntime) stop() {
+	close(rt.done)
+	<-rt.exited
+}
+
+func runMultiExchange(initialSymbols []string, logInterval time.Duration, rec *recorder.Recorder, interrupt chan os.Signal) {
 	ctx := context.Background()
-	orderbooksMap := make(map[string]*orderbook.OrderBook)
-	var obMutex sync.Mutex
-	symbolChange := make(chan string, 1)
-	currentSymbol := initialSymbol
+	mapper := symbols.NewMapper()
+	symbolRequests := make(chan websocket.SymbolRequest, 8)
 
 	// Get port from environment variable (Railway) or default to 8086
 	port := os.Getenv("PORT")
@@ -78,62 +147,107 @@ func runMultiExchange(initialSymbol string, logInterval time.Duration, interrupt
 	}
 
 	// Start WebSocket server
-	wsServer := websocket.NewServer(orderbooksMap, port, symbolChange)
+	appConfig := config.Default().App
+	wsServer := websocket.NewServer(port, symbolRequests)
+	wsServer.SetHistoryBackfill(appConfig.HistoryBackfill)
+	wsServer.SetSymbolLimit(appConfig.MaxSymbols)
+	if err := wsServer.SetReferenceQuote(appConfig.ReferenceQuote); err != nil {
+		log.Fatalf("Invalid reference quote: %v", err)
+	}
+
+	// Every symbol runs with its own venues, so one can be added or removed without
+	// restarting the others. The first is the default symbol of new clients.
+	running := make(map[string]*symbolRuntime)
+	for _, symbol := range initialSymbols {
+		rt := startSymbol(ctx, symbol, mapper, wsServer, rec, logInterval)
+		running[rt.symbol.String()] = rt
+	}
+
 	go func() {
 		if err := wsServer.Start(); err != nil {
 			log.Fatalf("WebSocket server error: %v", err)
 		}
 	}()
 
-	// Main loop to handle symbol changes
+	// Main loop to handle symbols added and removed by clients
 	for {
-		log.Printf("Starting exchanges for symbol: %s", currentSymbol)
-
-		// Start all exchanges with current symbol
-		done := make(chan struct{})
-		exchangesDone := make(chan struct{})
-
-		go func() {
-			startExchangesForSymbol(ctx, currentSymbol, orderbooksMap, &obMutex, logInterval, done, interrupt)
-			close(exchangesDone)
-		}()
-
-		// Wait for either symbol change or interrupt
 		select {
-		case newSymbol := <-symbolChange:
-			log.Printf("Symbol change requested: %s -> %s", currentSymbol, newSymbol)
-			currentSymbol = newSymbol
-
-			// Signal exchanges to stop
-			close(done)
-
-			// Wait for all exchanges to cleanly shut down
-			<-exchangesDone
-
-			// Clear orderbooks map
-			obMutex.Lock()
-			for k := range orderbooksMap {
-				delete(orderbooksMap, k)
+		case req := <-symbolRequests:
+			// The server validates requests before they get here
+			sym := symbols.MustParse(req.Symbol)
+			rt, ok := running[sym.String()]
+			switch {
+			case req.Remove && ok:
+				log.Printf("Stopping exchanges for symbol: %s", sym)
+				wsServer.RemoveFeed(sym)
+				rt.stop()
+				delete(running, sym.String())
+				log.Printf("All %s exchanges stopped", sym)
+			case !req.Remove && !ok && len(running) >= appConfig.MaxSymbols:
+				log.Printf("Not starting %s: %d symbols are already monitored", sym, len(running))
+			case !req.Remove && !ok:
+				running[sym.String()] = startSymbol(ctx, req.Symbol, mapper, wsServer, rec, logInterval)
 			}
-			obMutex.Unlock()
-
-			log.Printf("All exchanges stopped. Restarting with symbol: %s", currentSymbol)
-			time.Sleep(500 * time.Millisecond)
 
 		case <-interrupt:
 			log.Println("Interrupt received, shutting down...")
-			close(done)
-			<-exchangesDone
+			for _, rt := range running {
+				close(rt.done)
+			}
+			for _, rt := range running {
+				<-rt.exited
+			}
 			log.Println("All exchanges closed. Goodbye!")
 			return
 		}
 	}
 }
 
-func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap map[string]*orderbook.OrderBook, obMutex *sync.Mutex, logInterval time.Duration, done chan struct{}, interrupt chan os.Signal) {
-	cfg := config.NewMultiExchange(buildExchangeConfigs(symbol))
+// startSymbol resolves a valid symbol on every venue, serves its feed and starts its venues and
+// arbitrage detector
+func startSymbol(ctx context.Context, symbol string, mapper *symbols.Mapper, wsServer *websocket.Server, rec *recorder.Recorder, logInterval time.Duration) *symbolRuntime {
+	sym := symbols.MustParse(symbol)
+	log.Printf("Starting exchanges for symbol: %s", sym)
+
+	// Resolve the symbol on every venue up front, so clients learn which venues lack it
+	markets, unsupported := mapper.ResolveAll(getExchangeNames(), sym)
+	for name, err := range unsupported {
+		log.Printf("[%s %s] Not started: %v", name, symbol, err)
+	}
+
+	names := make([]exchange.ExchangeName, len(markets))
+	for i, market := range markets {
+		names[i] = market.Exchange
+	}
+
+	appConfig := config.Default().App
+	derivativesStore := derivatives.NewStore()
+	registry := instruments.NewRegistry()
+	feed := websocket.NewFeed(sym, derivativesStore, registry, history.NewStore(appConfig.HistoryResolution, appConfig.HistoryRetention))
+	feed.SetMarkets(markets, unsupported)
+
+	rt := &symbolRuntime{symbol: sym, done: make(chan struct{}), exited: make(chan struct{})}
+
+	// Watch for crossed markets across the symbol's venues for as long as it runs
+	detector := arbitrage.NewDetector(feed.Books(), registry, buildArbitrageConfig(config.Default().Arbitrage))
+	wsServer.ForwardArbitrageEvents(sym, detector.Subscribe())
+	go logArbitrageEvents(sym, detector.Subscribe())
+	go detector.Run(rt.done)
+
+	wsServer.AddFeed(feed)
+
+	go func() {
+		startExchangesForSymbol(ctx, symbol, names, mapper, feed.Books(), derivativesStore, registry, rec, feed.PublishTrade, logInterval, rt.done)
+		close(rt.exited)
+	}()
+	return rt
+}
+
+func startExchangesForSymbol(ctx context.Context, symbol string, names []exchange.ExchangeName, mapper *symbols.Mapper, books *orderbook.Books, derivativesStore *derivatives.Store, registry *instruments.Registry, rec *recorder.Recorder, publishTrade func(*exchange.Trade), logInterval time.Duration, done chan struct{}) {
+	cfg := config.NewMultiExchange(buildExchangeConfigs(symbol, names))
 
 	var wg sync.WaitGroup
+	var obMutex sync.Mutex // Guards orderbooks, which only the stats log reads
 	orderbooks := make([]*orderbookWithName, 0, len(cfg.Exchanges))
 
 	// Create an orderbook for each exchange
@@ -142,37 +256,68 @@ func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap m
 		go func(exCfg config.ExchangeConfig) {
 			defer wg.Done()
 
-			log.Printf("[%s] Starting connection...", exCfg.Name)
+			venue := fmt.Sprintf("%s %s", exCfg.Name, exCfg.Symbol)
+			log.Printf("[%s] Starting connection...", venue)
 
 			// Create exchange-specific orderbook
 			ob := orderbook.New()
+			ob.SetDepthBands(cfg.App.DepthBands)
 
 			// Create exchange instance
 			ex, err := factory.NewExchange(factory.ExchangeConfig{
 				Name:   exCfg.Name,
-				Symbol: exCfg.Symbol,
+				Symbol: symbols.MustParse(exCfg.Symbol),
+				Mapper: mapper,
 			})
 			if err != nil {
-				log.Printf("[%s] Failed to create exchange: %v", exCfg.Name, err)
+				log.Printf("[%s] Failed to create exchange: %v", venue, err)
 				return
 			}
 
 			// Connect
 			if err := ex.Connect(ctx); err != nil {
-				log.Printf("[%s] Failed to connect: %v", exCfg.Name, err)
+				log.Printf("[%s] Failed to connect: %v", venue, err)
 				return
 			}
 			defer ex.Close()
 
+			// Load tick size, lot size and contract terms; the tick ladder falls back to fixed levels without them
+			if provider, ok := ex.(exchange.InstrumentProvider); ok {
+				inst, err := registry.Load(ctx, provider)
+				if err != nil {
+					log.Printf("[%s] Failed to load instrument: %v", venue, err)
+				} else {
+					log.Printf("[%s] Instrument %s: tick=%s lot=%s minNotional=%s", venue, inst.Symbol, inst.TickSize, inst.LotSize, inst.MinNotional)
+				}
+			}
+
+			// Accumulate executed volume for venues that stream trades, and forward them to clients
+			var volume *analytics.TradeVolume
+			if provider, ok := ex.(exchange.TradeProvider); ok {
+				volume = analytics.NewTradeVolume()
+				go func() {
+					for trade := range provider.Trades() {
+						if err := volume.Add(trade); err != nil {
+							log.Printf("[%s] Skipping trade: %v", venue, err)
+							continue
+						}
+						publishTrade(trade)
+					}
+				}()
+			}
+
 			// Get snapshot
 			snapshot, err := ex.GetSnapshot(ctx)
 			if err != nil {
-				log.Printf("[%s] Failed to get snapshot: %v", exCfg.Name, err)
+				log.Printf("[%s] Failed to get snapshot: %v", venue, err)
 				return
 			}
+			if rec != nil {
+				rec.RecordSnapshot(exCfg.Name, exCfg.Symbol, snapshot)
+			}
 
 			if err := ob.LoadSnapshot(snapshot); err != nil {
-				log.Printf("[%s] Failed to load snapshot: %v", exCfg.Name, err)
+				log.Printf("[%s] Failed to load snapshot: %v", venue, err)
 				return
 			}
 
@@ -181,6 +326,9 @@ func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap m
 			go func() {
 				defer close(updatesDone)
 				for update := range ex.Updates() {
+					if rec != nil {
+						rec.RecordUpdate(exCfg.Name, exCfg.Symbol, update)
+					}
 					ob.HandleDepthUpdate(update)
 				}
 			}()
@@ -194,44 +342,54 @@ func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap m
 					select {
 					case <-ticker.C:
 						ob.CheckAndReinitialize(func() (*exchange.Snapshot, error) {
-							return ex.GetSnapshot(ctx)
+							snapshot, err := ex.GetSnapshot(ctx)
+							if err == nil && rec != nil {
+								rec.RecordSnapshot(exCfg.Name, exCfg.Symbol, snapshot)
+							}
+							return snapshot, err
 						})
 					case <-updatesDone:
 						return
 					case <-done:
 						return
-					case <-interrupt:
-						return
 					}
 				}
 			}()
 
 			ob.ProcessBufferedEvents()
-			log.Printf("[%s] Orderbook initialized", exCfg.Name)
+			log.Printf("[%s] Orderbook initialized", venue)
+
+			// Poll funding, mark/index price and open interest for perpetuals
+			pollCtx, cancelPoll := context.WithCancel(ctx)
+			defer cancelPoll()
+			if provider, ok := ex.(exchange.DerivativesProvider); ok {
+				go derivativesStore.Poll(pollCtx, provider, exCfg.Name, cfg.App.DerivativesPollInterval)
+			}
 
 			// Add orderbook to shared collections
 			obMutex.Lock()
 			orderbooks = append(orderbooks, &orderbookWithName{
-				name: string(exCfg.Name),
-				ob:   ob,
+				name:   string(exCfg.Name),
+				ob:     ob,
+				volume: volume,
 			})
-			orderbooksMap[string(exCfg.Name)] = ob
 			obMutex.Unlock()
+			books.Set(string(exCfg.Name), ob)
 
 			// Wait for shutdown
 			select {
 			case <-updatesDone:
-				log.Printf("[%s] Connection closed", exCfg.Name)
+				log.Printf("[%s] Connection closed", venue)
 			case <-done:
-				log.Printf("[%s] Shutting down...", exCfg.Name)
-			case <-interrupt:
-				log.Printf("[%s] Shutting down...", exCfg.Name)
+				log.Printf("[%s] Shutting down...", venue)
 			}
 
-			// Remove from map on shutdown
-			obMutex.Lock()
-			delete(orderbooksMap, string(exCfg.Name))
-			obMutex.Unlock()
+			// Remove from the feed on shutdown
+			books.Delete(string(exCfg.Name))
+
+			cancelPoll()
+			derivativesStore.Delete(string(exCfg.Name))
+			registry.Delete(string(exCfg.Name))
 		}(exConfig)
 	}
 
@@ -244,12 +402,10 @@ func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap m
 			select {
 			case <-ticker.C:
 				obMutex.Lock()
-				printCombinedStats(orderbooks)
+				printCombinedStats(symbol, orderbooks, derivativesStore)
 				obMutex.Unlock()
 			case <-done:
 				return
-			case <-interrupt:
-				return
 			}
 		}
 	}()
@@ -257,8 +413,88 @@ func startExchangesForSymbol(ctx context.Context, symbol string, orderbooksMap m
 	wg.Wait()
 }
 
-func buildExchangeConfigs(symbol string) []config.ExchangeConfig {
-	names := getExchangeNames()
+// runReplay serves recorded books through the websocket server as if they were live
+func runReplay(dir, symbol string, speed float64, logInterval time.Duration, interrupt chan os.Signal) {
+	reader, err := replay.Open(dir, symbol)
+	if err != nil {
+		log.Fatalf("Failed to open recordings: %v", err)
+	}
+	defer reader.Close()
+
+	log.Printf("Replaying %s from %s at %vx speed: %v", symbol, dir, speed, reader.Exchanges())
+
+	sym, err := symbols.Parse(symbol)
+	if err != nil {
+		log.Fatalf("Invalid -symbol: %v", err)
+	}
+
+	player := replay.NewPlayer(reader, speed)
+	for _, ob := range player.Books() {
+		ob.SetDepthBands(config.Default().App.DepthBands)
+	}
+	derivativesStore := derivatives.NewStore()
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8086"
+	}
+
+	// Recorded venues have no resolved markets, so their prices are shown as recorded.
+	// Without a symbol request channel, clients cannot add or remove symbols.
+	appConfig := config.Default().App
+	statsHistory := history.NewStore(appConfig.HistoryResolution, appConfig.HistoryRetention)
+	wsServer := websocket.NewServer(port, nil)
+	wsServer.SetHistoryBackfill(appConfig.HistoryBackfill)
+	feed := websocket.NewFeed(sym, derivativesStore, instruments.NewRegistry(), statsHistory)
+	for name, ob := range player.Books() {
+		feed.Books().Set(name, ob)
+	}
+	wsServer.AddFeed(feed)
+	go func() {
+		if err := wsServer.Start(); err != nil {
+			log.Fatalf("WebSocket server error: %v", err)
+		}
+	}()
+
+	orderbooks := make([]*orderbookWithName, 0, len(reader.Exchanges()))
+	for _, name := range reader.Exchanges() {
+		orderbooks = append(orderbooks, &orderbookWithName{name: string(name), ob: player.Books()[string(name)]})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replayDone := make(chan error, 1)
+	go func() {
+		replayDone <- player.Run(ctx)
+	}()
+
+	ticker := time.NewTicker(logInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			log.Printf("Replay position: %s", player.Position().UTC().Format(time.RFC3339))
+			printCombinedStats(symbol, orderbooks, derivativesStore)
+		case err := <-replayDone:
+			if err != nil {
+				log.Printf("Replay stopped: %v", err)
+			} else {
+				log.Println("Replay finished, serving final books until interrupted")
+			}
+			replayDone = nil
+		case <-interrupt:
+			log.Println("Interrupt received, shutting down...")
+			cancel()
+			if replayDone != nil {
+				<-replayDone
+			}
+			return
+		}
+	}
+}
+
+func buildExchangeConfigs(symbol string, names []exchange.ExchangeName) []config.ExchangeConfig {
 	configs := make([]config.ExchangeConfig, len(names))
 	for i, name := range names {
 		configs[i] = config.ExchangeConfig{
@@ -269,12 +505,43 @@ func buildExchangeConfigs(symbol string) []config.ExchangeConfig {
 	return configs
 }
 
-func printCombinedStats(orderbooks []*orderbookWithName) {
+func buildArbitrageConfig(cfg config.ArbitrageConfig) arbitrage.Config {
+	fees := make(map[string]float64, len(cfg.TakerFeeBps))
+	for name, fee := range cfg.TakerFeeBps {
+		fees[string(name)] = fee
+	}
+	return arbitrage.Config{
+		CheckInterval:      cfg.CheckInterval,
+		DefaultTakerFeeBps: cfg.DefaultTakerFeeBps,
+		TakerFeeBps:        fees,
+	}
+}
+
+func logArbitrageEvents(symbol symbols.Symbol, events <-chan arbitrage.Event) {
+	for event := range events {
+		opp := event.Opportunity
+		switch event.Type {
+		case arbitrage.EventOpened:
+			log.Printf("%s[ARB OPEN]%s %s buy %s @ %s → sell %s @ %s │ size %s │ net %s bps (gross %s)",
+				colorGreen, colorReset, symbol,
+				opp.BuyExchange, opp.BuyPrice.String(), opp.SellExchange, opp.SellPrice.String(),
+				opp.Size.String(), opp.NetBps.StringFixed(2), opp.GrossBps.StringFixed(2))
+		case arbitrage.EventClosed:
+			log.Printf("%s[ARB CLOSE]%s %s %s → %s lasted %v │ max size %s │ max net %s bps",
+				colorYellow, colorReset, symbol,
+				opp.BuyExchange, opp.SellExchange, opp.Duration.Round(time.Millisecond),
+				opp.MaxSize.String(), opp.MaxNetBps.StringFixed(2))
+		}
+	}
+}
+
+func printCombinedStats(symbol string, orderbooks []*orderbookWithName, derivativesStore *derivatives.Store) {
 	if len(orderbooks) == 0 {
 		return
 	}
 
 	fmt.Println()
+	fmt.Printf("%s── %s ──%s\n", colorBold, symbol, colorReset)
 
 	for i, obn := range orderbooks {
 		if !obn.ob.IsInitialized() {
@@ -293,25 +560,31 @@ func printCombinedStats(orderbooks []*orderbookWithName) {
 			colorGreen, stats.BestBid.StringFixed(2), colorReset,
 			colorRed, stats.BestAsk.StringFixed(2), colorReset)
 
-		// Print depth metrics
-		fmt.Printf("  DEPTH 0.5%% Bids: %s%9s%s │ Asks: %s%9s%s │ Δ: %s%10s%s\n",
-			colorGreen, stats.BidLiquidity05Pct.StringFixed(2), colorReset,
-			colorRed, stats.AskLiquidity05Pct.StringFixed(2), colorReset,
-			getDeltaColor(stats.DeltaLiquidity05Pct), stats.DeltaLiquidity05Pct.StringFixed(2), colorReset)
+		// Print depth metrics, one line per band
+		for _, depth := range stats.Depth {
+			fmt.Printf("  DEPTH %-6s Bids: %s%9s%s │ Asks: %s%9s%s │ Δ: %s%10s%s │ Notional: %s%8s%s/%s%-8s%s\n",
+				depth.Band,
+				colorGreen, depth.BidQty.StringFixed(2), colorReset,
+				colorRed, depth.AskQty.StringFixed(2), colorReset,
+				getDeltaColor(depth.DeltaQty), depth.DeltaQty.StringFixed(2), colorReset,
+				colorGreen, formatNotional(depth.BidNotional), colorReset,
+				colorRed, formatNotional(depth.AskNotional), colorReset)
+		}
 
-		fmt.Printf("  DEPTH 2%%:  Bids: %s%9s%s │ Asks: %s%9s%s │ Δ: %s%10s%s\n",
-			colorGreen, stats.BidLiquidity2Pct.StringFixed(2), colorReset,
-			colorRed, stats.AskLiquidity2Pct.StringFixed(2), colorReset,
-			getDeltaColor(stats.DeltaLiquidity2Pct), stats.DeltaLiquidity2Pct.StringFixed(2), colorReset)
+		fmt.Printf("  TOTAL        Bids: %s%9s%s │ Asks: %s%9s%s │ Δ: %s%10s%s │ Notional: %s%8s%s/%s%-8s%s\n",
+			colorGreen, stats.TotalBidsQty.StringFixed(2), colorReset,
+			colorRed, stats.TotalAsksQty.StringFixed(2), colorReset,
+			getDeltaColor(stats.TotalDelta), stats.TotalDelta.StringFixed(2), colorReset,
+			colorGreen, formatNotional(stats.TotalBidsNotional), colorReset,
+			colorRed, formatNotional(stats.TotalAsksNotional), colorReset)
 
-		fmt.Printf("  DEPTH 10%%  Bids: %s%9s%s │ Asks: %s%9s%s │ Δ: %s%10s%s\n",
-			colorGreen, stats.BidLiquidity10Pct.StringFixed(2), colorReset,
-			colorRed, stats.AskLiquidity10Pct.StringFixed(2), colorReset,
-			getDeltaColor(stats.DeltaLiquidity10Pct), stats.DeltaLiquidity10Pct.StringFixed(2), colorReset)
+		if obn.volume != nil {
+			printTradeVolume(obn.volume.Reset())
+		}
 
-		fmt.Printf("  TOTAL QTY: Bids: %s%9s%s │ Asks: %s%9s%s\n",
-			colorGreen, stats.TotalBidsQty.StringFixed(2), colorReset,
-			colorRed, stats.TotalAsksQty.StringFixed(2), colorReset)
+		if info, ok := derivativesStore.Get(obn.name); ok {
+			printDerivativesInfo(info)
+		}
 
 		// Print separator between exchanges (but not after the last one)
 		if i < len(orderbooks)-1 {
@@ -320,6 +593,52 @@ func printCombinedStats(orderbooks []*orderbookWithName) {
 	}
 }
 
+// formatNotional shortens a quote amount to thousands, millions or billions (e.g., 12.34M)
+func formatNotional(amount decimal.Decimal) string {
+	units := []struct {
+		suffix string
+		size   decimal.Decimal
+	}{
+		{"B", decimal.NewFromInt(1_000_000_000)},
+		{"M", decimal.NewFromInt(1_000_000)},
+		{"K", decimal.NewFromInt(1_000)},
+	}
+	for _, unit := range units {
+		if amount.Abs().GreaterThanOrEqual(unit.size) {
+			return amount.Div(unit.size).StringFixed(2) + unit.suffix
+		}
+	}
+	return amount.StringFixed(2)
+}
+
+// printTradeVolume prints taker volume since the previous stats print
+func printTradeVolume(summary analytics.VolumeSummary) {
+	fmt.Printf("  TRADES:    Buys: %s%9s%s │ Sells: %s%9s%s │ Δ: %s%10s%s │ VWAP: %10s │ Count: %d\n",
+		colorGreen, summary.BuyQty.StringFixed(2), colorReset,
+		colorRed, summary.SellQty.StringFixed(2), colorReset,
+		getDeltaColor(summary.TakerDeltaQty), summary.TakerDeltaQty.StringFixed(2), colorReset,
+		summary.VWAP.StringFixed(2), summary.Trades)
+}
+
+// printDerivativesInfo prints the funding and open interest line for a perpetual
+func printDerivativesInfo(info exchange.DerivativesInfo) {
+	fundingRate, _ := decimal.NewFromString(info.FundingRate)
+	markPrice, _ := decimal.NewFromString(info.MarkPrice)
+	indexPrice, _ := decimal.NewFromString(info.IndexPrice)
+	openInterest, _ := decimal.NewFromString(info.OpenInterest)
+
+	fundingPct := fundingRate.Mul(decimal.NewFromInt(100))
+	nextFunding := time.Until(info.NextFundingTime).Round(time.Minute)
+	if nextFunding < 0 {
+		nextFunding = 0
+	}
+
+	fmt.Printf("  FUNDING:   Rate: %s%8s%%%s │ Next: %8v │ Mark: %10s │ Index: %10s │ OI: %12s\n",
+		getDeltaColor(fundingPct), fundingPct.StringFixed(4), colorReset,
+		nextFunding,
+		markPrice.StringFixed(2), indexPrice.StringFixed(2), openInterest.StringFixed(2))
+}
+
 func getDeltaColor(delta decimal.Decimal) string {
 	if delta.GreaterThan(decimal.Zero) {
 		return colorGreen
@@ -19,7 +19,7 @@ import { Tooltip, TooltipContent, TooltipTrigger } from './components/ui/tooltip
 import { ToggleGroup, ToggleGroupItem } from './components/ui/toggle-group';
 import { Moon, Sun, Layers } from 'lucide-react';
 import { TICK_LEVELS, CHART_CONFIG, POPULAR_SYMBOLS } from './constants';
-import { filterExchangesByMarket, sortExchangesByGroup } from './utils/calculations';
+import { filterExchangesByMarket, sortExchangesByGroup, getDepthBands } from './utils/calculations';
 import type { MarketFilter } from './types';
 
 function App() {
@@ -33,7 +33,7 @@ function App() {
     : 'ws://localhost:8086/ws';
 
   const { orderbooks, stats, isConnected, currentSymbol, isSwitchingSymbol, setTickLevel, setSymbol } = useWebSocket(wsUrl);
-  const { chartData05Pct, chartData2Pct, chartData10Pct, chartDataTotal } = useChartData(stats, marketFilter);
+  const { depthCharts, chartDataTotal } = useChartData(stats, marketFilter);
 
   // Filter and sort orderbooks based on market filter
   const filteredOrderbooks = useMemo(() => {
@@ -48,7 +48,9 @@ function App() {
     return Object.fromEntries(filteredOrderbooks);
   }, [filteredOrderbooks]);
 
-  const aggregated = useAggregatedOrderbook(filteredOrderbooksData);
+  // Aggregate over the same depth bands the server measures
+  const depthBands = useMemo(() => getDepthBands(stats), [stats]);
+  const aggregated = useAggregatedOrderbook(filteredOrderbooksData, depthBands);
 
   return (
     <div className="min-h-screen bg-background text-foreground">
@@ -182,24 +184,17 @@ function App() {
             </div>
           </section>
 
-          {chartData05Pct.length > 0 && (
+          {chartDataTotal.length > 0 && (
             <section>
               <div className="grid grid-cols-1 lg:grid-cols-2 gap-4">
-                <LiquidityChart
-                  title="Liquidity at 0.5% Depth"
-                  data={chartData05Pct}
-                  config={CHART_CONFIG}
-                />
-                <LiquidityChart
-                  title="Liquidity at 2% Depth"
-                  data={chartData2Pct}
-                  config={CHART_CONFIG}
-                />
-                <LiquidityChart
-                  title="Liquidity at 10% Depth"
-                  data={chartData10Pct}
-                  config={CHART_CONFIG}
-                />
+                {depthCharts.map(({ band, data }) => (
+                  <LiquidityChart
+                    key={band}
+                    title={`Liquidity at ${band} Depth`}
+                    data={data}
+                    config={CHART_CONFIG}
+                  />
+                ))}
                 <LiquidityChart
                   title="Total Liquidity"
                   data={chartDataTotal}
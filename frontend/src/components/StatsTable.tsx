@@ -16,8 +16,8 @@ import {
   TableRow,
 } from '@/components/ui/table'
 import { ExchangeBadge } from '@/components/ExchangeBadge'
-import type { StatsData, MarketFilter } from '@/types'
-import { filterExchangesByMarket, sortExchangesByGroup } from '@/utils/calculations'
+import type { StatsData, MarketFilter, DepthBand } from '@/types'
+import { filterExchangesByMarket, sortExchangesByGroup, getDepthBands } from '@/utils/calculations'
 
 type ExchangeStats = {
   exchange: string
@@ -25,21 +25,54 @@ type ExchangeStats = {
   bestAsk: string
   midPrice: string
   spread: string
-  bidLiquidity05Pct: string
-  askLiquidity05Pct: string
-  deltaLiquidity05Pct: string
-  bidLiquidity2Pct: string
-  askLiquidity2Pct: string
-  deltaLiquidity2Pct: string
-  bidLiquidity10Pct: string
-  askLiquidity10Pct: string
-  deltaLiquidity10Pct: string
+  depth: DepthBand[]
   totalBidsQty: string
   totalAsksQty: string
   totalDelta: string
 }
 
-const createColumns = (): ColumnDef<ExchangeStats>[] => [
+// Bid, ask and delta quantity columns for one depth band; exchanges without the band show 0
+const createBandColumns = (band: string): ColumnDef<ExchangeStats>[] => {
+  const quantity = (stat: ExchangeStats, key: 'bidQty' | 'askQty' | 'deltaQty') =>
+    parseFloat(stat.depth.find((d) => d.band === band)?.[key] ?? '0')
+
+  return [
+    {
+      id: `bidQty-${band}`,
+      accessorFn: (stat) => quantity(stat, 'bidQty'),
+      header: `Bid Qty ${band}`,
+      cell: ({ getValue }) => (
+        <div className="font-mono text-xs">{getValue<number>().toFixed(0)}</div>
+      ),
+      sortingFn: 'basic',
+    },
+    {
+      id: `askQty-${band}`,
+      accessorFn: (stat) => quantity(stat, 'askQty'),
+      header: `Ask Qty ${band}`,
+      cell: ({ getValue }) => (
+        <div className="font-mono text-xs">{getValue<number>().toFixed(0)}</div>
+      ),
+      sortingFn: 'basic',
+    },
+    {
+      id: `deltaQty-${band}`,
+      accessorFn: (stat) => quantity(stat, 'deltaQty'),
+      header: `Δ Liq ${band}`,
+      cell: ({ getValue }) => {
+        const value = getValue<number>()
+        return (
+          <div className={`font-mono text-xs ${value > 0 ? 'text-green-500' : value < 0 ? 'text-red-500' : 'text-yellow-500'}`}>
+            {value.toFixed(0)}
+          </div>
+        )
+      },
+      sortingFn: 'basic',
+    },
+  ]
+}
+
+const createColumns = (bands: string[]): ColumnDef<ExchangeStats>[] => [
   {
     accessorKey: 'exchange',
     header: 'Exchange',
@@ -89,129 +122,7 @@ const createColumns = (): ColumnDef<ExchangeStats>[] => [
       return a - b
     },
   },
-  {
-    accessorKey: 'bidLiquidity05Pct',
-    header: 'Bid Qty 0.5%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('bidLiquidity05Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('bidLiquidity05Pct'))
-      const b = parseFloat(rowB.getValue('bidLiquidity05Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'askLiquidity05Pct',
-    header: 'Ask Qty 0.5%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('askLiquidity05Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('askLiquidity05Pct'))
-      const b = parseFloat(rowB.getValue('askLiquidity05Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'deltaLiquidity05Pct',
-    header: 'Δ Liq 0.5%',
-    cell: ({ row }) => {
-      const value = parseFloat(row.getValue('deltaLiquidity05Pct'))
-      return (
-        <div className={`font-mono text-xs ${value > 0 ? 'text-green-500' : value < 0 ? 'text-red-500' : 'text-yellow-500'}`}>
-          {value.toFixed(0)}
-        </div>
-      )
-    },
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('deltaLiquidity05Pct'))
-      const b = parseFloat(rowB.getValue('deltaLiquidity05Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'bidLiquidity2Pct',
-    header: 'Bid Qty 2%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('bidLiquidity2Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('bidLiquidity2Pct'))
-      const b = parseFloat(rowB.getValue('bidLiquidity2Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'askLiquidity2Pct',
-    header: 'Ask Qty 2%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('askLiquidity2Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('askLiquidity2Pct'))
-      const b = parseFloat(rowB.getValue('askLiquidity2Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'deltaLiquidity2Pct',
-    header: 'Δ Liq 2%',
-    cell: ({ row }) => {
-      const value = parseFloat(row.getValue('deltaLiquidity2Pct'))
-      return (
-        <div className={`font-mono text-xs ${value > 0 ? 'text-green-500' : value < 0 ? 'text-red-500' : 'text-yellow-500'}`}>
-          {value.toFixed(0)}
-        </div>
-      )
-    },
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('deltaLiquidity2Pct'))
-      const b = parseFloat(rowB.getValue('deltaLiquidity2Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'bidLiquidity10Pct',
-    header: 'Bid Qty 10%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('bidLiquidity10Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('bidLiquidity10Pct'))
-      const b = parseFloat(rowB.getValue('bidLiquidity10Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'askLiquidity10Pct',
-    header: 'Ask Qty 10%',
-    cell: ({ row }) => (
-      <div className="font-mono text-xs">{parseFloat(row.getValue('askLiquidity10Pct')).toFixed(0)}</div>
-    ),
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('askLiquidity10Pct'))
-      const b = parseFloat(rowB.getValue('askLiquidity10Pct'))
-      return a - b
-    },
-  },
-  {
-    accessorKey: 'deltaLiquidity10Pct',
-    header: 'Δ Liq 10%',
-    cell: ({ row }) => {
-      const value = parseFloat(row.getValue('deltaLiquidity10Pct'))
-      return (
-        <div className={`font-mono text-xs ${value > 0 ? 'text-green-500' : value < 0 ? 'text-red-500' : 'text-yellow-500'}`}>
-          {value.toFixed(0)}
-        </div>
-      )
-    },
-    sortingFn: (rowA, rowB) => {
-      const a = parseFloat(rowA.getValue('deltaLiquidity10Pct'))
-      const b = parseFloat(rowB.getValue('deltaLiquidity10Pct'))
-      return a - b
-    },
-  },
+  ...bands.flatMap((band) => createBandColumns(band)),
   {
     accessorKey: 'totalBidsQty',
     header: 'Total Bids Qty',
@@ -278,7 +189,9 @@ export function StatsTable({
     }));
   }, [stats, filter])
 
-  const columns = useMemo(() => createColumns(), [])
+  // Rebuild the columns only when the configured bands change, not on every stats tick
+  const bandKey = getDepthBands(stats).map(({ band }) => band).join(',')
+  const columns = useMemo(() => createColumns(bandKey ? bandKey.split(',') : []), [bandKey])
 
   const table = useReactTable({
     data,
@@ -1,7 +1,7 @@
 import { useMemo } from 'react';
-import type { OrderbookData, OrderbookLevel } from '@/types';
+import type { OrderbookData, OrderbookLevel, DepthBand } from '@/types';
 
-export function useAggregatedOrderbook(orderbooks: OrderbookData) {
+export function useAggregatedOrderbook(orderbooks: OrderbookData, bands: Pick<DepthBand, 'band' | 'bps'>[]) {
   return useMemo(() => {
     const priceMap = new Map<string, { bidQty: number; askQty: number }>();
 
@@ -61,30 +61,40 @@ export function useAggregatedOrderbook(orderbooks: OrderbookData) {
     const midPrice = bestBid && bestAsk ? (bestBid + bestAsk) / 2 : 0;
     const spread = bestBid && bestAsk ? bestAsk - bestBid : 0;
 
-    // Calculate liquidity at different depth percentages
-    const calculateLiquidity = (levels: OrderbookLevel[], referencePrice: number, percentage: number, isBid: boolean) => {
+    // Calculate liquidity within each configured depth band
+    const calculateLiquidity = (levels: OrderbookLevel[], referencePrice: number, bps: number, isBid: boolean) => {
       const threshold = isBid
-        ? referencePrice * (1 - percentage / 100)
-        : referencePrice * (1 + percentage / 100);
+        ? referencePrice * (1 - bps / 10000)
+        : referencePrice * (1 + bps / 10000);
 
-      let liquidity = 0;
+      let quantity = 0;
+      let notional = 0;
       for (const level of levels) {
         const price = parseFloat(level.price);
         if (isBid ? price >= threshold : price <= threshold) {
-          liquidity += parseFloat(level.quantity);
+          quantity += parseFloat(level.quantity);
+          notional += price * parseFloat(level.quantity);
         } else {
           break;
         }
       }
-      return liquidity;
+      return { quantity, notional };
     };
 
-    const bidLiquidity05Pct = calculateLiquidity(bids, midPrice, 0.5, true);
-    const askLiquidity05Pct = calculateLiquidity(asks, midPrice, 0.5, false);
-    const bidLiquidity2Pct = calculateLiquidity(bids, midPrice, 2, true);
-    const askLiquidity2Pct = calculateLiquidity(asks, midPrice, 2, false);
-    const bidLiquidity10Pct = calculateLiquidity(bids, midPrice, 10, true);
-    const askLiquidity10Pct = calculateLiquidity(asks, midPrice, 10, false);
+    const depth: DepthBand[] = bands.map(({ band, bps }) => {
+      const bid = calculateLiquidity(bids, midPrice, bps, true);
+      const ask = calculateLiquidity(asks, midPrice, bps, false);
+      return {
+        band,
+        bps,
+        bidQty: bid.quantity.toString(),
+        askQty: ask.quantity.toString(),
+        deltaQty: (bid.quantity - ask.quantity).toString(),
+        bidNotional: bid.notional.toString(),
+        askNotional: ask.notional.toString(),
+        deltaNotional: (bid.notional - ask.notional).toString(),
+      };
+    });
 
     return {
       bids,
@@ -94,19 +104,11 @@ export function useAggregatedOrderbook(orderbooks: OrderbookData) {
         bestAsk: bestAsk.toString(),
         midPrice: midPrice.toString(),
         spread: spread.toString(),
-        bidLiquidity05Pct: bidLiquidity05Pct.toString(),
-        askLiquidity05Pct: askLiquidity05Pct.toString(),
-        deltaLiquidity05Pct: (bidLiquidity05Pct - askLiquidity05Pct).toString(),
-        bidLiquidity2Pct: bidLiquidity2Pct.toString(),
-        askLiquidity2Pct: askLiquidity2Pct.toString(),
-        deltaLiquidity2Pct: (bidLiquidity2Pct - askLiquidity2Pct).toString(),
-        bidLiquidity10Pct: bidLiquidity10Pct.toString(),
-        askLiquidity10Pct: askLiquidity10Pct.toString(),
-        deltaLiquidity10Pct: (bidLiquidity10Pct - askLiquidity10Pct).toString(),
+        depth,
         totalBidsQty: bidCumulative.toString(),
         totalAsksQty: askCumulative.toString(),
         totalDelta: (bidCumulative - askCumulative).toString(),
       },
     };
-  }, [orderbooks]);
+  }, [orderbooks, bands]);
 }
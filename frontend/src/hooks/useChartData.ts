@@ -1,60 +1,46 @@
 import { useMemo } from 'react';
-import type { StatsData, MarketFilter, ChartDataPoint } from '@/types';
-import { filterExchangesByMarket, sortExchangesByGroup } from '@/utils/calculations';
+import type { StatsData, MarketFilter, ChartDataPoint, DepthBand } from '@/types';
+import { filterExchangesByMarket, sortExchangesByGroup, getDepthBands } from '@/utils/calculations';
+
+export type DepthChart = {
+  band: string;
+  data: ChartDataPoint[];
+};
 
 /**
  * Hook to transform stats data into chart-ready format
  * Memoized for performance
  */
 export function useChartData(stats: StatsData, filter: MarketFilter) {
-  const chartData05Pct = useMemo((): ChartDataPoint[] => {
-    const filtered = Object.entries(stats)
-      .filter(([exchange]) => filterExchangesByMarket(exchange, filter));
-    const sorted = sortExchangesByGroup(filtered);
-    return sorted.map(([exchange, stat]) => ({
-      exchange,
-      bid: parseFloat(stat.bidLiquidity05Pct),
-      ask: parseFloat(stat.askLiquidity05Pct),
-    }));
-  }, [stats, filter]);
-
-  const chartData2Pct = useMemo((): ChartDataPoint[] => {
+  const sortedStats = useMemo(() => {
     const filtered = Object.entries(stats)
       .filter(([exchange]) => filterExchangesByMarket(exchange, filter));
-    const sorted = sortExchangesByGroup(filtered);
-    return sorted.map(([exchange, stat]) => ({
-      exchange,
-      bid: parseFloat(stat.bidLiquidity2Pct),
-      ask: parseFloat(stat.askLiquidity2Pct),
-    }));
+    return sortExchangesByGroup(filtered);
   }, [stats, filter]);
 
-  const chartData10Pct = useMemo((): ChartDataPoint[] => {
-    const filtered = Object.entries(stats)
-      .filter(([exchange]) => filterExchangesByMarket(exchange, filter));
-    const sorted = sortExchangesByGroup(filtered);
-    return sorted.map(([exchange, stat]) => ({
-      exchange,
-      bid: parseFloat(stat.bidLiquidity10Pct),
-      ask: parseFloat(stat.askLiquidity10Pct),
+  // One chart per configured depth band, narrowest first
+  const depthCharts = useMemo((): DepthChart[] => {
+    return getDepthBands(stats).map(({ band }) => ({
+      band,
+      data: sortedStats.flatMap(([exchange, stat]) => {
+        const depth = stat.depth.find((d: DepthBand) => d.band === band);
+        return depth
+          ? [{ exchange, bid: parseFloat(depth.bidQty), ask: parseFloat(depth.askQty) }]
+          : [];
+      }),
     }));
-  }, [stats, filter]);
+  }, [stats, sortedStats]);
 
   const chartDataTotal = useMemo((): ChartDataPoint[] => {
-    const filtered = Object.entries(stats)
-      .filter(([exchange]) => filterExchangesByMarket(exchange, filter));
-    const sorted = sortExchangesByGroup(filtered);
-    return sorted.map(([exchange, stat]) => ({
+    return sortedStats.map(([exchange, stat]) => ({
       exchange,
       bid: parseFloat(stat.totalBidsQty),
       ask: parseFloat(stat.totalAsksQty),
     }));
-  }, [stats, filter]);
+  }, [sortedStats]);
 
   return {
-    chartData05Pct,
-    chartData2Pct,
-    chartData10Pct,
+    depthCharts,
     chartDataTotal,
   };
-}
\ No newline at end of file
+}
@@ -70,15 +70,7 @@ export function useWebSocket(url: string) {
               bestAsk: message.bestAsk,
               midPrice: message.midPrice,
               spread: message.spread,
-              bidLiquidity05Pct: message.bidLiquidity05Pct,
-              askLiquidity05Pct: message.askLiquidity05Pct,
-              deltaLiquidity05Pct: message.deltaLiquidity05Pct,
-              bidLiquidity2Pct: message.bidLiquidity2Pct,
-              askLiquidity2Pct: message.askLiquidity2Pct,
-              deltaLiquidity2Pct: message.deltaLiquidity2Pct,
-              bidLiquidity10Pct: message.bidLiquidity10Pct,
-              askLiquidity10Pct: message.askLiquidity10Pct,
-              deltaLiquidity10Pct: message.deltaLiquidity10Pct,
+              depth: message.depth ?? [],
               totalBidsQty: message.totalBidsQty,
               totalAsksQty: message.totalAsksQty,
               totalDelta: message.totalDelta,
@@ -14,21 +14,25 @@ export type StatsMessage = {
   bestAsk: string;
   midPrice: string;
   spread: string;
-  bidLiquidity05Pct: string;
-  askLiquidity05Pct: string;
-  deltaLiquidity05Pct: string;
-  bidLiquidity2Pct: string;
-  askLiquidity2Pct: string;
-  deltaLiquidity2Pct: string;
-  bidLiquidity10Pct: string;
-  askLiquidity10Pct: string;
-  deltaLiquidity10Pct: string;
+  depth: DepthBand[]; // Narrowest band first
   totalBidsQty: string;
   totalAsksQty: string;
   totalDelta: string;
   timestamp: number;
 };
 
+// Liquidity within one configured distance from the mid price
+export type DepthBand = {
+  band: string; // e.g. "25bps" or "2%"
+  bps: number;
+  bidQty: string;
+  askQty: string;
+  deltaQty: string;
+  bidNotional: string;
+  askNotional: string;
+  deltaNotional: string;
+};
+
 export type WebSocketMessage = OrderbookMessage | StatsMessage;
 
 // Data structures
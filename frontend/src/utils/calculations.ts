@@ -1,4 +1,4 @@
-import type { StatsData, MarketFilter } from '@/types';
+import type { StatsData, MarketFilter, DepthBand } from '@/types';
 
 /**
  * Filters exchanges based on market type
@@ -33,6 +33,17 @@ export function calculateReferenceMidPrice(
     : 0;
 }
 
+/**
+ * Lists the depth bands the server measures, narrowest first
+ */
+export function getDepthBands(stats: StatsData): Pick<DepthBand, 'band' | 'bps'>[] {
+  const bands = new Map<string, number>();
+  Object.values(stats).forEach((stat) => {
+    stat.depth?.forEach(({ band, bps }) => bands.set(band, bps));
+  });
+  return Array.from(bands, ([band, bps]) => ({ band, bps })).sort((a, b) => a.bps - b.bps);
+}
+
 /**
  * Calculates maximum cumulative value for depth visualization
  */
@@ -97,6 +97,48 @@ func (a *Aggregator) AggregateAsks(levels []types.PriceLevel) []types.PriceLevel
 	return aggregated
 }
 
+// LevelWalker iterates price levels best price first until the callback returns false
+type LevelWalker func(fn func(level types.PriceLevel) bool)
+
+// AggregateSortedBids aggregates bids from a best-first walk and stops after depth buckets.
+// The result is sorted by price descending, so callers don't need to sort or truncate.
+func (a *Aggregator) AggregateSortedBids(walk LevelWalker, depth int) []types.PriceLevel {
+	return a.aggregateSorted(walk, depth, a.roundToTickBid)
+}
+
+// AggregateSortedAsks aggregates asks from a best-first walk and stops after depth buckets.
+// The result is sorted by price ascending, so callers don't need to sort or truncate.
+func (a *Aggregator) AggregateSortedAsks(walk LevelWalker, depth int) []types.PriceLevel {
+	return a.aggregateSorted(walk, depth, a.roundToTickAsk)
+}
+
+// aggregateSorted merges consecutive levels that round to the same tick bucket
+func (a *Aggregator) aggregateSorted(walk LevelWalker, depth int, round func(decimal.Decimal) decimal.Decimal) []types.PriceLevel {
+	aggregated := make([]types.PriceLevel, 0, depth)
+
+	walk(func(level types.PriceLevel) bool {
+		roundedPrice := round(level.Price)
+
+		// Sorted input means equal buckets are always adjacent
+		if last := len(aggregated) - 1; last >= 0 && aggregated[last].Price.Equal(roundedPrice) {
+			aggregated[last].Quantity = aggregated[last].Quantity.Add(level.Quantity)
+			return true
+		}
+
+		if len(aggregated) == depth {
+			return false
+		}
+
+		aggregated = append(aggregated, types.PriceLevel{
+			Price:    roundedPrice,
+			Quantity: level.Quantity,
+		})
+		return true
+	})
+
+	return aggregated
+}
+
 // roundToTickBid rounds a bid price DOWN to maintain proper spread
 func (a *Aggregator) roundToTickBid(price decimal.Decimal) decimal.Decimal {
 	tickSize := decimal.NewFromFloat(float64(a.currentTick))
@@ -123,6 +165,44 @@ func (a *Aggregator) roundToTickAsk(price decimal.Decimal) decimal.Decimal {
 	return ceiled.Mul(tickSize)
 }
 
+// Tick level ladders span from about 0.1 bps to 1% of the price
+var (
+	minTickFraction = decimal.New(1, -5)
+	maxTickFraction = decimal.New(1, -2)
+	tickMultipliers = []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(2), decimal.NewFromInt(5)}
+)
+
+// TickLevels builds the aggregation levels for an instrument: multiples of its tick size in
+// 1-2-5 steps, from about 0.1 bps to 1% of refPrice. Unlike types.AvailableTickLevels, the
+// ladder fits assets priced in fractions of a cent as well as in the thousands.
+func TickLevels(tickSize, refPrice decimal.Decimal) []types.TickLevel {
+	if !tickSize.IsPositive() {
+		return types.AvailableTickLevels
+	}
+
+	low := refPrice.Mul(minTickFraction)
+	high := refPrice.Mul(maxTickFraction)
+
+	var levels []types.TickLevel
+	for decade := tickSize; decade.LessThanOrEqual(high); decade = decade.Shift(1) {
+		for _, multiplier := range tickMultipliers {
+			step := decade.Mul(multiplier)
+			if step.GreaterThan(high) {
+				break
+			}
+			if step.GreaterThanOrEqual(low) {
+				levels = append(levels, types.TickLevel(step.InexactFloat64()))
+			}
+		}
+	}
+
+	// The venue's own tick is always available, even when it is coarse for the price
+	if len(levels) == 0 {
+		levels = append(levels, types.TickLevel(tickSize.InexactFloat64()))
+	}
+	return levels
+}
+
 // FilterLevels filters price levels based on best ask price to remove outliers
 func FilterLevels(levels []types.PriceLevel, bestAsk decimal.Decimal, isBid bool) []types.PriceLevel {
 	if bestAsk.IsZero() {
@@ -244,8 +244,112 @@ func TestFilterLevels(t *testing.T) {
 	}
 }
 
+// walkLevels returns a LevelWalker over a pre-sorted slice
+func walkLevels(levels []types.PriceLevel) LevelWalker {
+	return func(fn func(level types.PriceLevel) bool) {
+		for _, level := range levels {
+			if !fn(level) {
+				return
+			}
+		}
+	}
+}
+
+func TestAggregateSortedBids(t *testing.T) {
+	agg := New(types.Tick10)
+
+	levels := []types.PriceLevel{
+		{Price: decimal.NewFromFloat(50009), Quantity: decimal.NewFromFloat(1.0)},
+		{Price: decimal.NewFromFloat(50001), Quantity: decimal.NewFromFloat(1.5)},
+		{Price: decimal.NewFromFloat(49995), Quantity: decimal.NewFromFloat(2.0)},
+		{Price: decimal.NewFromFloat(49980), Quantity: decimal.NewFromFloat(3.0)},
+		{Price: decimal.NewFromFloat(49970), Quantity: decimal.NewFromFloat(4.0)},
+	}
+
+	result := agg.AggregateSortedBids(walkLevels(levels), 3)
+
+	expectedPrices := []float64{50000, 49990, 49980}
+	expectedQtys := []float64{2.5, 2.0, 3.0}
+	if len(result) != len(expectedPrices) {
+		t.Fatalf("Expected %d aggregated levels, got %d", len(expectedPrices), len(result))
+	}
+	for i := range result {
+		if !result[i].Price.Equal(decimal.NewFromFloat(expectedPrices[i])) {
+			t.Errorf("Level %d: expected price %g, got %s", i, expectedPrices[i], result[i].Price.String())
+		}
+		if !result[i].Quantity.Equal(decimal.NewFromFloat(expectedQtys[i])) {
+			t.Errorf("Level %d: expected quantity %g, got %s", i, expectedQtys[i], result[i].Quantity.String())
+		}
+	}
+}
+
+func TestAggregateSortedAsks(t *testing.T) {
+	agg := New(types.Tick1)
+
+	levels := []types.PriceLevel{
+		{Price: decimal.NewFromFloat(50000.1), Quantity: decimal.NewFromFloat(1.0)},
+		{Price: decimal.NewFromFloat(50000.9), Quantity: decimal.NewFromFloat(1.5)},
+		{Price: decimal.NewFromFloat(50001.5), Quantity: decimal.NewFromFloat(2.0)},
+	}
+
+	result := agg.AggregateSortedAsks(walkLevels(levels), 20)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 aggregated levels, got %d", len(result))
+	}
+	if !result[0].Price.Equal(decimal.NewFromFloat(50001)) || !result[0].Quantity.Equal(decimal.NewFromFloat(2.5)) {
+		t.Errorf("Expected 2.5 @ 50001, got %s @ %s", result[0].Quantity.String(), result[0].Price.String())
+	}
+	if !result[1].Price.Equal(decimal.NewFromFloat(50002)) {
+		t.Errorf("Expected second level at 50002, got %s", result[1].Price.String())
+	}
+}
+
 // Benchmarks
 
+func TestTickLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		tickSize string
+		refPrice string
+		expected []types.TickLevel
+	}{
+		{
+			name:     "BTC",
+			tickSize: "0.01",
+			refPrice: "100000",
+			expected: []types.TickLevel{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+		},
+		{
+			name:     "DOGE",
+			tickSize: "0.00001",
+			refPrice: "0.1",
+			expected: []types.TickLevel{0.00001, 0.00002, 0.00005, 0.0001, 0.0002, 0.0005, 0.001},
+		},
+		{
+			name:     "tick coarser than the ladder",
+			tickSize: "1",
+			refPrice: "50",
+			expected: []types.TickLevel{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := TickLevels(decimal.RequireFromString(tt.tickSize), decimal.RequireFromString(tt.refPrice))
+			if len(levels) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, levels)
+			}
+			for i := range levels {
+				if levels[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, levels)
+					break
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkAggregateBids(b *testing.B) {
 	agg := New(types.Tick1)
 
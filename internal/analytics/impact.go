@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"fmt"
+
+	"orderbook/internal/orderbook"
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// Side is the taker side of a simulated order
+type Side string
+
+const (
+	SideBuy  Side = "buy"  // Lifts asks
+	SideSell Side = "sell" // Hits bids
+)
+
+// SizeUnit selects whether an order size is given in base or quote units
+type SizeUnit string
+
+const (
+	UnitBase  SizeUnit = "base"  // e.g. BTC for BTCUSDT
+	UnitQuote SizeUnit = "quote" // e.g. USDT for BTCUSDT
+)
+
+var bpsMultiplier = decimal.NewFromInt(10000)
+
+// ImpactResult describes how a market order of a given size would fill against the book
+type ImpactResult struct {
+	Side           Side
+	Unit           SizeUnit
+	RequestedSize  decimal.Decimal // Size as requested, in Unit
+	FilledBase     decimal.Decimal // Base quantity that could be filled
+	FilledQuote    decimal.Decimal // Quote notional of the filled part
+	UnfilledSize   decimal.Decimal // Remainder that the book could not absorb, in Unit
+	MidPrice       decimal.Decimal // Mid price at the time of the calculation
+	AvgPrice       decimal.Decimal // Volume-weighted average fill price
+	WorstPrice     decimal.Decimal // Price of the last level touched
+	SlippageBps    decimal.Decimal // Average price vs mid in basis points (positive = cost)
+	LevelsConsumed int             // Number of price levels touched
+}
+
+// CalculateImpact walks the book from the top on the opposite side and returns the
+// simulated fill for a market order of the given size
+func CalculateImpact(ob *orderbook.OrderBook, side Side, size decimal.Decimal, unit SizeUnit) (ImpactResult, error) {
+	if side != SideBuy && side != SideSell {
+		return ImpactResult{}, fmt.Errorf("invalid side %q", side)
+	}
+	if unit != UnitBase && unit != UnitQuote {
+		return ImpactResult{}, fmt.Errorf("invalid size unit %q", unit)
+	}
+	if !size.IsPositive() {
+		return ImpactResult{}, fmt.Errorf("size must be positive, got %s", size.String())
+	}
+
+	stats := ob.GetStats()
+	if stats.BestBid.IsZero() || stats.BestAsk.IsZero() {
+		return ImpactResult{}, fmt.Errorf("orderbook has no two-sided market")
+	}
+
+	result := ImpactResult{
+		Side:          side,
+		Unit:          unit,
+		RequestedSize: size,
+		FilledBase:    decimal.Zero,
+		FilledQuote:   decimal.Zero,
+		MidPrice:      stats.BestBid.Add(stats.BestAsk).Div(decimal.NewFromInt(2)),
+	}
+
+	remaining := size
+	consume := func(level types.PriceLevel) bool {
+		levelQuote := level.Price.Mul(level.Quantity)
+
+		var baseTaken decimal.Decimal
+		if unit == UnitBase {
+			baseTaken = decimal.Min(level.Quantity, remaining)
+			remaining = remaining.Sub(baseTaken)
+		} else if levelQuote.LessThanOrEqual(remaining) {
+			baseTaken = level.Quantity
+			remaining = remaining.Sub(levelQuote)
+		} else {
+			baseTaken = remaining.Div(level.Price)
+			remaining = decimal.Zero
+		}
+
+		result.FilledBase = result.FilledBase.Add(baseTaken)
+		result.FilledQuote = result.FilledQuote.Add(baseTaken.Mul(level.Price))
+		result.WorstPrice = level.Price
+		result.LevelsConsumed++
+
+		return remaining.IsPositive()
+	}
+
+	if side == SideBuy {
+		ob.WalkAsks(consume)
+	} else {
+		ob.WalkBids(consume)
+	}
+
+	result.UnfilledSize = remaining
+
+	if result.FilledBase.IsPositive() {
+		result.AvgPrice = result.FilledQuote.Div(result.FilledBase)
+
+		slippage := result.AvgPrice.Sub(result.MidPrice)
+		if side == SideSell {
+			slippage = slippage.Neg()
+		}
+		result.SlippageBps = slippage.Div(result.MidPrice).Mul(bpsMultiplier)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+	"orderbook/internal/orderbook"
+)
+
+func newTestBook(t *testing.T) *orderbook.OrderBook {
+	t.Helper()
+
+	ob := orderbook.New()
+	err := ob.LoadSnapshot(&exchange.Snapshot{
+		LastUpdateID: 1,
+		Bids: []exchange.PriceLevel{
+			{Price: "99", Quantity: "1"},
+			{Price: "98", Quantity: "2"},
+		},
+		Asks: []exchange.PriceLevel{
+			{Price: "101", Quantity: "1"},
+			{Price: "102", Quantity: "2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	return ob
+}
+
+func TestCalculateImpact(t *testing.T) {
+	tests := []struct {
+		name          string
+		side          Side
+		size          string
+		unit          SizeUnit
+		avgPrice      string
+		worstPrice    string
+		unfilled      string
+		slippageBps   string
+		levelsTouched int
+	}{
+		{
+			name:          "Buy within first level",
+			side:          SideBuy,
+			size:          "0.5",
+			unit:          UnitBase,
+			avgPrice:      "101",
+			worstPrice:    "101",
+			unfilled:      "0",
+			slippageBps:   "100",
+			levelsTouched: 1,
+		},
+		{
+			name:          "Buy across two levels",
+			side:          SideBuy,
+			size:          "2",
+			unit:          UnitBase,
+			avgPrice:      "101.5",
+			worstPrice:    "102",
+			unfilled:      "0",
+			slippageBps:   "150",
+			levelsTouched: 2,
+		},
+		{
+			name:          "Sell more than the book holds",
+			side:          SideSell,
+			size:          "4",
+			unit:          UnitBase,
+			avgPrice:      "98.33333333",
+			worstPrice:    "98",
+			unfilled:      "1",
+			slippageBps:   "166.6666666666666667",
+			levelsTouched: 2,
+		},
+		{
+			name:          "Buy by quote notional",
+			side:          SideBuy,
+			size:          "203",
+			unit:          UnitQuote,
+			avgPrice:      "101.5",
+			worstPrice:    "102",
+			unfilled:      "0",
+			slippageBps:   "150",
+			levelsTouched: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := newTestBook(t)
+
+			result, err := CalculateImpact(ob, tt.side, decimal.RequireFromString(tt.size), tt.unit)
+			if err != nil {
+				t.Fatalf("CalculateImpact failed: %v", err)
+			}
+
+			if !result.AvgPrice.Round(8).Equal(decimal.RequireFromString(tt.avgPrice)) {
+				t.Errorf("Expected avg price %s, got %s", tt.avgPrice, result.AvgPrice.String())
+			}
+			if !result.WorstPrice.Equal(decimal.RequireFromString(tt.worstPrice)) {
+				t.Errorf("Expected worst price %s, got %s", tt.worstPrice, result.WorstPrice.String())
+			}
+			if !result.UnfilledSize.Equal(decimal.RequireFromString(tt.unfilled)) {
+				t.Errorf("Expected unfilled %s, got %s", tt.unfilled, result.UnfilledSize.String())
+			}
+			if !result.SlippageBps.Round(6).Equal(decimal.RequireFromString(tt.slippageBps).Round(6)) {
+				t.Errorf("Expected slippage %s bps, got %s", tt.slippageBps, result.SlippageBps.String())
+			}
+			if result.LevelsConsumed != tt.levelsTouched {
+				t.Errorf("Expected %d levels consumed, got %d", tt.levelsTouched, result.LevelsConsumed)
+			}
+		})
+	}
+}
+
+func TestCalculateImpactInvalidInput(t *testing.T) {
+	ob := newTestBook(t)
+
+	if _, err := CalculateImpact(ob, Side("hold"), decimal.NewFromInt(1), UnitBase); err == nil {
+		t.Error("Expected error for invalid side")
+	}
+	if _, err := CalculateImpact(ob, SideBuy, decimal.Zero, UnitBase); err == nil {
+		t.Error("Expected error for zero size")
+	}
+	if _, err := CalculateImpact(orderbook.New(), SideBuy, decimal.NewFromInt(1), UnitBase); err == nil {
+		t.Error("Expected error for empty book")
+	}
+}
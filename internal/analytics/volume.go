@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"fmt"
+	"sync"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+// VolumeSummary is the executed volume accumulated over a window, split by aggressor side
+type VolumeSummary struct {
+	Trades        int64
+	BuyQty        decimal.Decimal // Base quantity bought by takers
+	SellQty       decimal.Decimal // Base quantity sold by takers
+	BuyNotional   decimal.Decimal // Quote value bought by takers
+	SellNotional  decimal.Decimal // Quote value sold by takers
+	LastPrice     decimal.Decimal
+	VWAP          decimal.Decimal // Zero when no trades
+	TakerDeltaQty decimal.Decimal // BuyQty - SellQty
+}
+
+// TradeVolume accumulates trades from a venue's trade stream. Safe for concurrent use.
+type TradeVolume struct {
+	mu      sync.Mutex
+	summary VolumeSummary
+}
+
+// NewTradeVolume creates an empty volume accumulator
+func NewTradeVolume() *TradeVolume {
+	return &TradeVolume{}
+}
+
+// Add records a trade
+func (v *TradeVolume) Add(trade *exchange.Trade) error {
+	price, err := decimal.NewFromString(trade.Price)
+	if err != nil {
+		return fmt.Errorf("invalid trade price %q: %w", trade.Price, err)
+	}
+	qty, err := decimal.NewFromString(trade.Quantity)
+	if err != nil {
+		return fmt.Errorf("invalid trade quantity %q: %w", trade.Quantity, err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	notional := price.Mul(qty)
+	switch trade.Side {
+	case exchange.TradeSideBuy:
+		v.summary.BuyQty = v.summary.BuyQty.Add(qty)
+		v.summary.BuyNotional = v.summary.BuyNotional.Add(notional)
+	case exchange.TradeSideSell:
+		v.summary.SellQty = v.summary.SellQty.Add(qty)
+		v.summary.SellNotional = v.summary.SellNotional.Add(notional)
+	default:
+		return fmt.Errorf("invalid trade side %q", trade.Side)
+	}
+
+	v.summary.Trades++
+	v.summary.LastPrice = price
+	return nil
+}
+
+// Reset returns the volume accumulated since the previous Reset and starts a new window
+func (v *TradeVolume) Reset() VolumeSummary {
+	v.mu.Lock()
+	summary := v.summary
+	v.summary = VolumeSummary{LastPrice: summary.LastPrice}
+	v.mu.Unlock()
+
+	totalQty := summary.BuyQty.Add(summary.SellQty)
+	if totalQty.IsPositive() {
+		summary.VWAP = summary.BuyNotional.Add(summary.SellNotional).Div(totalQty)
+	}
+	summary.TakerDeltaQty = summary.BuyQty.Sub(summary.SellQty)
+	return summary
+}
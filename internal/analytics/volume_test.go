@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+)
+
+func TestTradeVolume(t *testing.T) {
+	v := NewTradeVolume()
+
+	trades := []exchange.Trade{
+		{Price: "100", Quantity: "2", Side: exchange.TradeSideBuy},
+		{Price: "101", Quantity: "1", Side: exchange.TradeSideBuy},
+		{Price: "99", Quantity: "1", Side: exchange.TradeSideSell},
+	}
+	for i := range trades {
+		if err := v.Add(&trades[i]); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	summary := v.Reset()
+
+	if summary.Trades != 3 {
+		t.Errorf("Expected 3 trades, got %d", summary.Trades)
+	}
+	if !summary.BuyQty.Equal(decimal.NewFromInt(3)) || !summary.SellQty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected 3 bought and 1 sold, got %s and %s", summary.BuyQty.String(), summary.SellQty.String())
+	}
+	if !summary.BuyNotional.Equal(decimal.NewFromInt(301)) {
+		t.Errorf("Expected buy notional 301, got %s", summary.BuyNotional.String())
+	}
+	// (200 + 101 + 99) / 4
+	if !summary.VWAP.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected VWAP 100, got %s", summary.VWAP.String())
+	}
+	if !summary.TakerDeltaQty.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected taker delta 2, got %s", summary.TakerDeltaQty.String())
+	}
+
+	// The next window starts empty but remembers the last price
+	next := v.Reset()
+	if next.Trades != 0 || !next.VWAP.IsZero() {
+		t.Errorf("Expected empty window, got %d trades with VWAP %s", next.Trades, next.VWAP.String())
+	}
+	if !next.LastPrice.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected last price 99, got %s", next.LastPrice.String())
+	}
+}
+
+func TestTradeVolumeRejectsInvalidTrades(t *testing.T) {
+	tests := []struct {
+		name  string
+		trade exchange.Trade
+	}{
+		{"bad price", exchange.Trade{Price: "abc", Quantity: "1", Side: exchange.TradeSideBuy}},
+		{"bad quantity", exchange.Trade{Price: "100", Quantity: "", Side: exchange.TradeSideBuy}},
+		{"bad side", exchange.Trade{Price: "100", Quantity: "1", Side: "maker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTradeVolume()
+			if err := v.Add(&tt.trade); err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if summary := v.Reset(); summary.Trades != 0 {
+				t.Errorf("Expected no trades recorded, got %d", summary.Trades)
+			}
+		})
+	}
+}
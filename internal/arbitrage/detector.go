@@ -0,0 +1,293 @@
+package arbitrage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"orderbook/internal/exchange"
+	"orderbook/internal/instruments"
+	"orderbook/internal/orderbook"
+
+	"github.com/shopspring/decimal"
+)
+
+// EventType describes the lifecycle stage of an opportunity
+type EventType string
+
+const (
+	EventOpened EventType = "opened"
+	EventClosed EventType = "closed"
+)
+
+const (
+	// historySize is the number of closed opportunities kept for History()
+	historySize = 200
+	// subscriberBuffer is the per-subscriber event channel size
+	subscriberBuffer = 100
+)
+
+var bpsMultiplier = decimal.NewFromInt(10000)
+
+// Config holds detector settings
+type Config struct {
+	CheckInterval      time.Duration
+	DefaultTakerFeeBps float64            // Fee used for venues missing from TakerFeeBps
+	TakerFeeBps        map[string]float64 // Per-venue taker fee in basis points
+}
+
+// Opportunity is a crossed market between two venues that survives taker fees
+type Opportunity struct {
+	BuyExchange  string          // Venue whose ask we lift
+	SellExchange string          // Venue whose bid we hit
+	BuyPrice     decimal.Decimal // Best ask on BuyExchange
+	SellPrice    decimal.Decimal // Best bid on SellExchange
+	Size         decimal.Decimal // Executable size at top of book (min of both sides)
+	GrossBps     decimal.Decimal // Edge before fees
+	NetBps       decimal.Decimal // Edge after both taker fees
+	MaxNetBps    decimal.Decimal // Best net edge seen while open
+	MaxSize      decimal.Decimal // Largest top-of-book size seen while open
+	OpenedAt     time.Time
+	LastSeen     time.Time
+	ClosedAt     time.Time     // Zero while open
+	Duration     time.Duration // LastSeen - OpenedAt while open, ClosedAt - OpenedAt once closed
+}
+
+// Event is emitted when an opportunity opens or closes
+type Event struct {
+	Type        EventType
+	Opportunity Opportunity
+}
+
+// Detector watches best bid/ask across running order books for cross-venue arbitrage. Only
+// venues trading the same market are compared: a USD and a USDT book, or a spot and a perp
+// book, differ by a basis that is not an arbitrage.
+type Detector struct {
+	books       *orderbook.Books
+	instruments *instruments.Registry
+	cfg         Config
+	mu          sync.Mutex
+	active      map[string]*Opportunity
+	history     []Opportunity
+	subscribers []chan Event
+}
+
+// NewDetector creates a detector over the venue books of a symbol. The registry tells which
+// market each venue trades; venues whose instrument is not loaded are left out.
+func NewDetector(books *orderbook.Books, registry *instruments.Registry, cfg Config) *Detector {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 500 * time.Millisecond
+	}
+	return &Detector{
+		books:       books,
+		instruments: registry,
+		cfg:         cfg,
+		active:      make(map[string]*Opportunity),
+		history:     make([]Opportunity, 0, historySize),
+	}
+}
+
+// Subscribe returns a channel that receives every opened/closed event, closed once Run returns.
+// Slow subscribers drop events rather than block detection.
+func (d *Detector) Subscribe() <-chan Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	d.subscribers = append(d.subscribers, ch)
+	return ch
+}
+
+// Run checks for opportunities every CheckInterval until done is closed
+func (d *Detector) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(d.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			d.Check(now)
+		case <-done:
+			d.closeSubscribers()
+			return
+		}
+	}
+}
+
+// Active returns the currently open opportunities, best net edge first
+func (d *Detector) Active() []Opportunity {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	opps := make([]Opportunity, 0, len(d.active))
+	for _, opp := range d.active {
+		opps = append(opps, *opp)
+	}
+	sort.Slice(opps, func(i, j int) bool {
+		return opps[i].NetBps.GreaterThan(opps[j].NetBps)
+	})
+	return opps
+}
+
+// History returns recently closed opportunities, oldest first
+func (d *Detector) History() []Opportunity {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	history := make([]Opportunity, len(d.history))
+	copy(history, d.history)
+	return history
+}
+
+// market is what a venue trades: prices in one quote asset, on spot or a kind of perpetual
+type market struct {
+	quoteAsset string
+	kind       exchange.InstrumentType
+}
+
+// quote is a venue's top of book. Sizes are in base units on every venue.
+type quote struct {
+	exchange string
+	market   market
+	bid      decimal.Decimal
+	bidQty   decimal.Decimal
+	ask      decimal.Decimal
+	askQty   decimal.Decimal
+}
+
+// Check compares every pair of venues trading the same market and emits events for
+// opportunities that opened or closed
+func (d *Detector) Check(now time.Time) {
+	quotes := d.collectQuotes()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seen := make(map[string]bool)
+
+	for _, buy := range quotes {
+		for _, sell := range quotes {
+			if buy.exchange == sell.exchange || buy.market != sell.market || !sell.bid.GreaterThan(buy.ask) {
+				continue
+			}
+
+			gross := sell.bid.Sub(buy.ask).Div(buy.ask).Mul(bpsMultiplier)
+			net := gross.Sub(d.feeBps(buy.exchange)).Sub(d.feeBps(sell.exchange))
+			if !net.IsPositive() {
+				continue
+			}
+
+			key := buy.exchange + "->" + sell.exchange
+			seen[key] = true
+			size := decimal.Min(buy.askQty, sell.bidQty)
+
+			opp, exists := d.active[key]
+			if !exists {
+				opp = &Opportunity{
+					BuyExchange:  buy.exchange,
+					SellExchange: sell.exchange,
+					MaxNetBps:    net,
+					MaxSize:      size,
+					OpenedAt:     now,
+				}
+				d.active[key] = opp
+			}
+
+			opp.BuyPrice = buy.ask
+			opp.SellPrice = sell.bid
+			opp.Size = size
+			opp.GrossBps = gross
+			opp.NetBps = net
+			opp.MaxNetBps = decimal.Max(opp.MaxNetBps, net)
+			opp.MaxSize = decimal.Max(opp.MaxSize, size)
+			opp.LastSeen = now
+			opp.Duration = now.Sub(opp.OpenedAt)
+
+			if !exists {
+				d.emit(Event{Type: EventOpened, Opportunity: *opp})
+			}
+		}
+	}
+
+	for key, opp := range d.active {
+		if seen[key] {
+			continue
+		}
+		opp.ClosedAt = now
+		opp.Duration = now.Sub(opp.OpenedAt)
+		delete(d.active, key)
+
+		d.recordHistory(*opp)
+		d.emit(Event{Type: EventClosed, Opportunity: *opp})
+	}
+}
+
+// collectQuotes reads the top of book from every initialized venue whose instrument is known
+func (d *Detector) collectQuotes() []quote {
+	books := d.books.Snapshot()
+	quotes := make([]quote, 0, len(books))
+	for _, book := range books {
+		ob := book.Book
+		if !ob.IsInitialized() {
+			continue
+		}
+		inst, ok := d.instruments.Get(book.Exchange)
+		if !ok || inst.QuoteAsset == "" {
+			continue
+		}
+
+		bids := ob.TopBids(1)
+		asks := ob.TopAsks(1)
+		if len(bids) == 0 || len(asks) == 0 {
+			continue
+		}
+
+		quotes = append(quotes, quote{
+			exchange: book.Exchange,
+			market:   market{quoteAsset: strings.ToUpper(inst.QuoteAsset), kind: inst.Type},
+			bid:      bids[0].Price,
+			bidQty:   bids[0].Quantity,
+			ask:      asks[0].Price,
+			askQty:   asks[0].Quantity,
+		})
+	}
+	return quotes
+}
+
+// feeBps returns the taker fee for a venue
+func (d *Detector) feeBps(exchange string) decimal.Decimal {
+	if fee, ok := d.cfg.TakerFeeBps[exchange]; ok {
+		return decimal.NewFromFloat(fee)
+	}
+	return decimal.NewFromFloat(d.cfg.DefaultTakerFeeBps)
+}
+
+// recordHistory appends a closed opportunity, dropping the oldest once full (must be called with mutex locked)
+func (d *Detector) recordHistory(opp Opportunity) {
+	if len(d.history) == historySize {
+		d.history = append(d.history[:0], d.history[1:]...)
+	}
+	d.history = append(d.history, opp)
+}
+
+// closeSubscribers closes every subscriber channel, so consumers stop once detection stops
+func (d *Detector) closeSubscribers() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, ch := range d.subscribers {
+		close(ch)
+	}
+	d.subscribers = nil
+}
+
+// emit delivers an event to every subscriber without blocking (must be called with mutex locked)
+func (d *Detector) emit(event Event) {
+	for _, ch := range d.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
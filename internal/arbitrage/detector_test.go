@@ -0,0 +1,176 @@
+package arbitrage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+	"orderbook/internal/instruments"
+	"orderbook/internal/orderbook"
+)
+
+func newVenueBook(t *testing.T, bid, ask [2]string) *orderbook.OrderBook {
+	t.Helper()
+
+	ob := orderbook.New()
+	err := ob.LoadSnapshot(&exchange.Snapshot{
+		LastUpdateID: 1,
+		Bids:         []exchange.PriceLevel{{Price: bid[0], Quantity: bid[1]}},
+		Asks:         []exchange.PriceLevel{{Price: ask[0], Quantity: ask[1]}},
+	})
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	ob.ProcessBufferedEvents()
+	return ob
+}
+
+// newRegistry records the quote asset and instrument type of each venue, as "USDT spot"
+func newRegistry(markets map[string][2]string) *instruments.Registry {
+	registry := instruments.NewRegistry()
+	for name, market := range markets {
+		registry.Set(exchange.Instrument{
+			Exchange:   exchange.ExchangeName(name),
+			Type:       exchange.InstrumentType(market[1]),
+			QuoteAsset: market[0],
+			TickSize:   decimal.RequireFromString("0.01"),
+		})
+	}
+	return registry
+}
+
+func TestDetectorOpenAndClose(t *testing.T) {
+	cheap := newVenueBook(t, [2]string{"99", "1"}, [2]string{"100", "2"})
+	rich := newVenueBook(t, [2]string{"101", "0.5"}, [2]string{"102", "1"})
+	books := orderbook.NewBooks()
+	books.Set("cheap", cheap)
+	books.Set("rich", rich)
+
+	registry := newRegistry(map[string][2]string{"cheap": {"USDT", "spot"}, "rich": {"USDT", "spot"}})
+	d := NewDetector(books, registry, Config{TakerFeeBps: map[string]float64{"cheap": 10, "rich": 20}})
+	events := d.Subscribe()
+
+	start := time.Unix(1000, 0)
+	d.Check(start)
+
+	select {
+	case event := <-events:
+		if event.Type != EventOpened {
+			t.Fatalf("Expected opened event, got %s", event.Type)
+		}
+		opp := event.Opportunity
+		if opp.BuyExchange != "cheap" || opp.SellExchange != "rich" {
+			t.Errorf("Expected cheap->rich, got %s->%s", opp.BuyExchange, opp.SellExchange)
+		}
+		// Gross 100 bps minus 30 bps of fees
+		if !opp.NetBps.Equal(decimal.NewFromInt(70)) {
+			t.Errorf("Expected 70 bps net, got %s", opp.NetBps.String())
+		}
+		if !opp.Size.Equal(decimal.RequireFromString("0.5")) {
+			t.Errorf("Expected size 0.5, got %s", opp.Size.String())
+		}
+	default:
+		t.Fatal("Expected an opened event")
+	}
+
+	// Rich venue's bid drops back below the cheap ask
+	rich.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 2,
+		FinalUpdateID: 2,
+		PrevUpdateID:  1,
+		Bids:          []exchange.PriceLevel{{Price: "101", Quantity: "0"}, {Price: "99.5", Quantity: "1"}},
+	})
+	d.Check(start.Add(1500 * time.Millisecond))
+
+	select {
+	case event := <-events:
+		if event.Type != EventClosed {
+			t.Fatalf("Expected closed event, got %s", event.Type)
+		}
+		if event.Opportunity.Duration != 1500*time.Millisecond {
+			t.Errorf("Expected duration 1.5s, got %v", event.Opportunity.Duration)
+		}
+	default:
+		t.Fatal("Expected a closed event")
+	}
+
+	if len(d.Active()) != 0 {
+		t.Errorf("Expected no active opportunities, got %d", len(d.Active()))
+	}
+	if len(d.History()) != 1 {
+		t.Errorf("Expected 1 closed opportunity in history, got %d", len(d.History()))
+	}
+}
+
+func TestDetectorIgnoresEdgeBelowFees(t *testing.T) {
+	books := orderbook.NewBooks()
+	books.Set("a", newVenueBook(t, [2]string{"99", "1"}, [2]string{"100", "1"}))
+	books.Set("b", newVenueBook(t, [2]string{"100.05", "1"}, [2]string{"101", "1"}))
+
+	registry := newRegistry(map[string][2]string{"a": {"USDT", "spot"}, "b": {"USDT", "spot"}})
+	d := NewDetector(books, registry, Config{DefaultTakerFeeBps: 5})
+	events := d.Subscribe()
+	d.Check(time.Now())
+
+	select {
+	case event := <-events:
+		t.Errorf("Expected no event for a 5 bps gross edge, got %+v", event)
+	default:
+	}
+}
+
+func TestDetectorComparesSameMarketOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		markets map[string][2]string
+		opens   bool
+	}{
+		{
+			name:    "same quote and type",
+			markets: map[string][2]string{"cheap": {"USDT", "spot"}, "rich": {"USDT", "spot"}},
+			opens:   true,
+		},
+		{
+			name:    "different quotes",
+			markets: map[string][2]string{"cheap": {"USD", "spot"}, "rich": {"USDT", "spot"}},
+		},
+		{
+			name:    "spot and perp",
+			markets: map[string][2]string{"cheap": {"USDT", "spot"}, "rich": {"USDT", "linear_perp"}},
+		},
+		{
+			name:    "instrument not loaded",
+			markets: map[string][2]string{"cheap": {"USDT", "spot"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := orderbook.NewBooks()
+			books.Set("cheap", newVenueBook(t, [2]string{"99", "1"}, [2]string{"100", "1"}))
+			books.Set("rich", newVenueBook(t, [2]string{"101", "1"}, [2]string{"102", "1"}))
+
+			d := NewDetector(books, newRegistry(tt.markets), Config{})
+			d.Check(time.Now())
+
+			if opens := len(d.Active()) > 0; opens != tt.opens {
+				t.Errorf("Expected opened=%v, got %v", tt.opens, opens)
+			}
+		})
+	}
+}
+
+func TestDetectorClosesSubscribersWhenStopped(t *testing.T) {
+	d := NewDetector(orderbook.NewBooks(), instruments.NewRegistry(), Config{})
+	events := d.Subscribe()
+
+	done := make(chan struct{})
+	close(done)
+	d.Run(done)
+
+	if _, ok := <-events; ok {
+		t.Error("Expected the events channel to be closed once Run returns")
+	}
+	d.Check(time.Now()) // Must not send on the closed channel
+}
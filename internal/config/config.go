@@ -12,6 +12,7 @@ type Config struct {
 	Exchanges []ExchangeConfig
 	Display   DisplayConfig
 	App       AppConfig
+	Arbitrage ArbitrageConfig
 }
 
 // ExchangeConfig holds exchange-specific configuration
@@ -28,10 +29,42 @@ type DisplayConfig struct {
 
 // AppConfig holds general application configuration
 type AppConfig struct {
-	DefaultTickLevel    types.TickLevel
-	ReinitCheckInterval time.Duration
-	MaxBufferSize       int
-	UpdateChannelSize   int
+	DefaultTickLevel        types.TickLevel
+	ReinitCheckInterval     time.Duration
+	DerivativesPollInterval time.Duration // How often perp adapters are polled for funding, mark price and open interest
+	MaxBufferSize           int
+	UpdateChannelSize       int
+	ReferenceQuote          string            // Currency stats and the consolidated book are shown in until a client picks another (USD, USDT, USDC, ...)
+	DepthBands              []types.DepthBand // Distances from mid within which liquidity is measured
+	HistoryResolution       time.Duration     // Interval between recorded stats samples
+	HistoryRetention        time.Duration     // How far back stats history is kept per exchange
+	HistoryBackfill         time.Duration     // History sent to websocket clients when they connect
+	MaxSymbols              int               // Symbols monitored at once, each with its own venue connections
+}
+
+// ArbitrageConfig holds cross-venue arbitrage detection settings
+type ArbitrageConfig struct {
+	CheckInterval      time.Duration
+	DefaultTakerFeeBps float64
+	TakerFeeBps        map[exchange.ExchangeName]float64 // Taker fee per venue in basis points
+}
+
+// DefaultTakerFeeBps returns the base-tier taker fees of each supported venue in basis points
+func DefaultTakerFeeBps() map[exchange.ExchangeName]float64 {
+	return map[exchange.ExchangeName]float64{
+		exchange.Binancef:     5,
+		exchange.Binance:      10,
+		exchange.Bybitf:       5.5,
+		exchange.Bybit:        10,
+		exchange.Kraken:       40,
+		exchange.Hyperliquidf: 4.5,
+		exchange.OKX:          10,
+		exchange.OKXf:         5,
+		exchange.Coinbase:     60,
+		exchange.Asterdexf:    3.5,
+		exchange.BingX:        10,
+		exchange.BingXf:       5,
+	}
 }
 
 // Default returns the default configuration for BTCUSDT on Binance Futures
@@ -48,10 +81,23 @@ func Default() Config {
 			UpdateInterval: 2 * time.Second,
 		},
 		App: AppConfig{
-			DefaultTickLevel:    types.Tick1,
-			ReinitCheckInterval: 5 * time.Second,
-			MaxBufferSize:       100,
-			UpdateChannelSize:   1000,
+			DefaultTickLevel:        types.Tick1,
+			ReinitCheckInterval:     5 * time.Second,
+			DerivativesPollInterval: 10 * time.Second,
+			MaxBufferSize:           100,
+			UpdateChannelSize:       1000,
+			ReferenceQuote:          "USD",
+			// BTC reads best at 10/25/50bps; for alts try types.Percent(1), types.Percent(5), types.Percent(20)
+			DepthBands:        []types.DepthBand{types.Percent(0.5), types.Percent(2), types.Percent(10)},
+			HistoryResolution: time.Second,
+			HistoryRetention:  time.Hour,
+			HistoryBackfill:   2 * time.Minute,
+			MaxSymbols:        8,
+		},
+		Arbitrage: ArbitrageConfig{
+			CheckInterval:      500 * time.Millisecond,
+			DefaultTakerFeeBps: 10,
+			TakerFeeBps:        DefaultTakerFeeBps(),
 		},
 	}
 }
@@ -0,0 +1,269 @@
+package consolidated
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+
+	"orderbook/internal/aggregation"
+	"orderbook/internal/orderbook"
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeName is the virtual exchange name used when streaming the consolidated book
+const ExchangeName = "consolidated"
+
+// VenueQuantity is one venue's share of a consolidated price level
+type VenueQuantity struct {
+	Exchange string
+	Quantity decimal.Decimal
+}
+
+// Level is a consolidated price level with its per-venue breakdown
+type Level struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	Venues   []VenueQuantity // Sorted by quantity descending
+}
+
+// TopOfBook holds the best bid and ask across all venues
+type TopOfBook struct {
+	BestBid         decimal.Decimal
+	BestBidQty      decimal.Decimal
+	BestBidExchange string
+	BestAsk         decimal.Decimal
+	BestAskQty      decimal.Decimal
+	BestAskExchange string
+	Crossed         bool // True when the best bid is at or above the best ask
+}
+
+// PriceConverter returns the factor that converts a venue's prices into the book's reference
+// quote, and false if the venue's quote cannot be converted
+type PriceConverter func(exchange string) (decimal.Decimal, bool)
+
+// Book merges every running OrderBook into a single view.
+// It holds no state of its own; each call reads the venue books live.
+type Book struct {
+	books   *orderbook.Books
+	convert PriceConverter
+}
+
+// venue is an initialized book with the factor converting its prices into the reference quote
+type venue struct {
+	name string
+	ob   *orderbook.OrderBook
+	rate decimal.Decimal
+}
+
+// New creates a consolidated book over the venue books of a symbol
+func New(books *orderbook.Books) *Book {
+	return &Book{books: books}
+}
+
+// SetPriceConverter makes the book merge venues quoted in different assets (USD, USDT, USDC)
+// by converting their prices first. Venues the converter rejects are left out of the merge.
+// Without a converter prices are merged as quoted.
+func (b *Book) SetPriceConverter(convert PriceConverter) {
+	b.convert = convert
+}
+
+// venues returns the initialized, convertible books in a stable (name) order
+func (b *Book) venues() []venue {
+	books := b.books.Snapshot()
+	venues := make([]venue, 0, len(books))
+	for _, book := range books {
+		if !book.Book.IsInitialized() {
+			continue
+		}
+		rate := decimal.NewFromInt(1)
+		if b.convert != nil {
+			var ok bool
+			if rate, ok = b.convert(book.Exchange); !ok {
+				continue
+			}
+		}
+		venues = append(venues, venue{name: book.Exchange, ob: book.Book, rate: rate})
+	}
+	return venues
+}
+
+// price converts a venue price into the reference quote
+func (v venue) price(p decimal.Decimal) decimal.Decimal {
+	if v.rate.Equal(decimal.NewFromInt(1)) {
+		return p
+	}
+	return p.Mul(v.rate)
+}
+
+// walk wraps a venue walker so levels come out in the reference quote. A positive rate keeps
+// the walk order, so the aggregator's early stop still holds.
+func (v venue) walk(walk aggregation.LevelWalker) aggregation.LevelWalker {
+	return func(fn func(level types.PriceLevel) bool) {
+		walk(func(level types.PriceLevel) bool {
+			level.Price = v.price(level.Price)
+			return fn(level)
+		})
+	}
+}
+
+// TopOfBook returns the consolidated best bid and ask, summing size across venues quoting the same price
+func (b *Book) TopOfBook() TopOfBook {
+	var top TopOfBook
+
+	for _, v := range b.venues() {
+		if bids := v.ob.TopBids(1); len(bids) > 0 {
+			bid := bids[0]
+			price := v.price(bid.Price)
+			switch {
+			case top.BestBidExchange == "" || price.GreaterThan(top.BestBid):
+				top.BestBid, top.BestBidQty, top.BestBidExchange = price, bid.Quantity, v.name
+			case price.Equal(top.BestBid):
+				top.BestBidQty = top.BestBidQty.Add(bid.Quantity)
+			}
+		}
+
+		if asks := v.ob.TopAsks(1); len(asks) > 0 {
+			ask := asks[0]
+			price := v.price(ask.Price)
+			switch {
+			case top.BestAskExchange == "" || price.LessThan(top.BestAsk):
+				top.BestAsk, top.BestAskQty, top.BestAskExchange = price, ask.Quantity, v.name
+			case price.Equal(top.BestAsk):
+				top.BestAskQty = top.BestAskQty.Add(ask.Quantity)
+			}
+		}
+	}
+
+	top.Crossed = top.BestBidExchange != "" && top.BestAskExchange != "" && top.BestBid.GreaterThanOrEqual(top.BestAsk)
+	return top
+}
+
+// Ladder returns the consolidated depth ladder at the aggregator's tick, depth buckets per side.
+// Bids are sorted descending and asks ascending. Venue prices are converted before bucketing.
+func (b *Book) Ladder(agg *aggregation.Aggregator, depth int) ([]Level, []Level) {
+	bidBuckets := make(map[string]*Level)
+	askBuckets := make(map[string]*Level)
+
+	for _, v := range b.venues() {
+		// A venue can only contribute to the consolidated top-N through its own top-N buckets
+		mergeVenue(bidBuckets, v.name, agg.AggregateSortedBids(v.walk(v.ob.WalkBids), depth))
+		mergeVenue(askBuckets, v.name, agg.AggregateSortedAsks(v.walk(v.ob.WalkAsks), depth))
+	}
+
+	bids := sortedLevels(bidBuckets, true, depth)
+	asks := sortedLevels(askBuckets, false, depth)
+	return bids, asks
+}
+
+// Stats returns venue statistics summed across all running books, with consolidated best prices.
+// Notionals are summed after conversion into the reference quote.
+func (b *Book) Stats() types.Stats {
+	var stats types.Stats
+
+	for _, v := range b.venues() {
+		s := v.ob.GetStats().ConvertQuote(v.rate)
+
+		stats.EventsProcessed += s.EventsProcessed
+		stats.BufferedEvents += s.BufferedEvents
+		stats.BidLevels += s.BidLevels
+		stats.AskLevels += s.AskLevels
+		if s.LastEventTime.After(stats.LastEventTime) {
+			stats.LastEventTime = s.LastEventTime
+		}
+
+		stats.Depth = mergeDepth(stats.Depth, s.Depth)
+		stats.TotalBidsQty = stats.TotalBidsQty.Add(s.TotalBidsQty)
+		stats.TotalAsksQty = stats.TotalAsksQty.Add(s.TotalAsksQty)
+
+		stats.TotalBidsNotional = stats.TotalBidsNotional.Add(s.TotalBidsNotional)
+		stats.TotalAsksNotional = stats.TotalAsksNotional.Add(s.TotalAsksNotional)
+
+		// Weighted mids are summed by size here and divided out below
+		topQty := s.TopBidsQty.Add(s.TopAsksQty)
+		stats.WeightedMid = stats.WeightedMid.Add(s.WeightedMid.Mul(topQty))
+		stats.TopBidsQty = stats.TopBidsQty.Add(s.TopBidsQty)
+		stats.TopAsksQty = stats.TopAsksQty.Add(s.TopAsksQty)
+		stats.OrderFlowImbalance = stats.OrderFlowImbalance.Add(s.OrderFlowImbalance)
+	}
+
+	top := b.TopOfBook()
+	stats.BestBid = top.BestBid
+	stats.BestAsk = top.BestAsk
+	if !top.Crossed && !stats.BestBid.IsZero() && !stats.BestAsk.IsZero() {
+		stats.Spread = stats.BestAsk.Sub(stats.BestBid)
+	}
+	if topQty := top.BestBidQty.Add(top.BestAskQty); topQty.IsPositive() {
+		stats.Microprice = top.BestBid.Mul(top.BestAskQty).Add(top.BestAsk.Mul(top.BestBidQty)).Div(topQty)
+	}
+	if topQty := stats.TopBidsQty.Add(stats.TopAsksQty); topQty.IsPositive() {
+		stats.WeightedMid = stats.WeightedMid.Div(topQty)
+		stats.TopImbalance = stats.TopBidsQty.Sub(stats.TopAsksQty).Div(topQty)
+	}
+
+	for i := range stats.Depth {
+		depth := &stats.Depth[i]
+		depth.DeltaQty = depth.BidQty.Sub(depth.AskQty)
+		depth.DeltaNotional = depth.BidNotional.Sub(depth.AskNotional)
+	}
+	stats.TotalDelta = stats.TotalBidsQty.Sub(stats.TotalAsksQty)
+	stats.TotalNotionalDelta = stats.TotalBidsNotional.Sub(stats.TotalAsksNotional)
+
+	return stats
+}
+
+// mergeDepth adds one venue's depth bands into the running totals, matching bands by
+// width so venues configured with different bands still merge
+func mergeDepth(total, venue []types.DepthLiquidity) []types.DepthLiquidity {
+	for _, d := range venue {
+		i := slices.IndexFunc(total, func(t types.DepthLiquidity) bool { return t.Band == d.Band })
+		if i < 0 {
+			total = append(total, types.DepthLiquidity{Band: d.Band})
+			i = len(total) - 1
+		}
+		total[i].BidQty = total[i].BidQty.Add(d.BidQty)
+		total[i].AskQty = total[i].AskQty.Add(d.AskQty)
+		total[i].BidNotional = total[i].BidNotional.Add(d.BidNotional)
+		total[i].AskNotional = total[i].AskNotional.Add(d.AskNotional)
+	}
+	slices.SortFunc(total, func(a, b types.DepthLiquidity) int { return cmp.Compare(a.Band, b.Band) })
+	return total
+}
+
+// mergeVenue adds one venue's aggregated levels into the consolidated buckets
+func mergeVenue(buckets map[string]*Level, venue string, levels []types.PriceLevel) {
+	for _, level := range levels {
+		key := level.Price.String()
+		bucket, exists := buckets[key]
+		if !exists {
+			bucket = &Level{Price: level.Price, Quantity: decimal.Zero}
+			buckets[key] = bucket
+		}
+		bucket.Quantity = bucket.Quantity.Add(level.Quantity)
+		bucket.Venues = append(bucket.Venues, VenueQuantity{Exchange: venue, Quantity: level.Quantity})
+	}
+}
+
+// sortedLevels orders buckets best price first and keeps at most depth of them
+func sortedLevels(buckets map[string]*Level, descending bool, depth int) []Level {
+	levels := make([]Level, 0, len(buckets))
+	for _, bucket := range buckets {
+		sort.Slice(bucket.Venues, func(i, j int) bool {
+			return bucket.Venues[i].Quantity.GreaterThan(bucket.Venues[j].Quantity)
+		})
+		levels = append(levels, *bucket)
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price.GreaterThan(levels[j].Price)
+		}
+		return levels[i].Price.LessThan(levels[j].Price)
+	})
+
+	if len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return levels
+}
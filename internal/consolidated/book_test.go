@@ -0,0 +1,167 @@
+package consolidated
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/aggregation"
+	"orderbook/internal/exchange"
+	"orderbook/internal/orderbook"
+	"orderbook/internal/types"
+)
+
+func newVenueBook(t *testing.T, bids, asks [][2]string) *orderbook.OrderBook {
+	t.Helper()
+
+	snapshot := &exchange.Snapshot{LastUpdateID: 1}
+	for _, b := range bids {
+		snapshot.Bids = append(snapshot.Bids, exchange.PriceLevel{Price: b[0], Quantity: b[1]})
+	}
+	for _, a := range asks {
+		snapshot.Asks = append(snapshot.Asks, exchange.PriceLevel{Price: a[0], Quantity: a[1]})
+	}
+
+	ob := orderbook.New()
+	if err := ob.LoadSnapshot(snapshot); err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	ob.ProcessBufferedEvents()
+	return ob
+}
+
+func newTestBook(t *testing.T) *Book {
+	books := orderbook.NewBooks()
+	books.Set("binance", newVenueBook(t,
+		[][2]string{{"100.4", "1"}, {"99.2", "2"}},
+		[][2]string{{"101.3", "1"}, {"102.1", "2"}},
+	))
+	books.Set("bybit", newVenueBook(t,
+		[][2]string{{"100.4", "3"}, {"98.7", "1"}},
+		[][2]string{{"100.9", "2"}, {"101.6", "1"}},
+	))
+	return New(books)
+}
+
+func TestTopOfBook(t *testing.T) {
+	top := newTestBook(t).TopOfBook()
+
+	if !top.BestBid.Equal(decimal.RequireFromString("100.4")) || !top.BestBidQty.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("Expected best bid 4 @ 100.4, got %s @ %s", top.BestBidQty.String(), top.BestBid.String())
+	}
+	if !top.BestAsk.Equal(decimal.RequireFromString("100.9")) || top.BestAskExchange != "bybit" {
+		t.Errorf("Expected best ask 100.9 on bybit, got %s on %s", top.BestAsk.String(), top.BestAskExchange)
+	}
+	if top.Crossed {
+		t.Error("Expected market not to be crossed")
+	}
+}
+
+func TestLadder(t *testing.T) {
+	bids, asks := newTestBook(t).Ladder(aggregation.New(types.Tick1), 2)
+
+	if len(bids) != 2 || len(asks) != 2 {
+		t.Fatalf("Expected 2 levels per side, got %d bids and %d asks", len(bids), len(asks))
+	}
+
+	// 100.4 on both venues floors to 100
+	if !bids[0].Price.Equal(decimal.NewFromInt(100)) || !bids[0].Quantity.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("Expected 4 @ 100, got %s @ %s", bids[0].Quantity.String(), bids[0].Price.String())
+	}
+	if len(bids[0].Venues) != 2 || bids[0].Venues[0].Exchange != "bybit" {
+		t.Errorf("Expected bybit-first venue breakdown, got %+v", bids[0].Venues)
+	}
+	if !bids[1].Price.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected second bid at 99, got %s", bids[1].Price.String())
+	}
+
+	// 100.9 (bybit) ceils to 101; 101.3 (binance) and 101.6 (bybit) ceil to 102
+	if !asks[0].Price.Equal(decimal.NewFromInt(101)) || !asks[0].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected 2 @ 101, got %s @ %s", asks[0].Quantity.String(), asks[0].Price.String())
+	}
+	if !asks[1].Price.Equal(decimal.NewFromInt(102)) || !asks[1].Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected 2 @ 102, got %s @ %s", asks[1].Quantity.String(), asks[1].Price.String())
+	}
+}
+
+func TestPriceConverter(t *testing.T) {
+	book := newTestBook(t)
+	book.books.Set("kraken", newVenueBook(t, [][2]string{{"200", "5"}}, [][2]string{{"201", "5"}}))
+	book.SetPriceConverter(func(exchange string) (decimal.Decimal, bool) {
+		switch exchange {
+		case "bybit":
+			return decimal.RequireFromString("0.99"), true
+		case "binance":
+			return decimal.NewFromInt(1), true
+		}
+		return decimal.Zero, false // kraken's quote has no rate
+	})
+
+	// bybit's 100.4 bid converts to 99.396, leaving binance alone at the top
+	top := book.TopOfBook()
+	if !top.BestBid.Equal(decimal.RequireFromString("100.4")) || top.BestBidExchange != "binance" || !top.BestBidQty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected best bid 1 @ 100.4 on binance, got %s @ %s on %s", top.BestBidQty, top.BestBid, top.BestBidExchange)
+	}
+	if !top.BestAsk.Equal(decimal.RequireFromString("99.891")) || top.BestAskExchange != "bybit" {
+		t.Errorf("Expected best ask 99.891 on bybit, got %s on %s", top.BestAsk, top.BestAskExchange)
+	}
+	if !top.Crossed {
+		t.Error("Expected the converted books to cross")
+	}
+
+	bids, _ := book.Ladder(aggregation.New(types.Tick1), 3)
+	if len(bids) != 3 || !bids[0].Price.Equal(decimal.NewFromInt(100)) || !bids[1].Price.Equal(decimal.NewFromInt(99)) {
+		t.Fatalf("Expected converted bids at 100 and 99, got %+v", bids)
+	}
+	if !bids[1].Quantity.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected 5 @ 99 (binance 99.2 and bybit 99.396), got %s", bids[1].Quantity)
+	}
+}
+
+func TestStatsMergesDepthBands(t *testing.T) {
+	book := newTestBook(t)
+	bybit, _ := book.books.Get("bybit")
+	bybit.SetDepthBands([]types.DepthBand{types.Bps(10), types.Percent(2)})
+	binance, _ := book.books.Get("binance")
+	binance.SetDepthBands([]types.DepthBand{types.Percent(2)})
+
+	// Every bid is within 2% of its venue's mid: 1+2 on binance, 3+1 on bybit
+	stats := book.Stats()
+	if len(stats.Depth) != 2 || stats.Depth[0].Band != types.Bps(10) || stats.Depth[1].Band != types.Percent(2) {
+		t.Fatalf("Expected the 10bps and 2%% bands, got %+v", stats.Depth)
+	}
+	if !stats.Depth[1].BidQty.Equal(decimal.NewFromInt(7)) {
+		t.Errorf("Expected 7 bid within 2%%, got %s", stats.Depth[1].BidQty)
+	}
+	if !stats.Depth[1].DeltaQty.Equal(stats.Depth[1].BidQty.Sub(stats.Depth[1].AskQty)) {
+		t.Errorf("Expected the merged delta to match the merged sizes, got %s", stats.Depth[1].DeltaQty)
+	}
+}
+
+func TestVenuesChangeWhileReading(t *testing.T) {
+	book := newTestBook(t)
+	kraken := newVenueBook(t, [][2]string{{"100", "1"}}, [][2]string{{"101", "1"}})
+	agg := aggregation.New(types.Tick1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			book.books.Set("kraken", kraken)
+			book.books.Delete("kraken")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			book.Stats()
+			book.Ladder(agg, 5)
+		}
+	}()
+	wg.Wait()
+
+	if top := book.TopOfBook(); top.BestBidExchange != "binance" {
+		t.Errorf("Expected binance at the top once kraken left, got %s", top.BestBidExchange)
+	}
+}
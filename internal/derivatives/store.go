@@ -0,0 +1,80 @@
+package derivatives
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"orderbook/internal/exchange"
+)
+
+// Store holds the latest perpetual contract data per exchange
+type Store struct {
+	mu   sync.RWMutex
+	info map[string]exchange.DerivativesInfo
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{info: make(map[string]exchange.DerivativesInfo)}
+}
+
+// Set records the latest data for an exchange
+func (s *Store) Set(info exchange.DerivativesInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info[string(info.Exchange)] = info
+}
+
+// Get returns the latest data for an exchange
+func (s *Store) Get(exchangeName string) (exchange.DerivativesInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, ok := s.info[exchangeName]
+	return info, ok
+}
+
+// All returns a copy of the latest data for every exchange
+func (s *Store) All() map[string]exchange.DerivativesInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make(map[string]exchange.DerivativesInfo, len(s.info))
+	for name, info := range s.info {
+		all[name] = info
+	}
+	return all
+}
+
+// Delete removes an exchange, e.g. when its connection shuts down
+func (s *Store) Delete(exchangeName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.info, exchangeName)
+}
+
+// Poll fetches derivatives data immediately and then every interval until ctx is cancelled.
+// Failed fetches are logged and keep the previous value.
+func (s *Store) Poll(ctx context.Context, provider exchange.DerivativesProvider, name exchange.ExchangeName, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := provider.GetDerivativesInfo(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("[%s] Failed to fetch derivatives info: %v", name, err)
+		} else {
+			s.Set(*info)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package derivatives
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"orderbook/internal/exchange"
+)
+
+type fakeProvider struct {
+	calls chan struct{}
+	err   error
+}
+
+func (p *fakeProvider) GetDerivativesInfo(ctx context.Context) (*exchange.DerivativesInfo, error) {
+	defer func() { p.calls <- struct{}{} }()
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &exchange.DerivativesInfo{
+		Exchange:    exchange.Binancef,
+		FundingRate: "0.0001",
+		MarkPrice:   "100000",
+	}, nil
+}
+
+func TestStoreSetGetDelete(t *testing.T) {
+	store := NewStore()
+	store.Set(exchange.DerivativesInfo{Exchange: exchange.Bybitf, FundingRate: "-0.0002"})
+
+	info, ok := store.Get("bybitf")
+	if !ok || info.FundingRate != "-0.0002" {
+		t.Errorf("Expected bybitf funding -0.0002, got %+v (found=%v)", info, ok)
+	}
+	if len(store.All()) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(store.All()))
+	}
+
+	store.Delete("bybitf")
+	if _, ok := store.Get("bybitf"); ok {
+		t.Error("Expected bybitf to be deleted")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantStore bool
+	}{
+		{name: "success stores info", wantStore: true},
+		{name: "error keeps store empty", err: errors.New("boom"), wantStore: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			provider := &fakeProvider{calls: make(chan struct{}, 1), err: tt.err}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				store.Poll(ctx, provider, exchange.Binancef, time.Hour)
+				close(stopped)
+			}()
+
+			// The first fetch happens immediately, without waiting for the interval
+			select {
+			case <-provider.calls:
+			case <-time.After(time.Second):
+				t.Fatal("Expected an immediate fetch")
+			}
+
+			// Give Poll a moment to record the result before stopping it
+			deadline := time.Now().Add(time.Second)
+			for tt.wantStore && time.Now().Before(deadline) {
+				if _, ok := store.Get(string(exchange.Binancef)); ok {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			cancel()
+			<-stopped
+
+			_, ok := store.Get(string(exchange.Binancef))
+			if ok != tt.wantStore {
+				t.Errorf("Expected stored=%v, got %v", tt.wantStore, ok)
+			}
+		})
+	}
+}
@@ -7,29 +7,31 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
+
+	"github.com/shopspring/decimal"
 )
 
 // FuturesExchange implements the Exchange interface for Asterdex Futures
 type FuturesExchange struct {
 	symbol     string
-	wsURL      string
 	restURL    string
-	wsConn     *websocket.Conn
+	premiumURL string
+	oiURL      string
+	infoURL    string
+	ws         *supervisor.Supervisor
 	updateChan chan *exchange.DepthUpdate
-	done       chan struct{}
 	ctx        context.Context
 	cancel     context.CancelFunc
-	health     atomic.Value // stores exchange.HealthStatus
+	resyncID   int64 // lastUpdateId of the snapshot fetched after a reconnect (read goroutine only)
 }
 
 // Config holds configuration for Asterdex Futures exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTCUSDT), as resolved by the symbols package
 }
 
 // NewFuturesExchange creates a new Asterdex Futures exchange instance
@@ -39,22 +41,26 @@ func NewFuturesExchange(config Config) *FuturesExchange {
 	symbol := strings.ToLower(config.Symbol)
 	wsURL := fmt.Sprintf("wss://fstream.asterdex.com/ws/%s@depth", symbol)
 	restURL := fmt.Sprintf("https://fapi.asterdex.com/fapi/v1/depth?symbol=%s&limit=1000", strings.ToUpper(config.Symbol))
+	premiumURL := fmt.Sprintf("https://fapi.asterdex.com/fapi/v1/premiumIndex?symbol=%s", strings.ToUpper(config.Symbol))
+	oiURL := fmt.Sprintf("https://fapi.asterdex.com/fapi/v1/openInterest?symbol=%s", strings.ToUpper(config.Symbol))
+	infoURL := "https://fapi.asterdex.com/fapi/v1/exchangeInfo"
 
 	ex := &FuturesExchange{
 		symbol:     config.Symbol,
-		wsURL:      wsURL,
 		restURL:    restURL,
+		premiumURL: premiumURL,
+		oiURL:      oiURL,
+		infoURL:    infoURL,
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
 		ctx:        ctx,
 		cancel:     cancel,
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:      ex.GetName(),
+		URL:       wsURL,
+		Resync:    ex.resync,
+		OnMessage: ex.handleMessage,
 	})
 
 	return ex
@@ -70,54 +76,19 @@ func (e *FuturesExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Asterdex Futures
+// Connect establishes WebSocket connection to Asterdex Futures; the supervisor reconnects it if it drops
 func (e *FuturesExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
-
-	go e.readMessages()
-
-	return nil
+	return e.ws.Start(ctx)
 }
 
 // Close closes the WebSocket connection
 func (e *FuturesExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
+	e.cancel()
+	err := e.ws.Close()
 
-	if e.wsConn != nil {
-		select {
-		case <-e.done:
-		default:
-			close(e.done)
-		}
-
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		return e.wsConn.Close()
-	}
-	return nil
+	// The read goroutine has exited, so nothing sends on the channels anymore
+	close(e.updateChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via REST API
@@ -132,14 +103,14 @@ func (e *FuturesExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot,
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var asterdexSnapshot SnapshotResponse
 	if err := json.NewDecoder(resp.Body).Decode(&asterdexSnapshot); err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
 	}
 
@@ -147,6 +118,98 @@ func (e *FuturesExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot,
 	return snapshot, nil
 }
 
+// GetDerivativesInfo fetches funding, mark/index price and open interest via REST API
+func (e *FuturesExchange) GetDerivativesInfo(ctx context.Context) (*exchange.DerivativesInfo, error) {
+	var premium PremiumIndexResponse
+	if err := e.fetchJSON(ctx, e.premiumURL, &premium); err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %w", err)
+	}
+
+	var openInterest OpenInterestResponse
+	if err := e.fetchJSON(ctx, e.oiURL, &openInterest); err != nil {
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
+	}
+
+	return &exchange.DerivativesInfo{
+		Exchange:        e.GetName(),
+		Symbol:          e.symbol,
+		FundingRate:     premium.LastFundingRate,
+		NextFundingTime: time.UnixMilli(premium.NextFundingTime),
+		MarkPrice:       premium.MarkPrice,
+		IndexPrice:      premium.IndexPrice,
+		OpenInterest:    openInterest.OpenInterest,
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// GetInstrument fetches the contract's trading rules via REST API
+func (e *FuturesExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	var info ExchangeInfoResponse
+	if err := e.fetchJSON(ctx, e.infoURL, &info); err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	for _, s := range info.Symbols {
+		if !strings.EqualFold(s.Symbol, e.symbol) {
+			continue
+		}
+
+		inst := &exchange.Instrument{
+			Exchange:           e.GetName(),
+			Symbol:             s.Symbol,
+			Type:               exchange.InstrumentLinearPerp,
+			BaseAsset:          s.BaseAsset,
+			QuoteAsset:         s.QuoteAsset,
+			ContractMultiplier: decimal.NewFromInt(1),
+			BaseSizes:          true,
+		}
+		for _, f := range s.Filters {
+			var err error
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				inst.TickSize, err = decimal.NewFromString(f.TickSize)
+			case "LOT_SIZE":
+				inst.LotSize, err = decimal.NewFromString(f.StepSize)
+			case "MIN_NOTIONAL":
+				inst.MinNotional, err = decimal.NewFromString(f.Notional)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s filter for %s: %w", f.FilterType, s.Symbol, err)
+			}
+		}
+		return inst, nil
+	}
+
+	return nil, fmt.Errorf("symbol %s not found in exchange info", e.symbol)
+}
+
+// fetchJSON performs a GET request and decodes the Asterdex JSON response into v
+func (e *FuturesExchange) fetchJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e.ws.IncrementErrorCount()
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		e.ws.IncrementErrorCount()
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
 // Updates returns a channel that receives depth updates
 func (e *FuturesExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
@@ -154,52 +217,63 @@ func (e *FuturesExchange) Updates() <-chan *exchange.DepthUpdate {
 
 // IsConnected checks if the WebSocket connection is active
 func (e *FuturesExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *FuturesExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
+	return e.ws.Health()
+}
+
+// handleMessage processes a depth update message
+func (e *FuturesExchange) handleMessage(_ int, message []byte) {
+	var msg DepthUpdate
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse depth update: %v", e.GetName(), err)
+		return
+	}
+
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
+
+	if msg.FinalUpdateID <= e.resyncID {
+		return
 	}
-	return exchange.HealthStatus{}
+	e.sendUpdate(e.convertDepthUpdate(&msg))
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *FuturesExchange) readMessages() {
-	defer close(e.updateChan)
-	defer e.updateConnectionStatus(false)
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			var msg DepthUpdate
-			if err := e.wsConn.ReadJSON(&msg); err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				return
-			}
+// resync replaces the downstream book with a fresh REST snapshot after a reconnect, since depth
+// events were missed while disconnected. Events the snapshot already covers are skipped.
+func (e *FuturesExchange) resync() error {
+	ctx, cancel := context.WithTimeout(e.ctx, 15*time.Second)
+	defer cancel()
 
-			e.incrementMessageCount()
-			e.updateLastPing()
+	snapshot, err := e.GetSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	e.resyncID = snapshot.LastUpdateID
 
-			canonicalUpdate := e.convertDepthUpdate(&msg)
+	e.sendUpdate(&exchange.DepthUpdate{
+		Exchange:      e.GetName(),
+		Symbol:        snapshot.Symbol,
+		EventTime:     snapshot.Timestamp,
+		FirstUpdateID: snapshot.LastUpdateID,
+		FinalUpdateID: snapshot.LastUpdateID,
+		Bids:          snapshot.Bids,
+		Asks:          snapshot.Asks,
+		IsSnapshot:    true,
+	})
+	return nil
+}
 
-			select {
-			case e.updateChan <- canonicalUpdate:
-			case <-e.ctx.Done():
-				return
-			case <-e.done:
-				return
-			default:
-				log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-			}
-		}
+// sendUpdate forwards an update without blocking
+func (e *FuturesExchange) sendUpdate(update *exchange.DepthUpdate) {
+	select {
+	case e.updateChan <- update:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 	}
 }
 
@@ -260,35 +334,3 @@ func (e *FuturesExchange) convertDepthUpdate(update *DepthUpdate) *exchange.Dept
 		Asks:          asks,
 	}
 }
-
-// updateConnectionStatus updates the connection status in health
-func (e *FuturesExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
-	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *FuturesExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *FuturesExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *FuturesExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
-}
@@ -19,3 +19,41 @@ type DepthUpdate struct {
 	Bids            [][]string `json:"b"`  // Bids to be updated
 	Asks            [][]string `json:"a"`  // Asks to be updated
 }
+
+// PremiumIndexResponse represents the REST API response for Asterdex Futures mark price and funding rate
+type PremiumIndexResponse struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// OpenInterestResponse represents the REST API response for Asterdex Futures open interest
+type OpenInterestResponse struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+// ExchangeInfoResponse represents the REST API response for Asterdex exchange (trading rules) info
+type ExchangeInfoResponse struct {
+	Symbols []SymbolInfo `json:"symbols"`
+}
+
+// SymbolInfo represents the trading rules of a single Asterdex contract
+type SymbolInfo struct {
+	Symbol     string         `json:"symbol"`
+	BaseAsset  string         `json:"baseAsset"`
+	QuoteAsset string         `json:"quoteAsset"`
+	Filters    []SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter represents one entry of a contract's filters; only the fields of the filter type are set
+type SymbolFilter struct {
+	FilterType string `json:"filterType"`
+	TickSize   string `json:"tickSize"` // PRICE_FILTER
+	StepSize   string `json:"stepSize"` // LOT_SIZE
+	Notional   string `json:"notional"` // MIN_NOTIONAL
+}
@@ -0,0 +1,55 @@
+package binance
+
+import (
+	"fmt"
+	"strings"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+// findSymbol returns the entry for symbol from an exchangeInfo response
+func findSymbol(info *ExchangeInfoResponse, symbol string) (*SymbolInfo, error) {
+	for i := range info.Symbols {
+		if strings.EqualFold(info.Symbols[i].Symbol, symbol) {
+			return &info.Symbols[i], nil
+		}
+	}
+	return nil, fmt.Errorf("symbol %s not found in exchange info", symbol)
+}
+
+// toInstrument converts a symbol's filters into the canonical instrument. Binance quotes
+// book sizes in base units on both spot and USDⓈ-M futures.
+func (s *SymbolInfo) toInstrument(name exchange.ExchangeName, instType exchange.InstrumentType) (*exchange.Instrument, error) {
+	inst := &exchange.Instrument{
+		Exchange:           name,
+		Symbol:             s.Symbol,
+		Type:               instType,
+		BaseAsset:          s.BaseAsset,
+		QuoteAsset:         s.QuoteAsset,
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}
+
+	for _, f := range s.Filters {
+		var err error
+		switch f.FilterType {
+		case "PRICE_FILTER":
+			inst.TickSize, err = decimal.NewFromString(f.TickSize)
+		case "LOT_SIZE":
+			inst.LotSize, err = decimal.NewFromString(f.StepSize)
+		case "NOTIONAL", "MIN_NOTIONAL":
+			value := f.MinNotional
+			if value == "" {
+				value = f.Notional
+			}
+			inst.MinNotional, err = decimal.NewFromString(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s filter for %s: %w", f.FilterType, s.Symbol, err)
+		}
+	}
+
+	return inst, nil
+}
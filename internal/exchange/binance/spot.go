@@ -6,25 +6,25 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
 )
 
 // SpotExchange implements the Exchange interface for Binance Spot
 type SpotExchange struct {
 	symbol     string
-	wsURL      string
 	restURL    string
-	wsConn     *websocket.Conn
+	infoURL    string
+	ws         *supervisor.Supervisor
 	updateChan chan *exchange.DepthUpdate
-	done       chan struct{}
+	tradeChan  chan *exchange.Trade
 	ctx        context.Context
 	cancel     context.CancelFunc
-	health     atomic.Value // stores exchange.HealthStatus
+	resyncID   int64 // lastUpdateId of the snapshot fetched after a reconnect (read goroutine only)
 }
 
 // NewSpotExchange creates a new Binance Spot exchange instance
@@ -32,24 +32,25 @@ func NewSpotExchange(config Config) *SpotExchange {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	symbol := strings.ToLower(config.Symbol)
-	wsURL := fmt.Sprintf("wss://stream.binance.com:9443/stream?streams=%s@depth", symbol)
+	wsURL := fmt.Sprintf("wss://stream.binance.com:9443/stream?streams=%s@depth/%s@aggTrade", symbol, symbol)
 	restURL := fmt.Sprintf("https://api.binance.com/api/v3/depth?symbol=%s&limit=5000", strings.ToUpper(config.Symbol))
+	infoURL := fmt.Sprintf("https://api.binance.com/api/v3/exchangeInfo?symbol=%s", strings.ToUpper(config.Symbol))
 
 	ex := &SpotExchange{
 		symbol:     config.Symbol,
-		wsURL:      wsURL,
 		restURL:    restURL,
+		infoURL:    infoURL,
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
+		tradeChan:  make(chan *exchange.Trade, 1000),
 		ctx:        ctx,
 		cancel:     cancel,
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:      ex.GetName(),
+		URL:       wsURL,
+		Resync:    ex.resync,
+		OnMessage: ex.handleMessage,
 	})
 
 	return ex
@@ -65,54 +66,20 @@ func (e *SpotExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Binance Spot
+// Connect establishes WebSocket connection to Binance Spot; the supervisor reconnects it if it drops
 func (e *SpotExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
-
-	go e.readMessages()
-
-	return nil
+	return e.ws.Start(ctx)
 }
 
 // Close closes the WebSocket connection
 func (e *SpotExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
-
-	if e.wsConn != nil {
-		select {
-		case <-e.done:
-		default:
-			close(e.done)
-		}
-
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
+	e.cancel()
+	err := e.ws.Close()
 
-		e.updateConnectionStatus(false)
-		return e.wsConn.Close()
-	}
-	return nil
+	// The read goroutine has exited, so nothing sends on the channels anymore
+	close(e.updateChan)
+	close(e.tradeChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via REST API
@@ -127,14 +94,14 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var binanceSnapshot SnapshotResponse
 	if err := json.NewDecoder(resp.Body).Decode(&binanceSnapshot); err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
 	}
 
@@ -142,59 +109,122 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	return snapshot, nil
 }
 
+// GetInstrument fetches the symbol's trading rules via REST API
+func (e *SpotExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.infoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var info ExchangeInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode exchange info: %w", err)
+	}
+
+	symbol, err := findSymbol(&info, e.symbol)
+	if err != nil {
+		return nil, err
+	}
+	return symbol.toInstrument(e.GetName(), exchange.InstrumentSpot)
+}
+
 // Updates returns a channel that receives depth updates
 func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
 }
 
+// Trades returns a channel that receives executed trades
+func (e *SpotExchange) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
 // IsConnected checks if the WebSocket connection is active
 func (e *SpotExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *SpotExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *SpotExchange) readMessages() {
-	defer close(e.updateChan)
-	defer e.updateConnectionStatus(false)
+// handleMessage processes a combined-stream message
+func (e *SpotExchange) handleMessage(_ int, message []byte) {
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse message: %v", e.GetName(), err)
+		return
+	}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
+
+	// Combined stream: trades are forwarded separately from depth
+	if strings.HasSuffix(msg.Stream, "@aggTrade") {
+		var trade AggTrade
+		if err := json.Unmarshal(msg.Data, &trade); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to parse trade: %v", e.GetName(), err)
 			return
-		default:
-			var msg WSMessage
-			if err := e.wsConn.ReadJSON(&msg); err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				return
-			}
-
-			e.incrementMessageCount()
-			e.updateLastPing()
-
-			canonicalUpdate := e.convertDepthUpdate(&msg.Data)
-
-			select {
-			case e.updateChan <- canonicalUpdate:
-			case <-e.ctx.Done():
-				return
-			case <-e.done:
-				return
-			default:
-				log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-			}
 		}
+		e.sendTrade(e.convertTrade(&trade))
+		return
+	}
+
+	var depth DepthUpdate
+	if err := json.Unmarshal(msg.Data, &depth); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse depth update: %v", e.GetName(), err)
+		return
+	}
+	if depth.FinalUpdateID <= e.resyncID {
+		return
+	}
+
+	e.sendUpdate(e.convertDepthUpdate(&depth))
+}
+
+// resync replaces the downstream book with a fresh REST snapshot after a reconnect, since depth
+// events were missed while disconnected. Events the snapshot already covers are skipped.
+func (e *SpotExchange) resync() error {
+	ctx, cancel := context.WithTimeout(e.ctx, 15*time.Second)
+	defer cancel()
+
+	snapshot, err := e.GetSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	e.resyncID = snapshot.LastUpdateID
+
+	e.sendUpdate(&exchange.DepthUpdate{
+		Exchange:      e.GetName(),
+		Symbol:        snapshot.Symbol,
+		EventTime:     snapshot.Timestamp,
+		FirstUpdateID: snapshot.LastUpdateID,
+		FinalUpdateID: snapshot.LastUpdateID,
+		Bids:          snapshot.Bids,
+		Asks:          snapshot.Asks,
+		IsSnapshot:    true,
+	})
+	return nil
+}
+
+// sendUpdate forwards an update without blocking
+func (e *SpotExchange) sendUpdate(update *exchange.DepthUpdate) {
+	select {
+	case e.updateChan <- update:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 	}
 }
 
@@ -256,34 +286,29 @@ func (e *SpotExchange) convertDepthUpdate(update *DepthUpdate) *exchange.DepthUp
 	}
 }
 
-// updateConnectionStatus updates the connection status in health
-func (e *SpotExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
+// convertTrade converts a Binance aggregate trade to canonical format
+func (e *SpotExchange) convertTrade(trade *AggTrade) *exchange.Trade {
+	// Buyer is the maker when the seller was the aggressor
+	side := exchange.TradeSideBuy
+	if trade.IsBuyerMaker {
+		side = exchange.TradeSideSell
 	}
-	e.health.Store(status)
-}
 
-// incrementMessageCount increments the message count in health
-func (e *SpotExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *SpotExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.Symbol,
+		TradeID:   strconv.FormatInt(trade.AggTradeID, 10),
+		Price:     trade.Price,
+		Quantity:  trade.Quantity,
+		Side:      side,
+		Timestamp: time.UnixMilli(trade.TradeTime),
+	}
 }
 
-// updateLastPing updates the last ping time in health
-func (e *SpotExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
+// sendTrade forwards a trade without blocking; trades are dropped if no one is consuming them
+func (e *SpotExchange) sendTrade(trade *exchange.Trade) {
+	select {
+	case e.tradeChan <- trade:
+	default:
+	}
 }
@@ -1,5 +1,7 @@
 package binance
 
+import "encoding/json"
+
 // SnapshotResponse represents the REST API response for Binance order book snapshot
 type SnapshotResponse struct {
 	LastUpdateID int64      `json:"lastUpdateId"`
@@ -7,10 +9,11 @@ type SnapshotResponse struct {
 	Asks         [][]string `json:"asks"`
 }
 
-// WSMessage represents a WebSocket message from Binance
+// WSMessage represents a combined-stream WebSocket message from Binance
+// Data is a DepthUpdate or an AggTrade depending on the stream name
 type WSMessage struct {
-	Stream string      `json:"stream"`
-	Data   DepthUpdate `json:"data"`
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
 }
 
 // DepthUpdate represents a depth update event from Binance WebSocket
@@ -24,3 +27,54 @@ type DepthUpdate struct {
 	Bids          [][]string `json:"b"`
 	Asks          [][]string `json:"a"`
 }
+
+// AggTrade represents an aggregate trade event from Binance WebSocket
+type AggTrade struct {
+	EventType    string `json:"e"`
+	EventTime    int64  `json:"E"`
+	Symbol       string `json:"s"`
+	AggTradeID   int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	TradeTime    int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+}
+
+// PremiumIndexResponse represents the REST API response for Binance Futures mark price and funding rate
+type PremiumIndexResponse struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// OpenInterestResponse represents the REST API response for Binance Futures open interest
+type OpenInterestResponse struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+// ExchangeInfoResponse represents the REST API response for Binance exchange (trading rules) info
+type ExchangeInfoResponse struct {
+	Symbols []SymbolInfo `json:"symbols"`
+}
+
+// SymbolInfo represents the trading rules of a single Binance symbol
+type SymbolInfo struct {
+	Symbol     string         `json:"symbol"`
+	BaseAsset  string         `json:"baseAsset"`
+	QuoteAsset string         `json:"quoteAsset"`
+	Filters    []SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter represents one entry of a symbol's filters; only the fields of the filter type are set
+type SymbolFilter struct {
+	FilterType  string `json:"filterType"`
+	TickSize    string `json:"tickSize"`    // PRICE_FILTER
+	StepSize    string `json:"stepSize"`    // LOT_SIZE
+	MinNotional string `json:"minNotional"` // NOTIONAL / MIN_NOTIONAL (spot)
+	Notional    string `json:"notional"`    // MIN_NOTIONAL (futures)
+}
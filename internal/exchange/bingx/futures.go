@@ -5,58 +5,53 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
 )
 
 const (
-	futuresWsURL = "wss://open-api-swap.bingx.com/swap-market"
+	futuresWsURL   = "wss://open-api-swap.bingx.com/swap-market"
+	futuresRestURL = "https://open-api.bingx.com/openApi/swap/v2/quote"
 )
 
 // FuturesExchange implements the Exchange interface for BingX Perpetual Futures
 type FuturesExchange struct {
-	symbol         string
-	bingxSymbol    string // BingX format (e.g., BTC-USDT)
-	wsConn         *websocket.Conn
-	updateChan     chan *exchange.DepthUpdate
-	done           chan struct{}
-	ctx            context.Context
-	cancel         context.CancelFunc
-	health         atomic.Value
-	snapshotMutex  sync.Mutex
-	snapshot       *exchange.Snapshot
-	snapshotReady  chan struct{}
-	hasSnapshot    bool
+	symbol        string // BingX format (e.g., BTC-USDT)
+	ws            *supervisor.Supervisor
+	updateChan    chan *exchange.DepthUpdate
+	snapshotMutex sync.Mutex
+	snapshot      *exchange.Snapshot
+	snapshotReady chan struct{}
+	hasSnapshot   bool
+	resyncing     bool // Waiting for a fresh snapshot after a reconnect (read goroutine only)
 }
 
 // NewFuturesExchange creates a new BingX Futures exchange instance
 func NewFuturesExchange(config Config) *FuturesExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	bingxSymbol := convertToBingXSymbol(config.Symbol)
-
 	ex := &FuturesExchange{
 		symbol:        config.Symbol,
-		bingxSymbol:   bingxSymbol,
 		updateChan:    make(chan *exchange.DepthUpdate, 1000),
-		done:          make(chan struct{}),
-		ctx:           ctx,
-		cancel:        cancel,
 		snapshotReady: make(chan struct{}),
 		hasSnapshot:   false,
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	// BingX pings us with a text message, answered in handleMessage
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:       ex.GetName(),
+		URL:        futuresWsURL,
+		Header:     http.Header{"Accept-Encoding": {"gzip"}},
+		Subscribe:  ex.subscribe,
+		Resync:     ex.awaitSnapshot,
+		OnMessage:  ex.onMessage,
+		MinBackoff: 5 * time.Second,
 	})
 
 	return ex
@@ -72,74 +67,41 @@ func (e *FuturesExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to BingX Futures
+// Connect establishes WebSocket connection to BingX Futures; the supervisor reconnects it if it drops
 func (e *FuturesExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	// Add gzip compression support
-	header := map[string][]string{
-		"Accept-Encoding": {"gzip"},
-	}
-
-	conn, _, err := dialer.DialContext(ctx, futuresWsURL, header)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+	return e.ws.Start(ctx)
+}
 
-	// Subscribe to incremental depth
+// subscribe subscribes to incremental depth; it runs on every connect
+func (e *FuturesExchange) subscribe() error {
 	subMsg := SubscriptionMessage{
 		ID:       uuid.New().String(),
 		ReqType:  "sub",
-		DataType: fmt.Sprintf("%s@incrDepth", e.bingxSymbol),
+		DataType: fmt.Sprintf("%s@incrDepth", e.symbol),
 	}
 
-	if err := conn.WriteJSON(subMsg); err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("failed to subscribe: %w", err)
+	if err := e.ws.WriteJSON(subMsg); err != nil {
+		return err
 	}
 
 	log.Printf("[%s] Subscribed to %s", e.GetName(), subMsg.DataType)
+	return nil
+}
 
-	go e.readMessages()
-	go e.pingLoop()
-
+// awaitSnapshot runs after a reconnect. Updates are dropped until the new subscription's
+// full depth replaces the downstream book.
+func (e *FuturesExchange) awaitSnapshot() error {
+	e.resyncing = true
 	return nil
 }
 
 // Close closes the WebSocket connection
 func (e *FuturesExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
+	err := e.ws.Close()
 
-	if e.wsConn != nil {
-		select {
-		case <-e.done:
-		default:
-			close(e.done)
-		}
-
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		return e.wsConn.Close()
-	}
-	return nil
+	// The read goroutine has exited, so nothing sends on the channel anymore
+	close(e.updateChan)
+	return err
 }
 
 // GetSnapshot waits for and returns the initial orderbook snapshot from WebSocket
@@ -159,6 +121,106 @@ func (e *FuturesExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot,
 	}
 }
 
+// GetDerivativesInfo fetches funding, mark/index price and open interest via REST API
+func (e *FuturesExchange) GetDerivativesInfo(ctx context.Context) (*exchange.DerivativesInfo, error) {
+	var premium PremiumIndexData
+	if err := e.fetchQuote(ctx, "premiumIndex", &premium); err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %w", err)
+	}
+
+	var openInterest OpenInterestData
+	if err := e.fetchQuote(ctx, "openInterest", &openInterest); err != nil {
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
+	}
+
+	// BingX reports open interest as USDT notional; convert to base units at the mark price
+	markPrice, err := decimal.NewFromString(premium.MarkPrice.String())
+	if err != nil || !markPrice.IsPositive() {
+		return nil, fmt.Errorf("invalid mark price %q", premium.MarkPrice)
+	}
+	notional, err := decimal.NewFromString(openInterest.OpenInterest.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid open interest %q: %w", openInterest.OpenInterest, err)
+	}
+
+	return &exchange.DerivativesInfo{
+		Exchange:        e.GetName(),
+		Symbol:          e.symbol,
+		FundingRate:     premium.LastFundingRate.String(),
+		NextFundingTime: time.UnixMilli(premium.NextFundingTime),
+		MarkPrice:       markPrice.String(),
+		IndexPrice:      premium.IndexPrice.String(),
+		OpenInterest:    notional.Div(markPrice).StringFixed(4),
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// GetInstrument fetches the contract's trading rules via REST API
+func (e *FuturesExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	var contracts []ContractData
+	if err := e.fetchQuote(ctx, "contracts", &contracts); err != nil {
+		return nil, fmt.Errorf("failed to get contracts: %w", err)
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no contract returned for %s", e.symbol)
+	}
+
+	// BingX publishes precisions rather than increments; book sizes are in base units
+	contract := contracts[0]
+	inst := &exchange.Instrument{
+		Exchange:           e.GetName(),
+		Symbol:             contract.Symbol,
+		Type:               exchange.InstrumentLinearPerp,
+		BaseAsset:          contract.Asset,
+		QuoteAsset:         contract.Currency,
+		TickSize:           decimal.New(1, -contract.PricePrecision),
+		LotSize:            decimal.New(1, -contract.QuantityPrecision),
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}
+	if contract.TradeMinUSDT != "" {
+		minNotional, err := decimal.NewFromString(contract.TradeMinUSDT.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum notional %q: %w", contract.TradeMinUSDT, err)
+		}
+		inst.MinNotional = minNotional
+	}
+	return inst, nil
+}
+
+// fetchQuote calls a swap quote endpoint for this symbol and decodes its data field into v
+func (e *FuturesExchange) fetchQuote(ctx context.Context, endpoint string, v interface{}) error {
+	url := fmt.Sprintf("%s/%s?symbol=%s", futuresRestURL, endpoint, e.symbol)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return err
+	}
+	defer resp.Body.Close()
+
+	var quote QuoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		e.ws.IncrementErrorCount()
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if quote.Code != 0 {
+		e.ws.IncrementErrorCount()
+		return fmt.Errorf("request failed with code %d: %s", quote.Code, quote.Msg)
+	}
+
+	if err := json.Unmarshal(quote.Data, v); err != nil {
+		e.ws.IncrementErrorCount()
+		return fmt.Errorf("failed to decode data: %w", err)
+	}
+	return nil
+}
+
 // Updates returns a channel that receives depth updates
 func (e *FuturesExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
@@ -166,61 +228,18 @@ func (e *FuturesExchange) Updates() <-chan *exchange.DepthUpdate {
 
 // IsConnected checks if the WebSocket connection is active
 func (e *FuturesExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *FuturesExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
-}
-
-// pingLoop sends periodic pings (not needed for BingX, they send pings to us)
-// But we keep the goroutine structure for consistency
-func (e *FuturesExchange) pingLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-e.done:
-			return
-		case <-ticker.C:
-			// BingX sends pings to us, we just respond with pong
-			// This is just a keepalive check
-			continue
-		}
-	}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *FuturesExchange) readMessages() {
-	defer close(e.updateChan)
-	defer e.updateConnectionStatus(false)
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			messageType, message, err := e.wsConn.ReadMessage()
-			if err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				return
-			}
-
-			if err := e.handleMessage(messageType, message); err != nil {
-				log.Printf("[%s] Error handling message: %v", e.GetName(), err)
-			}
-		}
+// onMessage handles a message read by the supervisor
+func (e *FuturesExchange) onMessage(messageType int, message []byte) {
+	if err := e.handleMessage(messageType, message); err != nil {
+		log.Printf("[%s] Error handling message: %v", e.GetName(), err)
 	}
 }
 
@@ -234,7 +253,7 @@ func (e *FuturesExchange) handleMessage(messageType int, message []byte) error {
 		// Decompress gzip
 		decoded, err := decodeGzip(message)
 		if err != nil {
-			e.incrementErrorCount()
+			e.ws.IncrementErrorCount()
 			return fmt.Errorf("failed to decode gzip: %w", err)
 		}
 		decodedMsg = decoded
@@ -246,7 +265,7 @@ func (e *FuturesExchange) handleMessage(messageType int, message []byte) error {
 	lowerMsg := strings.ToLower(decodedMsg)
 	if strings.Contains(lowerMsg, "ping") || lowerMsg == "ping" {
 		// Respond with "Pong" (capitalized as per BingX futures docs)
-		if err := e.wsConn.WriteMessage(websocket.TextMessage, []byte("Pong")); err != nil {
+		if err := e.ws.WriteMessage(websocket.TextMessage, []byte("Pong")); err != nil {
 			log.Printf("[%s] Failed to send Pong: %v", e.GetName(), err)
 		}
 		return nil
@@ -273,47 +292,57 @@ func (e *FuturesExchange) handleMessage(messageType int, message []byte) error {
 		e.handleUpdate(&msg)
 	}
 
-	e.incrementMessageCount()
-	e.updateLastPing()
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
 
 	return nil
 }
 
-// handleSnapshot processes the initial full depth snapshot
+// handleSnapshot stores a full depth snapshot. The first one is served by GetSnapshot;
+// later ones, sent after a reconnect, replace the whole downstream book.
 func (e *FuturesExchange) handleSnapshot(msg *FuturesWSMessage) {
 	e.snapshotMutex.Lock()
 	defer e.snapshotMutex.Unlock()
 
+	snapshot := e.convertSnapshot(&msg.Data)
+	e.snapshot = snapshot
+	e.resyncing = false
+
 	if e.hasSnapshot {
-		// Already have snapshot, treat as update
+		e.sendUpdate(&exchange.DepthUpdate{
+			Exchange:      e.GetName(),
+			Symbol:        snapshot.Symbol,
+			EventTime:     snapshot.Timestamp,
+			FirstUpdateID: snapshot.LastUpdateID,
+			FinalUpdateID: snapshot.LastUpdateID,
+			Bids:          snapshot.Bids,
+			Asks:          snapshot.Asks,
+			IsSnapshot:    true,
+		})
 		return
 	}
-
-	snapshot := e.convertSnapshot(&msg.Data)
-	e.snapshot = snapshot
 	e.hasSnapshot = true
 
 	log.Printf("[%s] Received initial snapshot with lastUpdateId=%d, bids=%d, asks=%d",
 		e.GetName(), snapshot.LastUpdateID, len(snapshot.Bids), len(snapshot.Asks))
 
 	// Signal that snapshot is ready
-	select {
-	case <-e.snapshotReady:
-	default:
-		close(e.snapshotReady)
-	}
+	close(e.snapshotReady)
 }
 
 // handleUpdate processes incremental depth updates
 func (e *FuturesExchange) handleUpdate(msg *FuturesWSMessage) {
-	canonicalUpdate := e.convertDepthUpdate(&msg.Data)
+	if e.resyncing {
+		// Updates until the fresh snapshot would apply to a book with a hole in it
+		return
+	}
+	e.sendUpdate(e.convertDepthUpdate(&msg.Data))
+}
 
+// sendUpdate forwards an update downstream without blocking
+func (e *FuturesExchange) sendUpdate(update *exchange.DepthUpdate) {
 	select {
-	case e.updateChan <- canonicalUpdate:
-	case <-e.ctx.Done():
-		return
-	case <-e.done:
-		return
+	case e.updateChan <- update:
 	default:
 		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 	}
@@ -384,35 +413,3 @@ func (e *FuturesExchange) convertDepthUpdate(data *FuturesDepthData) *exchange.D
 		Asks:          asks,
 	}
 }
-
-// updateConnectionStatus updates the connection status in health
-func (e *FuturesExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
-	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *FuturesExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *FuturesExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *FuturesExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
-}
@@ -8,60 +8,53 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
 )
 
 const (
-	wsURL = "wss://open-api-ws.bingx.com/market"
+	wsURL      = "wss://open-api-ws.bingx.com/market"
+	symbolsURL = "https://open-api.bingx.com/openApi/spot/v1/common/symbols"
 )
 
 // SpotExchange implements the Exchange interface for BingX Spot
 type SpotExchange struct {
-	symbol         string
-	bingxSymbol    string // BingX format (e.g., BTC-USDT)
-	wsConn         *websocket.Conn
-	wsConnMu       sync.Mutex // Protects wsConn for concurrent writes
-	updateChan     chan *exchange.DepthUpdate
-	done           chan struct{}
-	ctx            context.Context
-	cancel         context.CancelFunc
-	health         atomic.Value
-	snapshotMutex  sync.Mutex
-	snapshot       *exchange.Snapshot
-	snapshotReady  chan struct{}
-	hasSnapshot    bool
-	reconnecting   atomic.Bool // Flag to prevent multiple reconnection attempts
+	symbol        string // BingX format (e.g., BTC-USDT)
+	ws            *supervisor.Supervisor
+	updateChan    chan *exchange.DepthUpdate
+	snapshotMutex sync.Mutex
+	snapshot      *exchange.Snapshot
+	snapshotReady chan struct{}
+	hasSnapshot   bool
+	resyncing     bool // Waiting for a fresh snapshot after a reconnect (read goroutine only)
 }
 
 // NewSpotExchange creates a new BingX Spot exchange instance
 func NewSpotExchange(config Config) *SpotExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	bingxSymbol := convertToBingXSymbol(config.Symbol)
-
 	ex := &SpotExchange{
 		symbol:        config.Symbol,
-		bingxSymbol:   bingxSymbol,
 		updateChan:    make(chan *exchange.DepthUpdate, 5000),
-		done:          make(chan struct{}),
-		ctx:           ctx,
-		cancel:        cancel,
 		snapshotReady: make(chan struct{}),
 		hasSnapshot:   false,
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	// BingX pings us with a text message, answered in handleMessage
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:       ex.GetName(),
+		URL:        wsURL,
+		Header:     http.Header{"Accept-Encoding": {"gzip"}},
+		Subscribe:  ex.subscribe,
+		Resync:     ex.awaitSnapshot,
+		OnMessage:  ex.onMessage,
+		MinBackoff: 5 * time.Second,
 	})
 
 	return ex
@@ -77,79 +70,41 @@ func (e *SpotExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to BingX Spot
+// Connect establishes WebSocket connection to BingX Spot; the supervisor reconnects it if it drops
 func (e *SpotExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	// Add gzip compression support
-	header := map[string][]string{
-		"Accept-Encoding": {"gzip"},
-	}
-
-	conn, _, err := dialer.DialContext(ctx, wsURL, header)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+	return e.ws.Start(ctx)
+}
 
-	// Subscribe to incremental depth
+// subscribe subscribes to incremental depth; it runs on every connect
+func (e *SpotExchange) subscribe() error {
 	subMsg := SubscriptionMessage{
 		ID:       uuid.New().String(),
 		ReqType:  "sub",
-		DataType: fmt.Sprintf("%s@incrDepth", e.bingxSymbol),
+		DataType: fmt.Sprintf("%s@incrDepth", e.symbol),
 	}
 
-	if err := conn.WriteJSON(subMsg); err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("failed to subscribe: %w", err)
+	if err := e.ws.WriteJSON(subMsg); err != nil {
+		return err
 	}
 
 	log.Printf("[%s] Subscribed to %s", e.GetName(), subMsg.DataType)
+	return nil
+}
 
-	go e.readMessages()
-	go e.pingLoop()
-
+// awaitSnapshot runs after a reconnect. Updates are dropped until the new subscription's
+// full depth replaces the downstream book.
+func (e *SpotExchange) awaitSnapshot() error {
+	e.resyncing = true
 	return nil
 }
 
 // Close closes the WebSocket connection
 func (e *SpotExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
-
-	// Close the done channel to signal all goroutines to stop
-	select {
-	case <-e.done:
-	default:
-		close(e.done)
-	}
-
-	if e.wsConn != nil {
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		e.wsConn.Close()
-	}
+	err := e.ws.Close()
 
-	// Close update channel after all goroutines have stopped
+	// The read goroutine has exited, so nothing sends on the channel anymore
 	close(e.updateChan)
-
-	return nil
+	return err
 }
 
 // GetSnapshot waits for and returns the initial orderbook snapshot from WebSocket
@@ -169,6 +124,69 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	}
 }
 
+// GetInstrument fetches the pair's trading rules via REST API
+func (e *SpotExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	url := fmt.Sprintf("%s?symbol=%s", symbolsURL, e.symbol)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to get symbols: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var quote QuoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if quote.Code != 0 {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("request failed with code %d: %s", quote.Code, quote.Msg)
+	}
+
+	var data SpotSymbolsData
+	if err := json.Unmarshal(quote.Data, &data); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+	if len(data.Symbols) == 0 {
+		return nil, fmt.Errorf("no symbol returned for %s", e.symbol)
+	}
+
+	symbol := data.Symbols[0]
+	tick, err := decimal.NewFromString(symbol.TickSize.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid tick size %q: %w", symbol.TickSize, err)
+	}
+	lot, err := decimal.NewFromString(symbol.StepSize.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid step size %q: %w", symbol.StepSize, err)
+	}
+
+	inst := &exchange.Instrument{
+		Exchange:           e.GetName(),
+		Symbol:             e.symbol,
+		Type:               exchange.InstrumentSpot,
+		TickSize:           tick,
+		LotSize:            lot,
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}
+	inst.BaseAsset, inst.QuoteAsset, _ = strings.Cut(e.symbol, "-")
+	if symbol.MinNotional != "" {
+		if inst.MinNotional, err = decimal.NewFromString(symbol.MinNotional.String()); err != nil {
+			return nil, fmt.Errorf("invalid min notional %q: %w", symbol.MinNotional, err)
+		}
+	}
+	return inst, nil
+}
+
 // Updates returns a channel that receives depth updates
 func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
@@ -176,146 +194,18 @@ func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 
 // IsConnected checks if the WebSocket connection is active
 func (e *SpotExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *SpotExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// pingLoop monitors connection health (BingX sends pings to us, we respond with pong)
-func (e *SpotExchange) pingLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-e.done:
-			return
-		case <-ticker.C:
-			// BingX sends pings to us, we respond with pong (handled in handleMessage)
-			// We just monitor LastPing to detect stale connections
-			health := e.Health()
-			if !health.LastPing.IsZero() && time.Since(health.LastPing) > 60*time.Second {
-				log.Printf("[%s] No ping from server for 60s, connection may be stale", e.GetName())
-				go e.reconnect()
-				return
-			}
-		}
-	}
-}
-
-// reconnect attempts to re-establish the WebSocket connection
-func (e *SpotExchange) reconnect() {
-	// Prevent multiple simultaneous reconnection attempts
-	if !e.reconnecting.CompareAndSwap(false, true) {
-		log.Printf("[%s] Reconnection already in progress, skipping", e.GetName())
-		return
-	}
-	defer e.reconnecting.Store(false)
-
-	log.Printf("[%s] Starting reconnection...", e.GetName())
-
-	// Close existing connection if any
-	e.wsConnMu.Lock()
-	if e.wsConn != nil {
-		e.wsConn.Close()
-		e.wsConn = nil
-	}
-	e.wsConnMu.Unlock()
-
-	// Wait before reconnecting
-	time.Sleep(5 * time.Second)
-
-	// Attempt to reconnect
-	maxAttempts := 10
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled during reconnection", e.GetName())
-			return
-		case <-e.done:
-			log.Printf("[%s] Done signal received during reconnection", e.GetName())
-			return
-		default:
-			log.Printf("[%s] Reconnection attempt %d/%d", e.GetName(), attempt, maxAttempts)
-
-			// Create new context for this connection attempt
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			err := e.Connect(ctx)
-			cancel()
-
-			if err == nil {
-				log.Printf("[%s] Reconnection successful!", e.GetName())
-
-				// Reset snapshot flag to get a fresh snapshot
-				e.snapshotMutex.Lock()
-				e.hasSnapshot = false
-				e.snapshot = nil
-				e.snapshotMutex.Unlock()
-
-				// Signal that a new snapshot is needed
-				e.snapshotReady = make(chan struct{})
-
-				return
-			}
-
-			log.Printf("[%s] Reconnection attempt %d failed: %v", e.GetName(), attempt, err)
-
-			// Exponential backoff with max of 30 seconds
-			backoff := time.Duration(attempt) * 5 * time.Second
-			if backoff > 30*time.Second {
-				backoff = 30 * time.Second
-			}
-			time.Sleep(backoff)
-		}
-	}
-
-	log.Printf("[%s] Failed to reconnect after %d attempts, giving up", e.GetName(), maxAttempts)
-}
-
-// readMessages continuously reads WebSocket messages
-func (e *SpotExchange) readMessages() {
-	// Note: Don't close updateChan here since we may reconnect and reuse it
-	defer e.updateConnectionStatus(false)
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			e.wsConnMu.Lock()
-			conn := e.wsConn
-			e.wsConnMu.Unlock()
-
-			if conn == nil {
-				log.Printf("[%s] Connection is nil, triggering reconnection", e.GetName())
-				go e.reconnect()
-				return
-			}
-
-			messageType, message, err := conn.ReadMessage()
-			if err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				// Trigger reconnection instead of just returning
-				go e.reconnect()
-				return
-			}
-
-			if err := e.handleMessage(messageType, message); err != nil {
-				log.Printf("[%s] Error handling message: %v", e.GetName(), err)
-			}
-		}
+// onMessage handles a message read by the supervisor
+func (e *SpotExchange) onMessage(messageType int, message []byte) {
+	if err := e.handleMessage(messageType, message); err != nil {
+		log.Printf("[%s] Error handling message: %v", e.GetName(), err)
 	}
 }
 
@@ -329,7 +219,7 @@ func (e *SpotExchange) handleMessage(messageType int, message []byte) error {
 		// Decompress gzip
 		decoded, err := decodeGzip(message)
 		if err != nil {
-			e.incrementErrorCount()
+			e.ws.IncrementErrorCount()
 			return fmt.Errorf("failed to decode gzip: %w", err)
 		}
 		decodedMsg = decoded
@@ -339,7 +229,7 @@ func (e *SpotExchange) handleMessage(messageType int, message []byte) error {
 
 	// Handle ping/pong
 	if strings.Contains(decodedMsg, "ping") || decodedMsg == "ping" {
-		if err := e.wsConn.WriteMessage(websocket.TextMessage, []byte("pong")); err != nil {
+		if err := e.ws.WriteMessage(websocket.TextMessage, []byte("pong")); err != nil {
 			log.Printf("[%s] Failed to send pong: %v", e.GetName(), err)
 		}
 		return nil
@@ -366,47 +256,57 @@ func (e *SpotExchange) handleMessage(messageType int, message []byte) error {
 		e.handleUpdate(&msg)
 	}
 
-	e.incrementMessageCount()
-	e.updateLastPing()
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
 
 	return nil
 }
 
-// handleSnapshot processes the initial full depth snapshot
+// handleSnapshot stores a full depth snapshot. The first one is served by GetSnapshot;
+// later ones, sent after a reconnect, replace the whole downstream book.
 func (e *SpotExchange) handleSnapshot(msg *WSMessage) {
 	e.snapshotMutex.Lock()
 	defer e.snapshotMutex.Unlock()
 
+	snapshot := e.convertSnapshot(&msg.Data)
+	e.snapshot = snapshot
+	e.resyncing = false
+
 	if e.hasSnapshot {
-		// Already have snapshot, treat as update
+		e.sendUpdate(&exchange.DepthUpdate{
+			Exchange:      e.GetName(),
+			Symbol:        snapshot.Symbol,
+			EventTime:     snapshot.Timestamp,
+			FirstUpdateID: snapshot.LastUpdateID,
+			FinalUpdateID: snapshot.LastUpdateID,
+			Bids:          snapshot.Bids,
+			Asks:          snapshot.Asks,
+			IsSnapshot:    true,
+		})
 		return
 	}
-
-	snapshot := e.convertSnapshot(&msg.Data)
-	e.snapshot = snapshot
 	e.hasSnapshot = true
 
 	log.Printf("[%s] Received initial snapshot with lastUpdateId=%d, bids=%d, asks=%d",
 		e.GetName(), snapshot.LastUpdateID, len(snapshot.Bids), len(snapshot.Asks))
 
 	// Signal that snapshot is ready
-	select {
-	case <-e.snapshotReady:
-	default:
-		close(e.snapshotReady)
-	}
+	close(e.snapshotReady)
 }
 
 // handleUpdate processes incremental depth updates
 func (e *SpotExchange) handleUpdate(msg *WSMessage) {
-	canonicalUpdate := e.convertDepthUpdate(&msg.Data)
+	if e.resyncing {
+		// Updates until the fresh snapshot would apply to a book with a hole in it
+		return
+	}
+	e.sendUpdate(e.convertDepthUpdate(&msg.Data))
+}
 
+// sendUpdate forwards an update downstream without blocking
+func (e *SpotExchange) sendUpdate(update *exchange.DepthUpdate) {
 	select {
-	case e.updateChan <- canonicalUpdate:
-	case <-e.ctx.Done():
-		return
-	case <-e.done:
-		return
+	case e.updateChan <- update:
 	default:
 		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 	}
@@ -486,66 +386,6 @@ func decodeGzip(data []byte) (string, error) {
 	return string(decodedMsg), nil
 }
 
-// convertToBingXSymbol converts various symbol formats to BingX format
-// Examples: BTCUSDT -> BTC-USDT, BTC-USDT -> BTC-USDT
-func convertToBingXSymbol(symbol string) string {
-	if strings.Contains(symbol, "-") {
-		return strings.ToUpper(symbol)
-	}
-
-	symbol = strings.ToUpper(symbol)
-
-	if strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USDT")
-		return fmt.Sprintf("%s-USDT", base)
-	}
-
-	if strings.HasSuffix(symbol, "USD") && !strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USD")
-		return fmt.Sprintf("%s-USD", base)
-	}
-
-	if strings.HasSuffix(symbol, "USDC") {
-		base := strings.TrimSuffix(symbol, "USDC")
-		return fmt.Sprintf("%s-USDC", base)
-	}
-
-	log.Printf("[BingX] Warning: Could not convert symbol %s to BingX format, using as-is", symbol)
-	return symbol
-}
-
-// updateConnectionStatus updates the connection status in health
-func (e *SpotExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
-	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *SpotExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *SpotExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *SpotExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
-}
-
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -1,8 +1,10 @@
 package bingx
 
+import "encoding/json"
+
 // Config holds configuration for BingX exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTC-USDT), as resolved by the symbols package
 }
 
 // SubscriptionMessage represents the subscription request to BingX WebSocket
@@ -15,11 +17,11 @@ type SubscriptionMessage struct {
 // WSMessage represents a WebSocket message from BingX
 // BingX sends messages as either text or binary (gzip compressed)
 type WSMessage struct {
-	Code       int          `json:"code,omitempty"`
-	Msg        string       `json:"msg,omitempty"`
-	DataType   string       `json:"dataType,omitempty"`
-	Data       DepthData    `json:"data,omitempty"`
-	Timestamp  int64        `json:"ts,omitempty"`
+	Code      int       `json:"code,omitempty"`
+	Msg       string    `json:"msg,omitempty"`
+	DataType  string    `json:"dataType,omitempty"`
+	Data      DepthData `json:"data,omitempty"`
+	Timestamp int64     `json:"ts,omitempty"`
 }
 
 // DepthData represents the depth update data from BingX Spot (map format)
@@ -41,11 +43,11 @@ type FuturesDepthData struct {
 
 // FuturesWSMessage represents a WebSocket message from BingX Futures
 type FuturesWSMessage struct {
-	Code       int              `json:"code,omitempty"`
-	Msg        string           `json:"msg,omitempty"`
-	DataType   string           `json:"dataType,omitempty"`
-	Data       FuturesDepthData `json:"data,omitempty"`
-	Timestamp  int64            `json:"ts,omitempty"`
+	Code      int              `json:"code,omitempty"`
+	Msg       string           `json:"msg,omitempty"`
+	DataType  string           `json:"dataType,omitempty"`
+	Data      FuturesDepthData `json:"data,omitempty"`
+	Timestamp int64            `json:"ts,omitempty"`
 }
 
 // PingMessage represents a ping message to BingX
@@ -57,3 +59,50 @@ type PingMessage struct {
 type PongMessage struct {
 	Pong int64 `json:"Pong"`
 }
+
+// QuoteResponse represents a REST API response from the BingX swap quote endpoints
+type QuoteResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// PremiumIndexData represents mark price and funding data from BingX Futures
+// Numeric fields use json.Number because BingX returns them as either strings or numbers
+type PremiumIndexData struct {
+	Symbol          string      `json:"symbol"`
+	MarkPrice       json.Number `json:"markPrice"`
+	IndexPrice      json.Number `json:"indexPrice"`
+	LastFundingRate json.Number `json:"lastFundingRate"`
+	NextFundingTime int64       `json:"nextFundingTime"`
+}
+
+// OpenInterestData represents open interest data from BingX Futures
+type OpenInterestData struct {
+	Symbol       string      `json:"symbol"`
+	OpenInterest json.Number `json:"openInterest"` // USDT notional
+	Time         int64       `json:"time"`
+}
+
+// SpotSymbolsData represents the data field of the BingX spot symbols response
+type SpotSymbolsData struct {
+	Symbols []SpotSymbol `json:"symbols"`
+}
+
+// SpotSymbol represents the trading rules of a BingX spot pair
+type SpotSymbol struct {
+	Symbol      string      `json:"symbol"`
+	TickSize    json.Number `json:"tickSize"`
+	StepSize    json.Number `json:"stepSize"`
+	MinNotional json.Number `json:"minNotional"`
+}
+
+// ContractData represents the trading rules of a BingX perpetual contract
+type ContractData struct {
+	Symbol            string      `json:"symbol"`
+	Asset             string      `json:"asset"`    // Base asset
+	Currency          string      `json:"currency"` // Quote (settlement) asset
+	PricePrecision    int32       `json:"pricePrecision"`
+	QuantityPrecision int32       `json:"quantityPrecision"`
+	TradeMinUSDT      json.Number `json:"tradeMinUSDT"`
+}
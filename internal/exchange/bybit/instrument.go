@@ -0,0 +1,77 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
+
+	"github.com/shopspring/decimal"
+)
+
+// fetchInstrument looks up a symbol's trading rules in the given category (spot or linear)
+func fetchInstrument(ctx context.Context, ws *supervisor.Supervisor, category, symbol string) (*InstrumentInfo, error) {
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=%s&symbol=%s", category, symbol)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to get instruments info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var info InstrumentsInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode instruments info: %w", err)
+	}
+
+	if info.RetCode != 0 {
+		ws.IncrementErrorCount()
+		return nil, fmt.Errorf("instruments info request failed: %s", info.RetMsg)
+	}
+	if len(info.Result.List) == 0 {
+		return nil, fmt.Errorf("no instrument returned for %s", symbol)
+	}
+	return &info.Result.List[0], nil
+}
+
+// toInstrument converts Bybit trading rules into the canonical instrument. Bybit quotes book
+// sizes in base units on both spot and linear contracts.
+func (i *InstrumentInfo) toInstrument(name exchange.ExchangeName, instType exchange.InstrumentType, lotSize, minNotional string) (*exchange.Instrument, error) {
+	tick, err := decimal.NewFromString(i.PriceFilter.TickSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tick size %q: %w", i.PriceFilter.TickSize, err)
+	}
+	lot, err := decimal.NewFromString(lotSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lot size %q: %w", lotSize, err)
+	}
+
+	inst := &exchange.Instrument{
+		Exchange:           name,
+		Symbol:             i.Symbol,
+		Type:               instType,
+		BaseAsset:          i.BaseCoin,
+		QuoteAsset:         i.QuoteCoin,
+		TickSize:           tick,
+		LotSize:            lot,
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}
+	if minNotional != "" {
+		if inst.MinNotional, err = decimal.NewFromString(minNotional); err != nil {
+			return nil, fmt.Errorf("invalid min notional %q: %w", minNotional, err)
+		}
+	}
+	return inst, nil
+}
@@ -2,27 +2,23 @@ package bybit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"orderbook/internal/exchange"
-
-	"github.com/gorilla/websocket"
+	"orderbook/internal/exchange/supervisor"
 )
 
 // SpotExchange implements the Exchange interface for Bybit Spot
 type SpotExchange struct {
 	symbol           string
-	wsURL            string
-	wsConn           *websocket.Conn
+	ws               *supervisor.Supervisor
 	updateChan       chan *exchange.DepthUpdate
-	done             chan struct{}
-	ctx              context.Context
-	cancel           context.CancelFunc
-	health           atomic.Value // stores exchange.HealthStatus
+	tradeChan        chan *exchange.Trade
 	snapshotReceived bool
 	lastSeq          int64
 	snapshot         *exchange.Snapshot
@@ -31,24 +27,20 @@ type SpotExchange struct {
 
 // NewSpotExchange creates a new Bybit Spot exchange instance
 func NewSpotExchange(config Config) *SpotExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	wsURL := "wss://stream.bybit.com/v5/public/spot"
 
 	ex := &SpotExchange{
 		symbol:     config.Symbol,
-		wsURL:      wsURL,
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
-		ctx:        ctx,
-		cancel:     cancel,
+		tradeChan:  make(chan *exchange.Trade, 1000),
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:      ex.GetName(),
+		URL:       wsURL,
+		Subscribe: ex.subscribe,
+		OnMessage: ex.handleMessage,
+		Ping:      ex.ping,
 	})
 
 	return ex
@@ -64,67 +56,42 @@ func (e *SpotExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Bybit Spot
+// Connect establishes WebSocket connection to Bybit Spot; the supervisor reconnects it if it drops
 func (e *SpotExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+	return e.ws.Start(ctx)
+}
 
+// subscribe subscribes to the orderbook and trade streams; it runs on every connect
+func (e *SpotExchange) subscribe() error {
 	subscribeMsg := SubscribeMessage{
-		Op:   "subscribe",
-		Args: []string{fmt.Sprintf("orderbook.1000.%s", e.symbol)},
+		Op: "subscribe",
+		Args: []string{
+			fmt.Sprintf("orderbook.1000.%s", e.symbol),
+			fmt.Sprintf("publicTrade.%s", e.symbol),
+		},
 	}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
-		e.incrementErrorCount()
-		conn.Close()
-		return fmt.Errorf("failed to subscribe: %w", err)
+	if err := e.ws.WriteJSON(subscribeMsg); err != nil {
+		return err
 	}
 
-	log.Printf("[%s] Subscribed to orderbook.1000.%s", e.GetName(), e.symbol)
-
-	go e.readMessages()
-
+	log.Printf("[%s] Subscribed to orderbook.1000.%s and publicTrade.%s", e.GetName(), e.symbol, e.symbol)
 	return nil
 }
 
+// ping sends Bybit's application-level heartbeat, without which it drops the connection
+func (e *SpotExchange) ping() error {
+	return e.ws.WriteJSON(SubscribeMessage{Op: "ping"})
+}
+
 // Close closes the WebSocket connection
 func (e *SpotExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
+	err := e.ws.Close()
 
-	if e.wsConn != nil {
-		select {
-		case <-e.done:
-		default:
-			close(e.done)
-		}
-
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		return e.wsConn.Close()
-	}
-	return nil
+	// The read goroutine has exited, so nothing sends on the channels anymore
+	close(e.updateChan)
+	close(e.tradeChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via WebSocket
@@ -153,72 +120,87 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	}
 }
 
+// GetInstrument fetches the pair's trading rules via REST API
+func (e *SpotExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	info, err := fetchInstrument(ctx, e.ws, "spot", e.symbol)
+	if err != nil {
+		return nil, err
+	}
+	return info.toInstrument(e.GetName(), exchange.InstrumentSpot, info.LotSizeFilter.BasePrecision, info.LotSizeFilter.MinOrderAmt)
+}
+
 // Updates returns a channel that receives depth updates
 func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
 }
 
+// Trades returns a channel that receives executed trades
+func (e *SpotExchange) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
 // IsConnected checks if the WebSocket connection is active
 func (e *SpotExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *SpotExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *SpotExchange) readMessages() {
-	defer close(e.updateChan)
-	defer e.updateConnectionStatus(false)
+// handleMessage processes an orderbook or trade message
+func (e *SpotExchange) handleMessage(_ int, message []byte) {
+	// Trade messages carry an array payload, so route on topic before decoding
+	var envelope TopicMessage
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse message: %v", e.GetName(), err)
+		return
+	}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			var msg WSMessage
-			if err := e.wsConn.ReadJSON(&msg); err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				return
-			}
+	if strings.HasPrefix(envelope.Topic, "publicTrade.") {
+		e.ws.IncrementMessageCount()
+		e.ws.UpdateLastPing()
+		e.handleTrades(message)
+		return
+	}
 
-			if msg.Topic == "" || msg.Data.Symbol == "" {
-				continue
-			}
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse orderbook message: %v", e.GetName(), err)
+		return
+	}
+
+	// Skip non-orderbook messages (subscription acks and pongs)
+	if msg.Topic == "" || msg.Data.Symbol == "" {
+		return
+	}
 
-			e.incrementMessageCount()
-			e.updateLastPing()
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
 
-			if msg.Type == "snapshot" && !e.snapshotReceived {
-				e.storeSnapshot(&msg)
-				e.snapshotReceived = true
-			}
+	// Bybit sends a snapshot on every subscribe, so a reconnect starts with one too.
+	// Snapshots after the first replace the whole downstream book.
+	isResync := false
+	if msg.Type == "snapshot" {
+		isResync = e.snapshotReceived
+		e.storeSnapshot(&msg)
+		e.snapshotReceived = true
+	}
 
-			canonicalUpdate := e.convertDepthUpdate(&msg)
+	canonicalUpdate := e.convertDepthUpdate(&msg)
+	canonicalUpdate.IsSnapshot = isResync
 
-			select {
-			case e.updateChan <- canonicalUpdate:
-			case <-e.ctx.Done():
-				return
-			case <-e.done:
-				return
-			default:
-				log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-			}
-		}
+	select {
+	case e.updateChan <- canonicalUpdate:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 	}
 }
 
-// storeSnapshot converts and stores the initial snapshot
+// storeSnapshot converts and stores the latest snapshot
 func (e *SpotExchange) storeSnapshot(msg *WSMessage) {
 	bids := make([]exchange.PriceLevel, len(msg.Data.Bids))
 	for i, bid := range msg.Data.Bids {
@@ -284,34 +266,38 @@ func (e *SpotExchange) convertDepthUpdate(msg *WSMessage) *exchange.DepthUpdate
 	}
 }
 
-// updateConnectionStatus updates the connection status in health
-func (e *SpotExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
+// handleTrades converts a publicTrade message and forwards each trade without blocking
+func (e *SpotExchange) handleTrades(message []byte) {
+	var msg TradeMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse trades: %v", e.GetName(), err)
+		return
 	}
-	e.health.Store(status)
-}
 
-// incrementMessageCount increments the message count in health
-func (e *SpotExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
+	for i := range msg.Data {
+		select {
+		case e.tradeChan <- e.convertTrade(&msg.Data[i]):
+		default:
+			// Trades are dropped if no one is consuming them
+		}
+	}
 }
 
-// incrementErrorCount increments the error count in health
-func (e *SpotExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
+// convertTrade converts a Bybit public trade to canonical format
+func (e *SpotExchange) convertTrade(trade *PublicTrade) *exchange.Trade {
+	side := exchange.TradeSideBuy
+	if trade.Side == "Sell" {
+		side = exchange.TradeSideSell
+	}
 
-// updateLastPing updates the last ping time in health
-func (e *SpotExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.Symbol,
+		TradeID:   trade.TradeID,
+		Price:     trade.Price,
+		Quantity:  trade.Size,
+		Side:      side,
+		Timestamp: time.UnixMilli(trade.Timestamp),
+	}
 }
@@ -1,5 +1,10 @@
 package bybit
 
+// TopicMessage is used to route a raw WebSocket message by topic before decoding it
+type TopicMessage struct {
+	Topic string `json:"topic"`
+}
+
 // WSMessage represents a WebSocket message from Bybit
 type WSMessage struct {
 	Topic string        `json:"topic"`
@@ -18,8 +23,72 @@ type OrderbookData struct {
 	SeqNum   int64      `json:"seq"`
 }
 
+// TradeMessage represents a publicTrade WebSocket message from Bybit
+type TradeMessage struct {
+	Topic string        `json:"topic"`
+	Type  string        `json:"type"`
+	TS    int64         `json:"ts"`
+	Data  []PublicTrade `json:"data"`
+}
+
+// PublicTrade represents a single executed trade from Bybit
+type PublicTrade struct {
+	Timestamp int64  `json:"T"` // Trade time in ms
+	Symbol    string `json:"s"`
+	Side      string `json:"S"` // Taker side: "Buy" or "Sell"
+	Size      string `json:"v"`
+	Price     string `json:"p"`
+	TradeID   string `json:"i"`
+}
+
 // SubscribeMessage represents a subscription request
 type SubscribeMessage struct {
 	Op   string   `json:"op"`
-	Args []string `json:"args"`
+	Args []string `json:"args,omitempty"`
+}
+
+// TickersResponse represents the REST API response for Bybit linear tickers
+type TickersResponse struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		Category string         `json:"category"`
+		List     []LinearTicker `json:"list"`
+	} `json:"result"`
+}
+
+// LinearTicker represents a single linear contract ticker from Bybit
+type LinearTicker struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	FundingRate     string `json:"fundingRate"`
+	NextFundingTime string `json:"nextFundingTime"` // Milliseconds as a string
+	OpenInterest    string `json:"openInterest"`
+}
+
+// InstrumentsInfoResponse represents the REST API response for Bybit instruments info
+type InstrumentsInfoResponse struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		Category string           `json:"category"`
+		List     []InstrumentInfo `json:"list"`
+	} `json:"result"`
+}
+
+// InstrumentInfo represents the trading rules of a single Bybit spot pair or linear contract
+type InstrumentInfo struct {
+	Symbol      string `json:"symbol"`
+	BaseCoin    string `json:"baseCoin"`
+	QuoteCoin   string `json:"quoteCoin"`
+	PriceFilter struct {
+		TickSize string `json:"tickSize"`
+	} `json:"priceFilter"`
+	LotSizeFilter struct {
+		BasePrecision    string `json:"basePrecision"`    // Spot
+		MinOrderAmt      string `json:"minOrderAmt"`      // Spot, in the quote coin
+		QtyStep          string `json:"qtyStep"`          // Linear
+		MinNotionalValue string `json:"minNotionalValue"` // Linear, in the quote coin
+	} `json:"lotSizeFilter"`
 }
@@ -5,56 +5,48 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
+	"net/http"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
 
-	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 )
 
 // SpotExchange implements the Exchange interface for Coinbase Spot
 type SpotExchange struct {
 	symbol           string
-	wsURL            string
-	wsConn           *websocket.Conn
-	wsConnMu         sync.Mutex // Protects wsConn for concurrent writes
+	ws               *supervisor.Supervisor
 	updateChan       chan *exchange.DepthUpdate
-	done             chan struct{}
-	ctx              context.Context
-	cancel           context.CancelFunc
-	health           atomic.Value
+	tradeChan        chan *exchange.Trade
 	snapshotReceived bool
 	snapshot         *exchange.Snapshot
 	snapshotMu       sync.Mutex
-	reconnecting     atomic.Bool // Flag to prevent multiple reconnection attempts
+	resyncing        bool  // Waiting for a fresh snapshot after a sequence gap or reconnect (read goroutine only)
+	lastSeq          int64 // Last sequence number seen on any channel of this connection (read goroutine only)
+	lastBookSeq      int64 // Sequence number of the last l2_data message forwarded (read goroutine only)
 }
 
 // NewSpotExchange creates a new Coinbase Spot exchange instance
 func NewSpotExchange(config Config) *SpotExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	wsURL := "wss://advanced-trade-ws.coinbase.com"
 
-	coinbaseSymbol := convertToCoinbaseSymbol(config.Symbol)
-
 	ex := &SpotExchange{
-		symbol:     coinbaseSymbol,
-		wsURL:      wsURL,
+		symbol:     config.Symbol,
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
-		ctx:        ctx,
-		cancel:     cancel,
+		tradeChan:  make(chan *exchange.Trade, 1000),
+		lastSeq:    -1,
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	// Coinbase pushes heartbeats, so no application-level ping is needed
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:      ex.GetName(),
+		URL:       wsURL,
+		Subscribe: ex.subscribe,
+		Resync:    ex.awaitSnapshot,
+		OnMessage: ex.handleMessage,
 	})
 
 	return ex
@@ -70,74 +62,39 @@ func (e *SpotExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Coinbase
+// Connect establishes WebSocket connection to Coinbase; the supervisor reconnects it if it drops
 func (e *SpotExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
+	return e.ws.Start(ctx)
+}
 
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+// subscribe subscribes to the level2 and trade channels; it runs on every connect
+func (e *SpotExchange) subscribe() error {
+	// Coinbase takes one channel per subscribe request
+	for _, channel := range []string{"level2", "market_trades"} {
+		subscribeMsg := SubscribeRequest{
+			Type:       "subscribe",
+			ProductIDs: []string{e.symbol},
+			Channel:    channel,
+		}
 
-	subscribeMsg := SubscribeRequest{
-		Type:       "subscribe",
-		ProductIDs: []string{e.symbol},
-		Channel:    "level2",
-	}
+		if err := e.ws.WriteJSON(subscribeMsg); err != nil {
+			return fmt.Errorf("%s: %w", channel, err)
+		}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
-		e.incrementErrorCount()
-		conn.Close()
-		return fmt.Errorf("failed to subscribe: %w", err)
+		log.Printf("[%s] Subscribed to %s channel for %s", e.GetName(), channel, e.symbol)
 	}
 
-	log.Printf("[%s] Subscribed to level2 channel for %s", e.GetName(), e.symbol)
-
-	go e.readMessages()
-	go e.pingLoop()
-
 	return nil
 }
 
 // Close closes the WebSocket connection
 func (e *SpotExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
-	}
-
-	// Close the done channel to signal all goroutines to stop
-	select {
-	case <-e.done:
-	default:
-		close(e.done)
-	}
+	err := e.ws.Close()
 
-	if e.wsConn != nil {
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		e.wsConn.Close()
-	}
-
-	// Close update channel after all goroutines have stopped
+	// The read goroutine has exited, so nothing sends on the channels anymore
 	close(e.updateChan)
-
-	return nil
+	close(e.tradeChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via WebSocket
@@ -168,108 +125,209 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	}
 }
 
+// GetInstrument fetches the product's trading rules via REST API
+func (e *SpotExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	url := fmt.Sprintf("https://api.exchange.coinbase.com/products/%s", e.symbol)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	var product ProductResponse
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode product: %w", err)
+	}
+
+	tick, err := decimal.NewFromString(product.QuoteIncrement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote increment %q: %w", product.QuoteIncrement, err)
+	}
+	lot, err := decimal.NewFromString(product.BaseIncrement)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base increment %q: %w", product.BaseIncrement, err)
+	}
+
+	inst := &exchange.Instrument{
+		Exchange:           e.GetName(),
+		Symbol:             e.symbol,
+		Type:               exchange.InstrumentSpot,
+		BaseAsset:          product.BaseCurrency,
+		QuoteAsset:         product.QuoteCurrency,
+		TickSize:           tick,
+		LotSize:            lot,
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}
+	if product.MinMarketFunds != "" {
+		if inst.MinNotional, err = decimal.NewFromString(product.MinMarketFunds); err != nil {
+			return nil, fmt.Errorf("invalid min market funds %q: %w", product.MinMarketFunds, err)
+		}
+	}
+	return inst, nil
+}
+
 // Updates returns a channel that receives depth updates
 func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
 }
 
+// Trades returns a channel that receives executed trades
+func (e *SpotExchange) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
 // IsConnected checks if the WebSocket connection is active
 func (e *SpotExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *SpotExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *SpotExchange) readMessages() {
-	// Note: Don't close updateChan here since we may reconnect and reuse it
-	defer e.updateConnectionStatus(false)
+// handleMessage processes a book, trade or heartbeat message
+func (e *SpotExchange) handleMessage(_ int, message []byte) {
+	// Try to parse as heartbeat message first
+	var heartbeat HeartbeatMessage
+	if err := json.Unmarshal(message, &heartbeat); err == nil && heartbeat.Channel == "heartbeats" {
+		// Heartbeat received, connection is alive
+		e.ws.UpdateLastPing()
+		e.checkSequence(&e.lastSeq, heartbeat.SequenceNum)
+		return
+	}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			e.wsConnMu.Lock()
-			conn := e.wsConn
-			e.wsConnMu.Unlock()
-
-			if conn == nil {
-				log.Printf("[%s] Connection is nil, triggering reconnection", e.GetName())
-				go e.reconnect()
-				return
-			}
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	if msg.Channel != "" {
+		e.checkSequence(&e.lastSeq, msg.SequenceNum)
+	}
 
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				// Trigger reconnection instead of just returning
-				go e.reconnect()
-				return
-			}
+	if msg.Channel == "market_trades" {
+		e.ws.UpdateLastPing()
+		e.handleTrades(message)
+		return
+	}
 
-			// Try to parse as heartbeat message first
-			var heartbeat HeartbeatMessage
-			if err := json.Unmarshal(message, &heartbeat); err == nil && heartbeat.Channel == "heartbeats" {
-				// Heartbeat received, connection is alive
-				e.updateLastPing()
-				continue
-			}
+	if msg.Channel != "l2_data" || len(msg.Events) == 0 {
+		return
+	}
 
-			var msg WSMessage
-			if err := json.Unmarshal(message, &msg); err != nil {
-				continue
-			}
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
 
-			if msg.Channel != "l2_data" || len(msg.Events) == 0 {
-				continue
+	event := msg.Events[0]
+
+	switch event.Type {
+	case "snapshot":
+		e.resyncing = false
+		if !e.snapshotReceived {
+			// Log first 500 chars of raw message for debugging
+			rawMsg := string(message)
+			if len(rawMsg) > 500 {
+				rawMsg = rawMsg[:500] + "..."
 			}
+			log.Printf("[%s] Raw snapshot message: %s", e.GetName(), rawMsg)
+		}
 
-			e.incrementMessageCount()
-			e.updateLastPing()
+		snapshot := e.storeSnapshot(&event, msg.SequenceNum)
+		e.lastBookSeq = msg.SequenceNum
+		if !e.snapshotReceived {
+			e.snapshotReceived = true
+			return
+		}
 
-			event := msg.Events[0]
+		// Snapshots after a resync or reconnect replace the whole downstream book
+		e.sendUpdate(&exchange.DepthUpdate{
+			Exchange:      e.GetName(),
+			Symbol:        snapshot.Symbol,
+			EventTime:     snapshot.Timestamp,
+			FirstUpdateID: msg.SequenceNum,
+			FinalUpdateID: msg.SequenceNum,
+			Bids:          snapshot.Bids,
+			Asks:          snapshot.Asks,
+			IsSnapshot:    true,
+		})
+
+	case "update":
+		if e.resyncing {
+			// Updates until the fresh snapshot would apply to a book with a hole in it
+			return
+		}
 
-			if event.Type == "snapshot" && !e.snapshotReceived {
-				// Log first 500 chars of raw message for debugging
-				rawMsg := string(message)
-				if len(rawMsg) > 500 {
-					rawMsg = rawMsg[:500] + "..."
-				}
-				log.Printf("[%s] Raw snapshot message: %s", e.GetName(), rawMsg)
-				e.storeSnapshot(&event)
-				e.snapshotReceived = true
-			}
+		canonicalUpdate := e.convertDepthUpdate(&event, e.lastBookSeq, msg.SequenceNum)
+		e.lastBookSeq = msg.SequenceNum
+		e.sendUpdate(canonicalUpdate)
+	}
+}
 
-			if event.Type == "update" {
-				canonicalUpdate := e.convertDepthUpdate(&event)
-
-				select {
-				case e.updateChan <- canonicalUpdate:
-				case <-e.ctx.Done():
-					return
-				case <-e.done:
-					return
-				default:
-					log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-				}
-			}
+// sendUpdate forwards an update downstream without blocking
+func (e *SpotExchange) sendUpdate(update *exchange.DepthUpdate) {
+	select {
+	case e.updateChan <- update:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
+	}
+}
+
+// awaitSnapshot runs after a reconnect. Sequence numbers restart with the new connection,
+// and updates are dropped until the resubscribed level2 snapshot replaces the downstream book.
+func (e *SpotExchange) awaitSnapshot() error {
+	e.lastSeq, e.lastBookSeq = -1, 0
+	e.resyncing = true
+	return nil
+}
+
+// checkSequence tracks the connection's sequence number and resyncs the book on a gap.
+// The sequence spans every channel, so a gap anywhere may have been a lost book update.
+func (e *SpotExchange) checkSequence(lastSeq *int64, seq int64) {
+	prev := *lastSeq
+	*lastSeq = seq
+	if prev < 0 || seq == prev+1 {
+		return
+	}
+
+	log.Printf("[%s] Sequence gap: expected %d, got %d. Resyncing book...", e.GetName(), prev+1, seq)
+	e.ws.IncrementResyncCount()
+	e.resync()
+}
+
+// resync resubscribes to the level2 channel so Coinbase sends a fresh snapshot
+func (e *SpotExchange) resync() {
+	e.resyncing = true
+
+	for _, msgType := range []string{"unsubscribe", "subscribe"} {
+		msg := SubscribeRequest{
+			Type:       msgType,
+			ProductIDs: []string{e.symbol},
+			Channel:    "level2",
+		}
+		if err := e.ws.WriteJSON(msg); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to %s level2 channel: %v", e.GetName(), msgType, err)
+			return
 		}
 	}
 }
 
-// storeSnapshot converts and stores the initial snapshot
-func (e *SpotExchange) storeSnapshot(event *Event) {
+// storeSnapshot converts and stores a snapshot, returning it
+func (e *SpotExchange) storeSnapshot(event *Event, seq int64) *exchange.Snapshot {
 	log.Printf("[%s] storeSnapshot called: event.Type=%s, len(event.Updates)=%d",
 		e.GetName(), event.Type, len(event.Updates))
 
@@ -300,7 +358,7 @@ func (e *SpotExchange) storeSnapshot(event *Event) {
 	snapshot := &exchange.Snapshot{
 		Exchange:     e.GetName(),
 		Symbol:       event.ProductID,
-		LastUpdateID: 0,
+		LastUpdateID: seq,
 		Bids:         filteredBids,
 		Asks:         filteredAsks,
 		Timestamp:    time.Now(),
@@ -309,6 +367,8 @@ func (e *SpotExchange) storeSnapshot(event *Event) {
 	e.snapshotMu.Lock()
 	e.snapshot = snapshot
 	e.snapshotMu.Unlock()
+
+	return snapshot
 }
 
 // filterSnapshotByDistance filters bids/asks to keep only those within a certain percentage of the mid price
@@ -372,8 +432,10 @@ func filterSnapshotByDistance(bids, asks []exchange.PriceLevel, maxDistancePct f
 	return filteredBids, filteredAsks
 }
 
-// convertDepthUpdate converts Coinbase depth update to canonical format
-func (e *SpotExchange) convertDepthUpdate(event *Event) *exchange.DepthUpdate {
+// convertDepthUpdate converts Coinbase depth update to canonical format.
+// The update covers every sequence number since the previous book message, since the ones
+// in between belong to other channels.
+func (e *SpotExchange) convertDepthUpdate(event *Event, prevSeq, seq int64) *exchange.DepthUpdate {
 	var bids []exchange.PriceLevel
 	var asks []exchange.PriceLevel
 
@@ -396,158 +458,59 @@ func (e *SpotExchange) convertDepthUpdate(event *Event) *exchange.DepthUpdate {
 		Exchange:      e.GetName(),
 		Symbol:        event.ProductID,
 		EventTime:     eventTime,
-		FirstUpdateID: 0,
-		FinalUpdateID: 0,
-		PrevUpdateID:  0,
+		FirstUpdateID: prevSeq + 1,
+		FinalUpdateID: seq,
+		PrevUpdateID:  prevSeq,
 		Bids:          bids,
 		Asks:          asks,
 	}
 }
 
-// pingLoop monitors connection health (Coinbase sends automatic heartbeats, we just monitor)
-func (e *SpotExchange) pingLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-e.done:
-			return
-		case <-ticker.C:
-			// Coinbase sends heartbeats automatically
-			// We just monitor LastPing to detect stale connections
-			health := e.Health()
-			if !health.LastPing.IsZero() && time.Since(health.LastPing) > 60*time.Second {
-				log.Printf("[%s] No heartbeat for 60s, connection may be stale", e.GetName())
-			}
-		}
-	}
-}
-
-// reconnect attempts to re-establish the WebSocket connection
-func (e *SpotExchange) reconnect() {
-	// Prevent multiple simultaneous reconnection attempts
-	if !e.reconnecting.CompareAndSwap(false, true) {
-		log.Printf("[%s] Reconnection already in progress, skipping", e.GetName())
+// handleTrades converts a market_trades message and forwards each trade without blocking.
+// Snapshot events carry recent history and are skipped so only live prints are emitted.
+func (e *SpotExchange) handleTrades(message []byte) {
+	var msg TradesMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse trades: %v", e.GetName(), err)
 		return
 	}
-	defer e.reconnecting.Store(false)
-
-	log.Printf("[%s] Starting reconnection...", e.GetName())
-
-	// Close existing connection if any
-	e.wsConnMu.Lock()
-	if e.wsConn != nil {
-		e.wsConn.Close()
-		e.wsConn = nil
-	}
-	e.wsConnMu.Unlock()
-
-	// Wait before reconnecting
-	time.Sleep(5 * time.Second)
-
-	// Attempt to reconnect
-	maxAttempts := 10
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled during reconnection", e.GetName())
-			return
-		case <-e.done:
-			log.Printf("[%s] Done signal received during reconnection", e.GetName())
-			return
-		default:
-			log.Printf("[%s] Reconnection attempt %d/%d", e.GetName(), attempt, maxAttempts)
-
-			// Create new context for this connection attempt
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			err := e.Connect(ctx)
-			cancel()
-
-			if err == nil {
-				log.Printf("[%s] Reconnection successful!", e.GetName())
-
-				// Reset snapshot flag to get a fresh snapshot
-				e.snapshotMu.Lock()
-				e.snapshotReceived = false
-				e.snapshot = nil
-				e.snapshotMu.Unlock()
-
-				return
-			}
 
-			log.Printf("[%s] Reconnection attempt %d failed: %v", e.GetName(), attempt, err)
+	for _, event := range msg.Events {
+		if event.Type != "update" {
+			continue
+		}
 
-			// Exponential backoff with max of 30 seconds
-			backoff := time.Duration(attempt) * 5 * time.Second
-			if backoff > 30*time.Second {
-				backoff = 30 * time.Second
+		// Trades within an event arrive newest first
+		for i := len(event.Trades) - 1; i >= 0; i-- {
+			select {
+			case e.tradeChan <- e.convertTrade(&event.Trades[i]):
+			default:
+				// Trades are dropped if no one is consuming them
 			}
-			time.Sleep(backoff)
 		}
 	}
-
-	log.Printf("[%s] Failed to reconnect after %d attempts, giving up", e.GetName(), maxAttempts)
 }
 
-// convertToCoinbaseSymbol converts various symbol formats to Coinbase format
-// Examples: BTCUSDT -> BTC-USD, BTC-USD -> BTC-USD
-func convertToCoinbaseSymbol(symbol string) string {
-	if strings.Contains(symbol, "-") {
-		return strings.ToUpper(symbol)
+// convertTrade converts a Coinbase market trade to canonical format
+func (e *SpotExchange) convertTrade(trade *MarketTrade) *exchange.Trade {
+	side := exchange.TradeSideBuy
+	if trade.Side == "SELL" {
+		side = exchange.TradeSideSell
 	}
 
-	symbol = strings.ToUpper(symbol)
-
-	if strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USDT")
-		return fmt.Sprintf("%s-USD", base)
-	}
-
-	if strings.HasSuffix(symbol, "USD") && !strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USD")
-		return fmt.Sprintf("%s-USD", base)
+	timestamp, err := time.Parse(time.RFC3339Nano, trade.Time)
+	if err != nil {
+		timestamp = time.Now()
 	}
 
-	if strings.HasSuffix(symbol, "USDC") {
-		base := strings.TrimSuffix(symbol, "USDC")
-		return fmt.Sprintf("%s-USDC", base)
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.ProductID,
+		TradeID:   trade.TradeID,
+		Price:     trade.Price,
+		Quantity:  trade.Size,
+		Side:      side,
+		Timestamp: timestamp,
 	}
-
-	log.Printf("[Coinbase] Warning: Could not convert symbol %s to Coinbase format, using as-is", symbol)
-	return symbol
-}
-
-// updateConnectionStatus updates the connection status in health
-func (e *SpotExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
-	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *SpotExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *SpotExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *SpotExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
 }
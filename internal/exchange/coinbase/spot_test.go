@@ -0,0 +1,54 @@
+package coinbase
+
+import "testing"
+
+func TestCheckSequence(t *testing.T) {
+	tests := []struct {
+		name        string
+		sequence    []int64
+		wantResyncs int64
+	}{
+		{"consecutive", []int64{0, 1, 2, 3}, 0},
+		{"first message sets the baseline", []int64{7, 8}, 0},
+		{"gap", []int64{0, 1, 3, 4}, 1},
+		{"repeated message", []int64{0, 1, 1, 2}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewSpotExchange(Config{Symbol: "BTCUSDT"})
+			lastSeq := int64(-1)
+			for _, seq := range tt.sequence {
+				e.checkSequence(&lastSeq, seq)
+			}
+
+			if got := e.Health().ResyncCount; got != tt.wantResyncs {
+				t.Errorf("Expected %d resyncs, got %d", tt.wantResyncs, got)
+			}
+			if e.resyncing != (tt.wantResyncs > 0) {
+				t.Errorf("Expected resyncing=%v, got %v", tt.wantResyncs > 0, e.resyncing)
+			}
+		})
+	}
+}
+
+func TestConvertDepthUpdateChainsSequence(t *testing.T) {
+	e := NewSpotExchange(Config{Symbol: "BTCUSDT"})
+	event := &Event{
+		ProductID: "BTC-USD",
+		Updates: []Update{
+			{Side: "bid", PriceLevel: "100", NewQuantity: "1"},
+			{Side: "offer", PriceLevel: "101", NewQuantity: "0"},
+		},
+	}
+
+	// Sequence numbers 6 and 7 went to other channels
+	update := e.convertDepthUpdate(event, 5, 8)
+
+	if update.PrevUpdateID != 5 || update.FirstUpdateID != 6 || update.FinalUpdateID != 8 {
+		t.Errorf("Expected pu=5 U=6 u=8, got pu=%d U=%d u=%d", update.PrevUpdateID, update.FirstUpdateID, update.FinalUpdateID)
+	}
+	if len(update.Bids) != 1 || len(update.Asks) != 1 {
+		t.Errorf("Expected 1 bid and 1 ask, got %d and %d", len(update.Bids), len(update.Asks))
+	}
+}
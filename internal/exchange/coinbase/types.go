@@ -2,10 +2,10 @@ package coinbase
 
 // Config holds configuration for Coinbase exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTC-USD), as resolved by the symbols package
 }
 
-// SubscribeRequest represents a subscription request to Coinbase WebSocket
+// SubscribeRequest represents a subscription (or unsubscription) request to Coinbase WebSocket
 type SubscribeRequest struct {
 	Type       string   `json:"type"`
 	ProductIDs []string `json:"product_ids"`
@@ -14,9 +14,10 @@ type SubscribeRequest struct {
 
 // WSMessage represents a WebSocket message from Coinbase
 type WSMessage struct {
-	Channel   string  `json:"channel"`
-	Timestamp string  `json:"timestamp"`
-	Events    []Event `json:"events"`
+	Channel     string  `json:"channel"`
+	Timestamp   string  `json:"timestamp"`
+	SequenceNum int64   `json:"sequence_num"` // Increments by one per message across all channels of a connection
+	Events      []Event `json:"events"`
 }
 
 // Event represents an event in the WebSocket message
@@ -34,6 +35,29 @@ type Update struct {
 	NewQuantity string `json:"new_quantity"` // quantity (if "0", remove level)
 }
 
+// TradesMessage represents a market_trades WebSocket message from Coinbase
+type TradesMessage struct {
+	Channel   string        `json:"channel"`
+	Timestamp string        `json:"timestamp"`
+	Events    []TradesEvent `json:"events"`
+}
+
+// TradesEvent represents a batch of trades in a market_trades message
+type TradesEvent struct {
+	Type   string        `json:"type"` // "snapshot" or "update"
+	Trades []MarketTrade `json:"trades"`
+}
+
+// MarketTrade represents a single executed trade
+type MarketTrade struct {
+	TradeID   string `json:"trade_id"`
+	ProductID string `json:"product_id"`
+	Price     string `json:"price"`
+	Size      string `json:"size"`
+	Side      string `json:"side"` // Taker side: "BUY" or "SELL"
+	Time      string `json:"time"` // RFC3339 timestamp
+}
+
 // HeartbeatMessage represents a heartbeat message from Coinbase
 type HeartbeatMessage struct {
 	Channel        string `json:"channel"`
@@ -43,3 +67,13 @@ type HeartbeatMessage struct {
 	CurrentTime    string `json:"current_time"`
 	HeartbeatCount int64  `json:"heartbeat_counter"`
 }
+
+// ProductResponse represents the Coinbase Exchange REST API response for a product's trading rules
+type ProductResponse struct {
+	ID             string `json:"id"`
+	BaseCurrency   string `json:"base_currency"`
+	QuoteCurrency  string `json:"quote_currency"`
+	QuoteIncrement string `json:"quote_increment"`
+	BaseIncrement  string `json:"base_increment"`
+	MinMarketFunds string `json:"min_market_funds"`
+}
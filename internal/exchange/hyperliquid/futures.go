@@ -6,58 +6,48 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
-	"strings"
-	"sync"
-	"sync/atomic"
+	"strconv"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
+
+	"github.com/shopspring/decimal"
 )
 
 // FuturesExchange implements the Exchange interface for Hyperliquid
 type FuturesExchange struct {
-	symbol       string
-	wsURL        string
-	restURL      string
-	wsConn       *websocket.Conn
-	wsConnMu     sync.Mutex // Protects wsConn for thread-safe operations
-	updateChan   chan *exchange.DepthUpdate
-	done         chan struct{}
-	ctx          context.Context
-	cancel       context.CancelFunc
-	health       atomic.Value // stores exchange.HealthStatus
-	reconnecting atomic.Bool  // Prevents concurrent reconnection attempts
+	symbol      string
+	restURL     string
+	ws          *supervisor.Supervisor
+	updateChan  chan *exchange.DepthUpdate
+	tradeChan   chan *exchange.Trade
+	connectedAt int64 // Unix ms of the latest subscription; older trades are history (read goroutine only)
 }
 
 // Config holds configuration for Hyperliquid exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTC), as resolved by the symbols package
 }
 
 // NewFuturesExchange creates a new Hyperliquid exchange instance
 func NewFuturesExchange(config Config) *FuturesExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
-	// Convert XXXUSDT to XXX for Hyperliquid (e.g., BTCUSDT -> BTC)
-	symbol := strings.TrimSuffix(config.Symbol, "USDT")
-
 	ex := &FuturesExchange{
-		symbol:     symbol,
-		wsURL:      "wss://api.hyperliquid.xyz/ws",
+		symbol:     config.Symbol,
 		restURL:    "https://api.hyperliquid.xyz/info",
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
-		ctx:        ctx,
-		cancel:     cancel,
+		tradeChan:  make(chan *exchange.Trade, 1000),
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	// Every l2Book push is a full book, so a reconnect needs no resync
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:      ex.GetName(),
+		URL:       "wss://api.hyperliquid.xyz/ws",
+		Subscribe: ex.subscribe,
+		OnMessage: ex.handleMessage,
+		Ping:      ex.ping,
 	})
 
 	return ex
@@ -73,34 +63,13 @@ func (e *FuturesExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Hyperliquid
+// Connect establishes WebSocket connection to Hyperliquid; the supervisor reconnects it if it drops
 func (e *FuturesExchange) Connect(ctx context.Context) error {
-	// Check if we're shutting down before connecting
-	select {
-	case <-ctx.Done():
-		return fmt.Errorf("context cancelled, not connecting")
-	case <-e.done:
-		return fmt.Errorf("exchange shutting down, not connecting")
-	default:
-	}
-
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConnMu.Lock()
-	e.wsConn = conn
-	e.wsConnMu.Unlock()
-
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+	return e.ws.Start(ctx)
+}
 
+// subscribe subscribes to L2 book and trade updates; it runs on every connect
+func (e *FuturesExchange) subscribe() error {
 	// Subscribe to L2 book updates
 	subscription := SubscriptionMessage{
 		Method: "subscribe",
@@ -110,130 +79,39 @@ func (e *FuturesExchange) Connect(ctx context.Context) error {
 		},
 	}
 
-	if err := conn.WriteJSON(subscription); err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("failed to send subscription: %w", err)
-	}
-
-	go e.readMessages()
-	go e.pingLoop()
-
-	return nil
-}
-
-// Close closes the WebSocket connection
-func (e *FuturesExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
+	if err := e.ws.WriteJSON(subscription); err != nil {
+		return err
 	}
 
-	select {
-	case <-e.done:
-	default:
-		close(e.done)
+	// Subscribe to trades; the first message replays recent trades, which are filtered by connectedAt
+	e.connectedAt = time.Now().UnixMilli()
+	tradesSubscription := SubscriptionMessage{
+		Method: "subscribe",
+		Subscription: map[string]interface{}{
+			"type": "trades",
+			"coin": e.symbol,
+		},
 	}
 
-	e.wsConnMu.Lock()
-	conn := e.wsConn
-	e.wsConnMu.Unlock()
-
-	var connErr error
-	if conn != nil {
-		err := conn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
-
-		select {
-		case <-time.After(time.Second):
-		}
-
-		e.updateConnectionStatus(false)
-		connErr = conn.Close()
+	if err := e.ws.WriteJSON(tradesSubscription); err != nil {
+		return fmt.Errorf("trades: %w", err)
 	}
-
-	// Close the update channel after everything else is cleaned up
-	close(e.updateChan)
-	return connErr
+	return nil
 }
 
-// pingLoop monitors connection health by checking message timestamps
-func (e *FuturesExchange) pingLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-e.done:
-			return
-		case <-ticker.C:
-			health := e.Health()
-			if !health.LastPing.IsZero() && time.Since(health.LastPing) > 60*time.Second {
-				log.Printf("[%s] No messages received for 60s, connection may be stale", e.GetName())
-				go e.reconnect()
-				return
-			}
-		}
-	}
+// ping sends Hyperliquid's application-level ping; the server answers on the pong channel
+func (e *FuturesExchange) ping() error {
+	return e.ws.WriteJSON(SubscriptionMessage{Method: "ping"})
 }
 
-// reconnect attempts to reconnect the WebSocket connection with exponential backoff
-func (e *FuturesExchange) reconnect() {
-	// Prevent multiple simultaneous reconnection attempts
-	if !e.reconnecting.CompareAndSwap(false, true) {
-		log.Printf("[%s] Reconnection already in progress, skipping", e.GetName())
-		return
-	}
-	defer e.reconnecting.Store(false)
-
-	log.Printf("[%s] Starting reconnection process", e.GetName())
-
-	// Close existing connection
-	e.wsConnMu.Lock()
-	if e.wsConn != nil {
-		e.wsConn.Close()
-		e.wsConn = nil
-	}
-	e.wsConnMu.Unlock()
-
-	maxAttempts := 10
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled during reconnection", e.GetName())
-			return
-		case <-e.done:
-			log.Printf("[%s] Shutdown signal during reconnection", e.GetName())
-			return
-		default:
-		}
-
-		log.Printf("[%s] Reconnection attempt %d/%d", e.GetName(), attempt, maxAttempts)
-
-		// Exponential backoff: 5s, 10s, 15s, ..., up to 30s
-		if attempt > 1 {
-			backoff := time.Duration(attempt-1) * 5 * time.Second
-			if backoff > 30*time.Second {
-				backoff = 30 * time.Second
-			}
-			log.Printf("[%s] Waiting %v before reconnection attempt", e.GetName(), backoff)
-			time.Sleep(backoff)
-		}
-
-		// Attempt to reconnect
-		if err := e.Connect(e.ctx); err != nil {
-			log.Printf("[%s] Reconnection attempt %d failed: %v", e.GetName(), attempt, err)
-			continue
-		}
-
-		log.Printf("[%s] Reconnection successful", e.GetName())
-		return
-	}
+// Close closes the WebSocket connection
+func (e *FuturesExchange) Close() error {
+	err := e.ws.Close()
 
-	log.Printf("[%s] Failed to reconnect after %d attempts", e.GetName(), maxAttempts)
+	// The read goroutine has exited, so nothing sends on the channels anymore
+	close(e.updateChan)
+	close(e.tradeChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via REST API
@@ -260,14 +138,14 @@ func (e *FuturesExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot,
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var hyperliquidSnapshot L2BookResponse
 	if err := json.NewDecoder(resp.Body).Decode(&hyperliquidSnapshot); err != nil {
-		e.incrementErrorCount()
+		e.ws.IncrementErrorCount()
 		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
 	}
 
@@ -275,93 +153,230 @@ func (e *FuturesExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot,
 	return snapshot, nil
 }
 
+// GetDerivativesInfo fetches funding, mark/oracle price and open interest from the metaAndAssetCtxs endpoint
+func (e *FuturesExchange) GetDerivativesInfo(ctx context.Context) (*exchange.DerivativesInfo, error) {
+	_, assetCtx, err := e.fetchAssetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &exchange.DerivativesInfo{
+		Exchange:        e.GetName(),
+		Symbol:          e.symbol,
+		FundingRate:     assetCtx.Funding,
+		NextFundingTime: now.Truncate(time.Hour).Add(time.Hour), // Funding settles every hour
+		MarkPrice:       assetCtx.MarkPx,
+		IndexPrice:      assetCtx.OraclePx,
+		OpenInterest:    assetCtx.OpenInterest,
+		Timestamp:       now,
+	}, nil
+}
+
+// GetInstrument derives the perpetual's trading rules from the metaAndAssetCtxs endpoint.
+// Hyperliquid publishes no tick size: prices may have at most 5 significant figures and
+// 6 - szDecimals decimals, so the tick depends on the current price.
+func (e *FuturesExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	asset, assetCtx, err := e.fetchAssetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markPx, err := decimal.NewFromString(assetCtx.MarkPx)
+	if err != nil || !markPx.IsPositive() {
+		return nil, fmt.Errorf("invalid mark price %q", assetCtx.MarkPx)
+	}
+
+	return &exchange.Instrument{
+		Exchange:           e.GetName(),
+		Symbol:             e.symbol,
+		Type:               exchange.InstrumentLinearPerp,
+		BaseAsset:          e.symbol,
+		QuoteAsset:         "USDC",
+		TickSize:           tickSize(markPx, asset.SzDecimals),
+		LotSize:            decimal.New(1, -int32(asset.SzDecimals)),
+		MinNotional:        decimal.NewFromInt(10), // Hyperliquid rejects orders worth less than $10
+		ContractMultiplier: decimal.NewFromInt(1),
+		BaseSizes:          true,
+	}, nil
+}
+
+// fetchAssetContext fetches the universe entry and live context of this symbol
+func (e *FuturesExchange) fetchAssetContext(ctx context.Context) (*AssetMeta, *AssetContext, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"type": "metaAndAssetCtxs",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.restURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, nil, fmt.Errorf("failed to get asset contexts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Response is a two-element array: [meta, assetCtxs], aligned by index
+	var raw []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, nil, fmt.Errorf("failed to decode asset contexts: %w", err)
+	}
+	if len(raw) != 2 {
+		e.ws.IncrementErrorCount()
+		return nil, nil, fmt.Errorf("unexpected asset contexts response with %d elements", len(raw))
+	}
+
+	var meta MetaResponse
+	var assetCtxs []AssetContext
+	if err := json.Unmarshal(raw[0], &meta); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, nil, fmt.Errorf("failed to decode meta: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &assetCtxs); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, nil, fmt.Errorf("failed to decode asset contexts: %w", err)
+	}
+
+	for i, asset := range meta.Universe {
+		if asset.Name != e.symbol || i >= len(assetCtxs) {
+			continue
+		}
+		return &meta.Universe[i], &assetCtxs[i], nil
+	}
+
+	return nil, nil, fmt.Errorf("asset %s not found in universe", e.symbol)
+}
+
+// tickSize returns the price increment at price for a perpetual with szDecimals size decimals
+func tickSize(price decimal.Decimal, szDecimals int) decimal.Decimal {
+	// At most 6 - szDecimals decimals
+	tick := decimal.New(1, -int32(6-szDecimals))
+
+	// At most 5 significant figures
+	magnitude := int32(math.Floor(math.Log10(price.InexactFloat64())))
+	if sigFig := decimal.New(1, magnitude-4); sigFig.GreaterThan(tick) {
+		tick = sigFig
+	}
+	return tick
+}
+
 // Updates returns a channel that receives depth updates
 func (e *FuturesExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
 }
 
+// Trades returns a channel that receives executed trades
+func (e *FuturesExchange) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
 // IsConnected checks if the WebSocket connection is active
 func (e *FuturesExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *FuturesExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *FuturesExchange) readMessages() {
-	defer e.updateConnectionStatus(false)
+// handleMessage processes a book, trade or control message
+func (e *FuturesExchange) handleMessage(_ int, message []byte) {
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse message: %v", e.GetName(), err)
+		return
+	}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
+
+	// Handle subscription and ping responses
+	if msg.Channel == "subscriptionResponse" || msg.Channel == "pong" {
+		return
+	}
+
+	// Handle trades
+	if msg.Channel == "trades" {
+		var trades []WsTrade
+		dataBytes, err := json.Marshal(msg.Data)
+		if err != nil {
+			log.Printf("[%s] Error marshalling trades: %v", e.GetName(), err)
 			return
-		case <-e.done:
+		}
+
+		if err := json.Unmarshal(dataBytes, &trades); err != nil {
+			log.Printf("[%s] Error unmarshalling trades: %v", e.GetName(), err)
 			return
-		default:
-			// Thread-safe access to connection
-			e.wsConnMu.Lock()
-			conn := e.wsConn
-			e.wsConnMu.Unlock()
-
-			if conn == nil {
-				log.Printf("[%s] Connection is nil, triggering reconnection", e.GetName())
-				go e.reconnect()
-				return
-			}
+		}
 
-			var msg WSMessage
-			if err := conn.ReadJSON(&msg); err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				go e.reconnect()
-				return
+		for i := range trades {
+			if trades[i].Time < e.connectedAt {
+				continue
 			}
+			select {
+			case e.tradeChan <- e.convertTrade(&trades[i]):
+			default:
+				// Trades are dropped if no one is consuming them
+			}
+		}
+		return
+	}
 
-			e.incrementMessageCount()
-			e.updateLastPing()
+	// Handle L2 book updates
+	if msg.Channel == "l2Book" {
+		var bookData WsBook
+		dataBytes, err := json.Marshal(msg.Data)
+		if err != nil {
+			log.Printf("[%s] Error marshalling book data: %v", e.GetName(), err)
+			return
+		}
 
-			// Handle subscription response
-			if msg.Channel == "subscriptionResponse" {
-				continue
-			}
+		if err := json.Unmarshal(dataBytes, &bookData); err != nil {
+			log.Printf("[%s] Error unmarshalling book data: %v", e.GetName(), err)
+			return
+		}
 
-			// Handle L2 book updates
-			if msg.Channel == "l2Book" {
-				var bookData WsBook
-				dataBytes, err := json.Marshal(msg.Data)
-				if err != nil {
-					log.Printf("[%s] Error marshalling book data: %v", e.GetName(), err)
-					continue
-				}
-
-				if err := json.Unmarshal(dataBytes, &bookData); err != nil {
-					log.Printf("[%s] Error unmarshalling book data: %v", e.GetName(), err)
-					continue
-				}
-
-				canonicalUpdate := e.convertDepthUpdate(&bookData)
-
-				select {
-				case e.updateChan <- canonicalUpdate:
-				case <-e.ctx.Done():
-					return
-				case <-e.done:
-					return
-				default:
-					log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-				}
-			}
+		canonicalUpdate := e.convertDepthUpdate(&bookData)
+
+		select {
+		case e.updateChan <- canonicalUpdate:
+		default:
+			log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
 		}
 	}
 }
 
+// convertTrade converts a Hyperliquid trade to canonical format
+func (e *FuturesExchange) convertTrade(trade *WsTrade) *exchange.Trade {
+	// Side is the aggressor: "B" for buy, "A" for sell
+	side := exchange.TradeSideBuy
+	if trade.Side == "A" {
+		side = exchange.TradeSideSell
+	}
+
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.Coin,
+		TradeID:   strconv.FormatInt(trade.TID, 10),
+		Price:     trade.Px,
+		Quantity:  trade.Sz,
+		Side:      side,
+		Timestamp: time.UnixMilli(trade.Time),
+	}
+}
+
 // convertSnapshot converts Hyperliquid snapshot to canonical format
 func (e *FuturesExchange) convertSnapshot(snapshot *L2BookResponse) *exchange.Snapshot {
 	// Hyperliquid returns levels as [bids[], asks[]] in normal order
@@ -438,35 +453,3 @@ func (e *FuturesExchange) convertDepthUpdate(update *WsBook) *exchange.DepthUpda
 		IsSnapshot:    true, // Full snapshot, not incremental
 	}
 }
-
-// updateConnectionStatus updates the connection status in health
-func (e *FuturesExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
-	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *FuturesExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *FuturesExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *FuturesExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
-}
\ No newline at end of file
@@ -21,10 +21,21 @@ type WsLevel struct {
 	N  int    `json:"n"`  // number of orders
 }
 
+// WsTrade represents a single trade from the Hyperliquid trades subscription
+type WsTrade struct {
+	Coin string `json:"coin"`
+	Side string `json:"side"` // Aggressor side: "B" (buy) or "A" (sell)
+	Px   string `json:"px"`
+	Sz   string `json:"sz"`
+	Time int64  `json:"time"`
+	Hash string `json:"hash"`
+	TID  int64  `json:"tid"`
+}
+
 // SubscriptionMessage represents the WebSocket subscription message
 type SubscriptionMessage struct {
 	Method       string                 `json:"method"`
-	Subscription map[string]interface{} `json:"subscription"`
+	Subscription map[string]interface{} `json:"subscription,omitempty"`
 }
 
 // SubscriptionResponse represents the WebSocket subscription acknowledgment
@@ -37,4 +48,24 @@ type SubscriptionResponse struct {
 type WSMessage struct {
 	Channel string      `json:"channel"`
 	Data    interface{} `json:"data"`
-}
\ No newline at end of file
+}
+
+// MetaResponse represents the perpetuals universe returned by metaAndAssetCtxs
+type MetaResponse struct {
+	Universe []AssetMeta `json:"universe"`
+}
+
+// AssetMeta represents a single perpetual in the Hyperliquid universe
+type AssetMeta struct {
+	Name       string `json:"name"`
+	SzDecimals int    `json:"szDecimals"`
+}
+
+// AssetContext represents live market data for a perpetual, aligned by index with the universe
+type AssetContext struct {
+	Funding      string `json:"funding"`      // Hourly funding rate
+	OpenInterest string `json:"openInterest"` // In coins
+	MarkPx       string `json:"markPx"`
+	OraclePx     string `json:"oraclePx"`
+	MidPx        string `json:"midPx"`
+}
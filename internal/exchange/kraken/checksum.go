@@ -0,0 +1,113 @@
+package kraken
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// checksumLevels is the number of levels per side covered by Kraken's book checksum
+const checksumLevels = 10
+
+// bookLevel is a price level kept exactly as Kraken formatted it, since the checksum is
+// computed over the original text
+type bookLevel struct {
+	price decimal.Decimal
+	text  PriceQty
+}
+
+// localBook mirrors Kraken's view of the book, limited to the subscribed depth,
+// so that the checksum sent with every message can be verified
+type localBook struct {
+	depth int
+	bids  []bookLevel // Sorted by price descending (best bid first)
+	asks  []bookLevel // Sorted by price ascending (best ask first)
+}
+
+func newLocalBook(depth int) *localBook {
+	return &localBook{depth: depth}
+}
+
+// reset replaces the book with a snapshot
+func (b *localBook) reset(data *BookData) error {
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+	return b.apply(data)
+}
+
+// apply applies an update; levels with zero quantity are removed and levels pushed
+// beyond the subscribed depth are dropped, as Kraken does
+func (b *localBook) apply(data *BookData) error {
+	var err error
+	for _, level := range data.Bids {
+		if b.bids, err = b.applyLevel(b.bids, level, true); err != nil {
+			return err
+		}
+	}
+	for _, level := range data.Asks {
+		if b.asks, err = b.applyLevel(b.asks, level, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *localBook) applyLevel(side []bookLevel, level PriceQty, descending bool) ([]bookLevel, error) {
+	price, err := decimal.NewFromString(level.Price.String())
+	if err != nil {
+		return side, fmt.Errorf("invalid price %q: %w", level.Price, err)
+	}
+	qty, err := decimal.NewFromString(level.Qty.String())
+	if err != nil {
+		return side, fmt.Errorf("invalid quantity %q: %w", level.Qty, err)
+	}
+
+	i := sort.Search(len(side), func(i int) bool {
+		if descending {
+			return side[i].price.LessThanOrEqual(price)
+		}
+		return side[i].price.GreaterThanOrEqual(price)
+	})
+	found := i < len(side) && side[i].price.Equal(price)
+
+	switch {
+	case qty.IsZero():
+		if found {
+			side = append(side[:i], side[i+1:]...)
+		}
+	case found:
+		side[i].text = level
+	default:
+		side = append(side, bookLevel{})
+		copy(side[i+1:], side[i:])
+		side[i] = bookLevel{price: price, text: level}
+	}
+
+	if b.depth > 0 && len(side) > b.depth {
+		side = side[:b.depth]
+	}
+	return side, nil
+}
+
+// checksum computes Kraken's CRC32 over the top 10 asks followed by the top 10 bids
+func (b *localBook) checksum() uint32 {
+	var sb strings.Builder
+	writeChecksumLevels(&sb, b.asks)
+	writeChecksumLevels(&sb, b.bids)
+	return crc32.ChecksumIEEE([]byte(sb.String()))
+}
+
+func writeChecksumLevels(sb *strings.Builder, levels []bookLevel) {
+	for i := 0; i < len(levels) && i < checksumLevels; i++ {
+		sb.WriteString(checksumField(levels[i].text.Price.String()))
+		sb.WriteString(checksumField(levels[i].text.Qty.String()))
+	}
+}
+
+// checksumField strips the decimal point and leading zeros, e.g. "0.00500000" -> "500000"
+func checksumField(value string) string {
+	return strings.TrimLeft(strings.Replace(value, ".", "", 1), "0")
+}
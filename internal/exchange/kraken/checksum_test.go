@@ -0,0 +1,86 @@
+package kraken
+
+import (
+	"encoding/json"
+	"hash/crc32"
+	"strconv"
+	"testing"
+)
+
+func level(price, qty string) PriceQty {
+	return PriceQty{Price: json.Number(price), Qty: json.Number(qty)}
+}
+
+func TestChecksumField(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"45283.5", "452835"},
+		{"0.00500000", "500000"},
+		{"0.10000000", "10000000"},
+		{"100", "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := checksumField(tt.input); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLocalBookChecksum(t *testing.T) {
+	book := newLocalBook(bookDepth)
+	err := book.reset(&BookData{
+		Bids: []PriceQty{level("100.0", "1.50000000"), level("99.5", "0.00200000")},
+		Asks: []PriceQty{level("100.5", "2.00000000"), level("101.0", "0.10000000")},
+	})
+	if err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+
+	// Asks best first, then bids best first
+	expected := crc32.ChecksumIEEE([]byte("1005200000000" + "101010000000" + "1000150000000" + "995200000"))
+	if got := book.checksum(); got != expected {
+		t.Errorf("Expected checksum %d, got %d", expected, got)
+	}
+
+	// Remove the best bid and improve the ask; the new levels must land in price order
+	err = book.apply(&BookData{
+		Bids: []PriceQty{level("100.0", "0.00000000")},
+		Asks: []PriceQty{level("100.2", "0.30000000")},
+	})
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	expected = crc32.ChecksumIEEE([]byte("100230000000" + "1005200000000" + "101010000000" + "995200000"))
+	if got := book.checksum(); got != expected {
+		t.Errorf("Expected checksum %d after update, got %d", expected, got)
+	}
+}
+
+func TestLocalBookCoversTopTenWithinDepth(t *testing.T) {
+	book := newLocalBook(12)
+
+	var asks []PriceQty
+	for i := 1; i <= 15; i++ {
+		asks = append(asks, level(strconv.Itoa(100+i), "1"))
+	}
+	if err := book.reset(&BookData{Asks: asks}); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if len(book.asks) != 12 {
+		t.Fatalf("Expected book truncated to 12 levels, got %d", len(book.asks))
+	}
+
+	var text string
+	for i := 1; i <= checksumLevels; i++ {
+		text += strconv.Itoa(100+i) + "1"
+	}
+	if got := book.checksum(); got != crc32.ChecksumIEEE([]byte(text)) {
+		t.Errorf("Expected checksum over the 10 best asks, got %d", got)
+	}
+}
@@ -5,56 +5,57 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
 
-	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 )
 
+// bookDepth is the number of levels per side subscribed to on the book channel
+const bookDepth = 1000
+
 // SpotExchange implements the Exchange interface for Kraken Spot
 type SpotExchange struct {
 	symbol           string
-	wsURL            string
-	wsConn           *websocket.Conn
-	wsConnMu         sync.Mutex // Protects wsConn for concurrent writes
+	ws               *supervisor.Supervisor
 	updateChan       chan *exchange.DepthUpdate
-	done             chan struct{}
-	ctx              context.Context
-	cancel           context.CancelFunc
-	health           atomic.Value
+	tradeChan        chan *exchange.Trade
 	snapshotReceived bool
 	snapshot         *exchange.Snapshot
 	snapshotMu       sync.Mutex
-	reconnecting     atomic.Bool // Flag to prevent multiple reconnection attempts
+	book             *localBook   // Local copy used to verify checksums (read goroutine only)
+	resyncing        bool         // Waiting for a fresh snapshot after a failed checksum or reconnect (read goroutine only)
+	pingReqID        atomic.Int64 // req_id of the last ping
 }
 
 // NewSpotExchange creates a new Kraken Spot exchange instance
 func NewSpotExchange(config Config) *SpotExchange {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	wsURL := "wss://ws.kraken.com/v2"
 
-	// Convert symbol to Kraken format (e.g., BTCUSDT -> BTC/USD)
-	krakenSymbol := convertToKrakenSymbol(config.Symbol)
-
 	ex := &SpotExchange{
-		symbol:     krakenSymbol,
-		wsURL:      wsURL,
+		symbol:     config.Symbol,
 		updateChan: make(chan *exchange.DepthUpdate, 5000),
-		done:       make(chan struct{}),
-		ctx:        ctx,
-		cancel:     cancel,
+		tradeChan:  make(chan *exchange.Trade, 1000),
+		book:       newLocalBook(bookDepth),
 	}
 
-	ex.health.Store(exchange.HealthStatus{
-		Connected:    false,
-		LastPing:     time.Time{},
-		MessageCount: 0,
-		ErrorCount:   0,
+	// Kraken recommends waiting at least 5 seconds before reconnecting
+	ex.ws = supervisor.New(supervisor.Config{
+		Name:         ex.GetName(),
+		URL:          wsURL,
+		Subscribe:    ex.subscribe,
+		Resync:       ex.awaitSnapshot,
+		OnMessage:    ex.handleMessage,
+		Ping:         ex.ping,
+		PingInterval: 30 * time.Second,
+		MinBackoff:   5 * time.Second,
 	})
 
 	return ex
@@ -70,78 +71,56 @@ func (e *SpotExchange) GetSymbol() string {
 	return e.symbol
 }
 
-// Connect establishes WebSocket connection to Kraken
+// Connect establishes WebSocket connection to Kraken; the supervisor reconnects it if it drops
 func (e *SpotExchange) Connect(ctx context.Context) error {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, e.wsURL, nil)
-	if err != nil {
-		e.incrementErrorCount()
-		return fmt.Errorf("websocket connection failed: %w", err)
-	}
-
-	e.wsConn = conn
-	e.updateConnectionStatus(true)
-	log.Printf("[%s] WebSocket connected successfully", e.GetName())
+	return e.ws.Start(ctx)
+}
 
+// subscribe subscribes to the book and trade channels; it runs on every connect
+func (e *SpotExchange) subscribe() error {
 	subscribeMsg := SubscribeRequest{
 		Method: "subscribe",
 		Params: SubscribeParams{
 			Channel:  "book",
 			Symbol:   []string{e.symbol},
-			Depth:    1000,
+			Depth:    bookDepth,
 			Snapshot: true,
 		},
 	}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
-		e.incrementErrorCount()
-		conn.Close()
-		return fmt.Errorf("failed to subscribe: %w", err)
+	if err := e.ws.WriteJSON(subscribeMsg); err != nil {
+		return err
 	}
 
 	log.Printf("[%s] Subscribed to book channel for %s", e.GetName(), e.symbol)
 
-	go e.readMessages()
-	go e.pingLoop()
-
-	return nil
-}
-
-// Close closes the WebSocket connection
-func (e *SpotExchange) Close() error {
-	if e.cancel != nil {
-		e.cancel()
+	// Live trades only; the snapshot would replay recent history as new prints
+	tradeSubscribeMsg := SubscribeRequest{
+		Method: "subscribe",
+		Params: SubscribeParams{
+			Channel:  "trade",
+			Symbol:   []string{e.symbol},
+			Snapshot: false,
+		},
 	}
 
-	// Close the done channel to signal all goroutines to stop
-	select {
-	case <-e.done:
-	default:
-		close(e.done)
+	if err := e.ws.WriteJSON(tradeSubscribeMsg); err != nil {
+		return fmt.Errorf("trades: %w", err)
 	}
 
-	if e.wsConn != nil {
-		err := e.wsConn.WriteMessage(websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-		if err != nil {
-			log.Printf("[%s] Error sending close message: %v", e.GetName(), err)
-		}
+	log.Printf("[%s] Subscribed to trade channel for %s", e.GetName(), e.symbol)
 
-		select {
-		case <-time.After(time.Second):
-		}
+	return nil
+}
 
-		e.updateConnectionStatus(false)
-		e.wsConn.Close()
-	}
+// Close closes the WebSocket connection
+func (e *SpotExchange) Close() error {
+	err := e.ws.Close()
 
-	// Close update channel after all goroutines have stopped
+	// The read goroutine has exited, so nothing sends on the channels anymore
 	close(e.updateChan)
-
-	return nil
+	close(e.tradeChan)
+	return err
 }
 
 // GetSnapshot fetches the initial orderbook snapshot via WebSocket
@@ -170,116 +149,245 @@ func (e *SpotExchange) GetSnapshot(ctx context.Context) (*exchange.Snapshot, err
 	}
 }
 
+// GetInstrument fetches the pair's trading rules via REST API
+func (e *SpotExchange) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.kraken.com/0/public/AssetPairs", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to get asset pairs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pairs AssetPairsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("failed to decode asset pairs: %w", err)
+	}
+	if len(pairs.Error) > 0 {
+		e.ws.IncrementErrorCount()
+		return nil, fmt.Errorf("asset pairs request failed: %s", strings.Join(pairs.Error, ", "))
+	}
+
+	for _, pair := range pairs.Result {
+		// REST wsnames still use Kraken's legacy asset codes, while WebSocket v2 uses the common ones
+		if normalizeKrakenAssets(pair.WSName) != e.symbol {
+			continue
+		}
+
+		tick, err := decimal.NewFromString(pair.TickSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tick size %q: %w", pair.TickSize, err)
+		}
+		inst := &exchange.Instrument{
+			Exchange:           e.GetName(),
+			Symbol:             e.symbol,
+			Type:               exchange.InstrumentSpot,
+			TickSize:           tick,
+			LotSize:            decimal.New(1, -pair.LotDecimals),
+			ContractMultiplier: decimal.NewFromInt(1),
+			BaseSizes:          true,
+		}
+		if base, quote, ok := strings.Cut(e.symbol, "/"); ok {
+			inst.BaseAsset, inst.QuoteAsset = base, quote
+		}
+		if pair.CostMin != "" {
+			if inst.MinNotional, err = decimal.NewFromString(pair.CostMin); err != nil {
+				return nil, fmt.Errorf("invalid cost minimum %q: %w", pair.CostMin, err)
+			}
+		}
+		return inst, nil
+	}
+
+	return nil, fmt.Errorf("pair %s not found in asset pairs", e.symbol)
+}
+
 // Updates returns a channel that receives depth updates
 func (e *SpotExchange) Updates() <-chan *exchange.DepthUpdate {
 	return e.updateChan
 }
 
+// Trades returns a channel that receives executed trades
+func (e *SpotExchange) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
 // IsConnected checks if the WebSocket connection is active
 func (e *SpotExchange) IsConnected() bool {
-	return e.wsConn != nil
+	return e.ws.IsConnected()
 }
 
 // Health returns connection health information
 func (e *SpotExchange) Health() exchange.HealthStatus {
-	if status, ok := e.health.Load().(exchange.HealthStatus); ok {
-		return status
-	}
-	return exchange.HealthStatus{}
+	return e.ws.Health()
 }
 
-// readMessages continuously reads WebSocket messages
-func (e *SpotExchange) readMessages() {
-	// Note: Don't close updateChan here since we may reconnect and reuse it
-	defer e.updateConnectionStatus(false)
+// handleMessage processes a book, trade or control message
+func (e *SpotExchange) handleMessage(_ int, message []byte) {
+	// Try to parse as subscription response first
+	var subResp SubscribeResponse
+	if err := json.Unmarshal(message, &subResp); err == nil && (subResp.Method == "subscribe" || subResp.Method == "unsubscribe") {
+		if !subResp.Success {
+			log.Printf("[%s] %s failed: %s", e.GetName(), subResp.Method, subResp.Error)
+		}
+		return
+	}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled, stopping message reading", e.GetName())
-			return
-		case <-e.done:
-			return
-		default:
-			e.wsConnMu.Lock()
-			conn := e.wsConn
-			e.wsConnMu.Unlock()
-
-			if conn == nil {
-				log.Printf("[%s] Connection is nil, triggering reconnection", e.GetName())
-				go e.reconnect()
-				return
-			}
+	// Try to parse as pong response
+	var pongResp PongResponse
+	if err := json.Unmarshal(message, &pongResp); err == nil && pongResp.Method == "pong" {
+		// Pong received, connection is alive
+		e.ws.UpdateLastPing()
+		return
+	}
 
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				e.incrementErrorCount()
-				log.Printf("[%s] WebSocket read error: %v", e.GetName(), err)
-				// Trigger reconnection instead of just returning
-				go e.reconnect()
-				return
-			}
+	// Try to parse as heartbeat message
+	var heartbeat HeartbeatMessage
+	if err := json.Unmarshal(message, &heartbeat); err == nil && heartbeat.Channel == "heartbeat" {
+		// Heartbeat received, connection is alive
+		e.ws.UpdateLastPing()
+		return
+	}
 
-			// Try to parse as subscription response first
-			var subResp SubscribeResponse
-			if err := json.Unmarshal(message, &subResp); err == nil && subResp.Method == "subscribe" {
-				if !subResp.Success {
-					log.Printf("[%s] Subscription failed: %s", e.GetName(), subResp.Error)
-				}
-				continue
+	// Try to parse as trade message
+	var trades TradeMessage
+	if err := json.Unmarshal(message, &trades); err == nil && trades.Channel == "trade" {
+		e.ws.IncrementMessageCount()
+		e.ws.UpdateLastPing()
+		for i := range trades.Data {
+			select {
+			case e.tradeChan <- e.convertTrade(&trades.Data[i]):
+			default:
+				// Trades are dropped if no one is consuming them
 			}
+		}
+		return
+	}
 
-			// Try to parse as pong response
-			var pongResp PongResponse
-			if err := json.Unmarshal(message, &pongResp); err == nil && pongResp.Method == "pong" {
-				// Pong received, connection is alive
-				e.updateLastPing()
-				continue
-			}
+	// Parse as data message
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		// Skip unknown message types silently
+		return
+	}
 
-			// Try to parse as heartbeat message
-			var heartbeat HeartbeatMessage
-			if err := json.Unmarshal(message, &heartbeat); err == nil && heartbeat.Channel == "heartbeat" {
-				// Heartbeat received, connection is alive
-				e.updateLastPing()
-				continue
-			}
+	if msg.Channel != "book" || len(msg.Data) == 0 {
+		return
+	}
 
-			// Parse as data message
-			var msg WSMessage
-			if err := json.Unmarshal(message, &msg); err != nil {
-				// Skip unknown message types silently
-				continue
-			}
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
 
-			if msg.Channel != "book" || len(msg.Data) == 0 {
-				continue
-			}
+	bookData := msg.Data[0]
 
-			e.incrementMessageCount()
-			e.updateLastPing()
+	switch msg.Type {
+	case "snapshot":
+		e.resyncing = false
+		if err := e.book.reset(&bookData); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to apply snapshot: %v", e.GetName(), err)
+			e.resync()
+			return
+		}
+		if !e.verifyChecksum(&bookData) {
+			return
+		}
 
-			bookData := msg.Data[0]
+		e.storeSnapshot(&bookData)
+		if !e.snapshotReceived {
+			e.snapshotReceived = true
+			return
+		}
 
-			if msg.Type == "snapshot" && !e.snapshotReceived {
-				e.storeSnapshot(&bookData)
-				e.snapshotReceived = true
-			}
+		// Snapshots after a resync or reconnect replace the whole downstream book
+		canonicalUpdate := e.convertDepthUpdate(&bookData, msg.Type)
+		canonicalUpdate.IsSnapshot = true
+		e.sendUpdate(canonicalUpdate)
 
-			if msg.Type == "update" {
-				canonicalUpdate := e.convertDepthUpdate(&bookData, msg.Type)
-
-				select {
-				case e.updateChan <- canonicalUpdate:
-				case <-e.ctx.Done():
-					return
-				case <-e.done:
-					return
-				default:
-					log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
-				}
-			}
+	case "update":
+		if e.resyncing {
+			// Updates until the fresh snapshot would apply to a book we know is wrong
+			return
 		}
+		if err := e.book.apply(&bookData); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to apply update: %v", e.GetName(), err)
+			e.resync()
+			return
+		}
+		if !e.verifyChecksum(&bookData) {
+			return
+		}
+
+		e.sendUpdate(e.convertDepthUpdate(&bookData, msg.Type))
+	}
+}
+
+// sendUpdate forwards an update downstream without blocking
+func (e *SpotExchange) sendUpdate(update *exchange.DepthUpdate) {
+	select {
+	case e.updateChan <- update:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
+	}
+}
+
+// awaitSnapshot runs after a reconnect: updates are dropped until the resubscribed
+// book channel sends its snapshot, which replaces the downstream book
+func (e *SpotExchange) awaitSnapshot() error {
+	e.resyncing = true
+	return nil
+}
+
+// verifyChecksum compares the local book against the checksum Kraken sent with a message,
+// triggering a resync on mismatch
+func (e *SpotExchange) verifyChecksum(data *BookData) bool {
+	local := e.book.checksum()
+	if int64(local) == data.Checksum {
+		return true
+	}
+
+	log.Printf("[%s] Checksum mismatch: expected %d, got %d. Resyncing book...", e.GetName(), data.Checksum, local)
+	e.ws.IncrementResyncCount()
+	e.resync()
+	return false
+}
+
+// resync resubscribes to the book channel so Kraken sends a fresh snapshot
+func (e *SpotExchange) resync() {
+	e.resyncing = true
+
+	unsubscribeMsg := UnsubscribeRequest{
+		Method: "unsubscribe",
+		Params: UnsubscribeParams{
+			Channel: "book",
+			Symbol:  []string{e.symbol},
+			Depth:   bookDepth,
+		},
+	}
+	subscribeMsg := SubscribeRequest{
+		Method: "subscribe",
+		Params: SubscribeParams{
+			Channel:  "book",
+			Symbol:   []string{e.symbol},
+			Depth:    bookDepth,
+			Snapshot: true,
+		},
+	}
+
+	if err := e.ws.WriteJSON(unsubscribeMsg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to unsubscribe from book channel: %v", e.GetName(), err)
+		return
+	}
+	if err := e.ws.WriteJSON(subscribeMsg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to resubscribe to book channel: %v", e.GetName(), err)
 	}
 }
 
@@ -288,16 +396,16 @@ func (e *SpotExchange) storeSnapshot(data *BookData) {
 	bids := make([]exchange.PriceLevel, len(data.Bids))
 	for i, bid := range data.Bids {
 		bids[i] = exchange.PriceLevel{
-			Price:    fmt.Sprintf("%.10f", bid.Price),
-			Quantity: fmt.Sprintf("%.10f", bid.Qty),
+			Price:    bid.Price.String(),
+			Quantity: bid.Qty.String(),
 		}
 	}
 
 	asks := make([]exchange.PriceLevel, len(data.Asks))
 	for i, ask := range data.Asks {
 		asks[i] = exchange.PriceLevel{
-			Price:    fmt.Sprintf("%.10f", ask.Price),
-			Quantity: fmt.Sprintf("%.10f", ask.Qty),
+			Price:    ask.Price.String(),
+			Quantity: ask.Qty.String(),
 		}
 	}
 
@@ -320,16 +428,16 @@ func (e *SpotExchange) convertDepthUpdate(data *BookData, msgType string) *excha
 	bids := make([]exchange.PriceLevel, len(data.Bids))
 	for i, bid := range data.Bids {
 		bids[i] = exchange.PriceLevel{
-			Price:    fmt.Sprintf("%.10f", bid.Price),
-			Quantity: fmt.Sprintf("%.10f", bid.Qty),
+			Price:    bid.Price.String(),
+			Quantity: bid.Qty.String(),
 		}
 	}
 
 	asks := make([]exchange.PriceLevel, len(data.Asks))
 	for i, ask := range data.Asks {
 		asks[i] = exchange.PriceLevel{
-			Price:    fmt.Sprintf("%.10f", ask.Price),
-			Quantity: fmt.Sprintf("%.10f", ask.Qty),
+			Price:    ask.Price.String(),
+			Quantity: ask.Qty.String(),
 		}
 	}
 
@@ -352,180 +460,50 @@ func (e *SpotExchange) convertDepthUpdate(data *BookData, msgType string) *excha
 	}
 }
 
-// pingLoop sends periodic ping messages to keep the connection alive
-func (e *SpotExchange) pingLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	reqID := 1
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-e.done:
-			return
-		case <-ticker.C:
-			e.wsConnMu.Lock()
-			conn := e.wsConn
-			e.wsConnMu.Unlock()
-
-			if conn == nil {
-				log.Printf("[%s] Ping loop: connection is nil, stopping ping loop", e.GetName())
-				return
-			}
-
-			pingMsg := PingRequest{
-				Method: "ping",
-				ReqID:  reqID,
-			}
-			reqID++
-
-			e.wsConnMu.Lock()
-			err := conn.WriteJSON(pingMsg)
-			e.wsConnMu.Unlock()
-
-			if err != nil {
-				log.Printf("[%s] Failed to send ping: %v", e.GetName(), err)
-				// Don't reconnect here, let readMessages handle it
-				return
-			}
-		}
+// convertTrade converts a Kraken trade to canonical format
+func (e *SpotExchange) convertTrade(trade *TradeData) *exchange.Trade {
+	side := exchange.TradeSideBuy
+	if trade.Side == "sell" {
+		side = exchange.TradeSideSell
 	}
-}
 
-// reconnect attempts to re-establish the WebSocket connection
-func (e *SpotExchange) reconnect() {
-	// Prevent multiple simultaneous reconnection attempts
-	if !e.reconnecting.CompareAndSwap(false, true) {
-		log.Printf("[%s] Reconnection already in progress, skipping", e.GetName())
-		return
+	timestamp, err := time.Parse(time.RFC3339Nano, trade.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
 	}
-	defer e.reconnecting.Store(false)
-
-	log.Printf("[%s] Starting reconnection...", e.GetName())
 
-	// Close existing connection if any
-	e.wsConnMu.Lock()
-	if e.wsConn != nil {
-		e.wsConn.Close()
-		e.wsConn = nil
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.Symbol,
+		TradeID:   strconv.FormatInt(trade.TradeID, 10),
+		Price:     trade.Price.String(),
+		Quantity:  trade.Qty.String(),
+		Side:      side,
+		Timestamp: timestamp,
 	}
-	e.wsConnMu.Unlock()
-
-	// Wait before reconnecting (Kraken recommends at least 5 seconds)
-	time.Sleep(5 * time.Second)
-
-	// Attempt to reconnect
-	maxAttempts := 10
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		select {
-		case <-e.ctx.Done():
-			log.Printf("[%s] Context cancelled during reconnection", e.GetName())
-			return
-		case <-e.done:
-			log.Printf("[%s] Done signal received during reconnection", e.GetName())
-			return
-		default:
-			log.Printf("[%s] Reconnection attempt %d/%d", e.GetName(), attempt, maxAttempts)
-
-			// Create new context for this connection attempt
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			err := e.Connect(ctx)
-			cancel()
-
-			if err == nil {
-				log.Printf("[%s] Reconnection successful!", e.GetName())
-
-				// Reset snapshot flag to get a fresh snapshot
-				e.snapshotMu.Lock()
-				e.snapshotReceived = false
-				e.snapshot = nil
-				e.snapshotMu.Unlock()
-
-				return
-			}
-
-			log.Printf("[%s] Reconnection attempt %d failed: %v", e.GetName(), attempt, err)
-
-			// Exponential backoff with max of 30 seconds
-			backoff := time.Duration(attempt) * 5 * time.Second
-			if backoff > 30*time.Second {
-				backoff = 30 * time.Second
-			}
-			time.Sleep(backoff)
-		}
-	}
-
-	log.Printf("[%s] Failed to reconnect after %d attempts, giving up", e.GetName(), maxAttempts)
 }
 
-// convertToKrakenSymbol converts various symbol formats to Kraken format
-// Examples: BTCUSDT -> BTC/USD, ETHUSDT -> ETH/USD, BTC/USD -> BTC/USD
-func convertToKrakenSymbol(symbol string) string {
-	// If already has slash, assume it's correct
-	if strings.Contains(symbol, "/") {
-		return strings.ToUpper(symbol)
-	}
-
-	symbol = strings.ToUpper(symbol)
-
-	// Common conversions for USDT pairs
-	if strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USDT")
-		return fmt.Sprintf("%s/USD", base)
-	}
-
-	// Common conversions for USD pairs (without T)
-	if strings.HasSuffix(symbol, "USD") && !strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USD")
-		return fmt.Sprintf("%s/USD", base)
-	}
+// ping sends Kraken's application-level ping
+func (e *SpotExchange) ping() error {
+	return e.ws.WriteJSON(PingRequest{
+		Method: "ping",
+		ReqID:  int(e.pingReqID.Add(1)),
+	})
+}
 
-	// Common conversions for EUR pairs
-	if strings.HasSuffix(symbol, "EUR") {
-		base := strings.TrimSuffix(symbol, "EUR")
-		return fmt.Sprintf("%s/EUR", base)
+// normalizeKrakenAssets replaces Kraken's legacy asset codes in a pair name (XBT/USD -> BTC/USD)
+func normalizeKrakenAssets(pair string) string {
+	base, quote, ok := strings.Cut(pair, "/")
+	if !ok {
+		return pair
 	}
 
-	// Common conversions for GBP pairs
-	if strings.HasSuffix(symbol, "GBP") {
-		base := strings.TrimSuffix(symbol, "GBP")
-		return fmt.Sprintf("%s/GBP", base)
+	legacy := map[string]string{"XBT": "BTC", "XDG": "DOGE"}
+	if name, ok := legacy[base]; ok {
+		base = name
 	}
-
-	// If we can't determine, return as-is and let Kraken reject it
-	log.Printf("[Kraken] Warning: Could not convert symbol %s to Kraken format, using as-is", symbol)
-	return symbol
-}
-
-// updateConnectionStatus updates the connection status in health
-func (e *SpotExchange) updateConnectionStatus(connected bool) {
-	status := e.Health()
-	status.Connected = connected
-	if !connected {
-		now := time.Now()
-		status.ReconnectTime = &now
+	if name, ok := legacy[quote]; ok {
+		quote = name
 	}
-	e.health.Store(status)
-}
-
-// incrementMessageCount increments the message count in health
-func (e *SpotExchange) incrementMessageCount() {
-	status := e.Health()
-	status.MessageCount++
-	e.health.Store(status)
-}
-
-// incrementErrorCount increments the error count in health
-func (e *SpotExchange) incrementErrorCount() {
-	status := e.Health()
-	status.ErrorCount++
-	e.health.Store(status)
-}
-
-// updateLastPing updates the last ping time in health
-func (e *SpotExchange) updateLastPing() {
-	status := e.Health()
-	status.LastPing = time.Now()
-	e.health.Store(status)
+	return base + "/" + quote
 }
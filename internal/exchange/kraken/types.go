@@ -1,8 +1,10 @@
 package kraken
 
+import "encoding/json"
+
 // Config holds configuration for Kraken exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTC/USD), as resolved by the symbols package
 }
 
 // SubscribeRequest represents a subscription request to Kraken WebSocket v2
@@ -16,11 +18,25 @@ type SubscribeRequest struct {
 type SubscribeParams struct {
 	Channel  string   `json:"channel"`
 	Symbol   []string `json:"symbol"`
-	Depth    int      `json:"depth"`
+	Depth    int      `json:"depth,omitempty"` // Book channel only
 	Snapshot bool     `json:"snapshot"`
 }
 
-// SubscribeResponse represents the subscription acknowledgement
+// UnsubscribeRequest represents an unsubscribe request to Kraken WebSocket v2
+type UnsubscribeRequest struct {
+	Method string            `json:"method"`
+	Params UnsubscribeParams `json:"params"`
+	ReqID  int               `json:"req_id,omitempty"`
+}
+
+// UnsubscribeParams holds the unsubscribe parameters
+type UnsubscribeParams struct {
+	Channel string   `json:"channel"`
+	Symbol  []string `json:"symbol"`
+	Depth   int      `json:"depth,omitempty"` // Book channel only
+}
+
+// SubscribeResponse represents the subscription (or unsubscription) acknowledgement
 type SubscribeResponse struct {
 	Method  string          `json:"method"`
 	Result  SubscribeResult `json:"result"`
@@ -57,9 +73,29 @@ type BookData struct {
 }
 
 // PriceQty represents a price level with price and quantity
+// Values are kept as json.Number because the book checksum is computed over their exact text
 type PriceQty struct {
-	Price float64 `json:"price"`
-	Qty   float64 `json:"qty"`
+	Price json.Number `json:"price"`
+	Qty   json.Number `json:"qty"`
+}
+
+// TradeMessage represents a trade channel message from Kraken
+type TradeMessage struct {
+	Channel string      `json:"channel"`
+	Type    string      `json:"type"` // "snapshot" or "update"
+	Data    []TradeData `json:"data"`
+}
+
+// TradeData represents a single executed trade
+// Price and quantity are kept as json.Number to avoid float rounding
+type TradeData struct {
+	Symbol    string      `json:"symbol"`
+	Side      string      `json:"side"` // Taker side: "buy" or "sell"
+	Price     json.Number `json:"price"`
+	Qty       json.Number `json:"qty"`
+	OrdType   string      `json:"ord_type"`
+	TradeID   int64       `json:"trade_id"`
+	Timestamp string      `json:"timestamp"`
 }
 
 // PingRequest represents a ping request to Kraken WebSocket
@@ -80,3 +116,18 @@ type PongResponse struct {
 type HeartbeatMessage struct {
 	Channel string `json:"channel"`
 }
+
+// AssetPairsResponse represents the REST API response for Kraken tradable asset pairs
+type AssetPairsResponse struct {
+	Error  []string             `json:"error"`
+	Result map[string]AssetPair `json:"result"`
+}
+
+// AssetPair represents the trading rules of a single Kraken pair
+type AssetPair struct {
+	Altname     string `json:"altname"`
+	WSName      string `json:"wsname"` // e.g., XBT/USD
+	LotDecimals int32  `json:"lot_decimals"`
+	TickSize    string `json:"tick_size"`
+	CostMin     string `json:"costmin"` // Minimum order value in the quote currency
+}
@@ -0,0 +1,114 @@
+package okx
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// bookDepth is the number of levels per side maintained by the books channel
+	bookDepth = 400
+	// checksumLevels is the number of levels per side covered by OKX's book checksum
+	checksumLevels = 25
+)
+
+// bookLevel is a price level kept exactly as OKX formatted it, since the checksum is
+// computed over the original strings
+type bookLevel struct {
+	price decimal.Decimal
+	px    string
+	sz    string
+}
+
+// localBook mirrors the books channel state so that the checksum sent with every push can be verified
+type localBook struct {
+	depth int
+	bids  []bookLevel // Sorted by price descending (best bid first)
+	asks  []bookLevel // Sorted by price ascending (best ask first)
+}
+
+func newLocalBook(depth int) *localBook {
+	return &localBook{depth: depth}
+}
+
+// reset replaces the book with a snapshot
+func (b *localBook) reset(data *BookData) error {
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+	return b.apply(data)
+}
+
+// apply applies an update; levels with zero quantity are removed
+func (b *localBook) apply(data *BookData) error {
+	var err error
+	for _, level := range data.Bids {
+		if b.bids, err = b.applyLevel(b.bids, level, true); err != nil {
+			return err
+		}
+	}
+	for _, level := range data.Asks {
+		if b.asks, err = b.applyLevel(b.asks, level, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *localBook) applyLevel(side []bookLevel, level []string, descending bool) ([]bookLevel, error) {
+	if len(level) < 2 {
+		return side, fmt.Errorf("malformed level %v", level)
+	}
+	price, err := decimal.NewFromString(level[0])
+	if err != nil {
+		return side, fmt.Errorf("invalid price %q: %w", level[0], err)
+	}
+	qty, err := decimal.NewFromString(level[1])
+	if err != nil {
+		return side, fmt.Errorf("invalid quantity %q: %w", level[1], err)
+	}
+
+	i := sort.Search(len(side), func(i int) bool {
+		if descending {
+			return side[i].price.LessThanOrEqual(price)
+		}
+		return side[i].price.GreaterThanOrEqual(price)
+	})
+	found := i < len(side) && side[i].price.Equal(price)
+
+	switch {
+	case qty.IsZero():
+		if found {
+			side = append(side[:i], side[i+1:]...)
+		}
+	case found:
+		side[i].px, side[i].sz = level[0], level[1]
+	default:
+		side = append(side, bookLevel{})
+		copy(side[i+1:], side[i:])
+		side[i] = bookLevel{price: price, px: level[0], sz: level[1]}
+	}
+
+	if b.depth > 0 && len(side) > b.depth {
+		side = side[:b.depth]
+	}
+	return side, nil
+}
+
+// checksum computes OKX's signed CRC32 over the top 25 levels, interleaved as
+// bid:bidSz:ask:askSz, continuing with the longer side once the shorter one runs out
+func (b *localBook) checksum() int32 {
+	fields := make([]string, 0, 4*checksumLevels)
+	for i := 0; i < checksumLevels; i++ {
+		if i < len(b.bids) {
+			fields = append(fields, b.bids[i].px, b.bids[i].sz)
+		}
+		if i < len(b.asks) {
+			fields = append(fields, b.asks[i].px, b.asks[i].sz)
+		}
+	}
+	return int32(crc32.ChecksumIEEE([]byte(strings.Join(fields, ":"))))
+}
@@ -0,0 +1,94 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+// FuturesExchange implements the Exchange interface for OKX perpetual swaps using the public WebSocket.
+// Book and trade sizes are converted from contracts to base units.
+type FuturesExchange struct {
+	*stream
+}
+
+// contractSpec converts swap sizes, quoted in contracts, to base units
+type contractSpec struct {
+	value   decimal.Decimal // Contract value
+	inverse bool            // Contract value is in quote currency (coin-margined swaps)
+}
+
+// NewFuturesExchange creates a new OKX perpetual swap exchange instance
+func NewFuturesExchange(config Config) *FuturesExchange {
+	instId := config.Symbol
+
+	// Funding, mark price, open interest and the underlying index arrive on their own channels
+	extraArgs := []SubscribeArg{
+		{Channel: "funding-rate", InstID: instId},
+		{Channel: "mark-price", InstID: instId},
+		{Channel: "open-interest", InstID: instId},
+		{Channel: "index-tickers", InstID: strings.TrimSuffix(instId, "-SWAP")},
+	}
+
+	return &FuturesExchange{
+		stream: newStream(exchange.OKXf, instId, "SWAP", extraArgs),
+	}
+}
+
+// Connect loads the contract value, so sizes can be converted to base units, and connects
+func (e *FuturesExchange) Connect(ctx context.Context) error {
+	if e.contract == nil {
+		instrument, err := e.fetchInstrumentData(ctx)
+		if err != nil {
+			e.ws.IncrementErrorCount()
+			return fmt.Errorf("failed to load contract spec: %w", err)
+		}
+		spec, err := parseContractSpec(instrument)
+		if err != nil {
+			return fmt.Errorf("failed to load contract spec: %w", err)
+		}
+		e.contract = spec
+	}
+	return e.stream.Connect(ctx)
+}
+
+// GetDerivativesInfo returns the latest funding, mark/index price and open interest pushed over the WebSocket
+func (e *FuturesExchange) GetDerivativesInfo(ctx context.Context) (*exchange.DerivativesInfo, error) {
+	info, ok := e.latestDerivatives()
+	if !ok {
+		return nil, fmt.Errorf("no derivatives data received yet for %s", e.instId)
+	}
+	return &info, nil
+}
+
+// parseContractSpec builds the size conversion for an instrument
+func parseContractSpec(instrument *InstrumentData) (*contractSpec, error) {
+	value, err := decimal.NewFromString(instrument.CtVal)
+	if err != nil || !value.IsPositive() {
+		return nil, fmt.Errorf("invalid contract value %q", instrument.CtVal)
+	}
+	return &contractSpec{value: value, inverse: instrument.CtType == "inverse"}, nil
+}
+
+// toBase converts a size in contracts to base units. Inverse contracts are worth a fixed
+// quote amount, so their base value depends on the price.
+func (c *contractSpec) toBase(price, size string) string {
+	qty, err := decimal.NewFromString(size)
+	if err != nil {
+		return size
+	}
+	qty = qty.Mul(c.value)
+
+	if c.inverse && !qty.IsZero() {
+		px, err := decimal.NewFromString(price)
+		if err != nil || !px.IsPositive() {
+			return size
+		}
+		qty = qty.Div(px)
+	}
+	return qty.String()
+}
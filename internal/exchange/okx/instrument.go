@@ -0,0 +1,106 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+const instrumentsBaseURL = "https://www.okx.com/api/v5/public/instruments"
+
+// GetInstrument fetches the instrument's trading rules via REST API
+func (e *stream) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	data, err := e.fetchInstrumentData(ctx)
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		return nil, err
+	}
+	inst, err := toInstrument(e.name, data)
+	if err != nil {
+		return nil, err
+	}
+	// Swap sizes are only in base units once Connect has loaded the contract value
+	inst.BaseSizes = data.InstType != "SWAP" || e.contract != nil
+	return inst, nil
+}
+
+// fetchInstrumentData fetches the instrument from the public instruments REST API
+func (e *stream) fetchInstrumentData(ctx context.Context) (*InstrumentData, error) {
+	url := fmt.Sprintf("%s?instType=%s&instId=%s", instrumentsBaseURL, e.instType, e.instId)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var instruments InstrumentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&instruments); err != nil {
+		return nil, fmt.Errorf("failed to decode instrument: %w", err)
+	}
+	if instruments.Code != "0" {
+		return nil, fmt.Errorf("API error: code=%s, msg=%s", instruments.Code, instruments.Msg)
+	}
+	if len(instruments.Data) == 0 {
+		return nil, fmt.Errorf("no instrument returned for %s", e.instId)
+	}
+
+	return &instruments.Data[0], nil
+}
+
+// toInstrument converts OKX instrument data into the canonical instrument. Swap sizes are
+// quoted in contracts of ContractMultiplier; the stream converts them with its contract spec.
+func toInstrument(name exchange.ExchangeName, data *InstrumentData) (*exchange.Instrument, error) {
+	tick, err := decimal.NewFromString(data.TickSz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tick size %q: %w", data.TickSz, err)
+	}
+	lot, err := decimal.NewFromString(data.LotSz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lot size %q: %w", data.LotSz, err)
+	}
+
+	inst := &exchange.Instrument{
+		Exchange:           name,
+		Symbol:             data.InstID,
+		Type:               exchange.InstrumentSpot,
+		BaseAsset:          data.BaseCcy,
+		QuoteAsset:         data.QuoteCcy,
+		TickSize:           tick,
+		LotSize:            lot,
+		ContractMultiplier: decimal.NewFromInt(1),
+	}
+
+	if data.InstType == "SWAP" {
+		spec, err := parseContractSpec(data)
+		if err != nil {
+			return nil, err
+		}
+		inst.ContractMultiplier = spec.value
+		inst.Type = exchange.InstrumentLinearPerp
+		if spec.inverse {
+			inst.Type = exchange.InstrumentInversePerp
+		}
+		// Swaps leave baseCcy/quoteCcy empty; the underlying names both (e.g., BTC-USD)
+		inst.BaseAsset, inst.QuoteAsset, _ = strings.Cut(data.Uly, "-")
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,488 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"orderbook/internal/exchange"
+	"orderbook/internal/exchange/supervisor"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsURL = "wss://ws.okx.com:8443/ws/v5/public"
+	// pingInterval keeps the connection alive; OKX drops connections idle for 30 seconds
+	pingInterval = 20 * time.Second
+)
+
+// stream is the OKX public WebSocket client shared by the spot and swap adapters.
+// It maintains the books channel with seqId/prevSeqId continuity and checksum validation,
+// resubscribing for a fresh snapshot whenever either check fails.
+type stream struct {
+	name             exchange.ExchangeName
+	instId           string         // OKX format (e.g., BTC-USDT or BTC-USDT-SWAP)
+	instType         string         // OKX instrument type (SPOT or SWAP)
+	extraArgs        []SubscribeArg // Channels subscribed besides books and trades
+	contract         *contractSpec  // Converts contract sizes to base units; nil for spot
+	ws               *supervisor.Supervisor
+	updateChan       chan *exchange.DepthUpdate
+	tradeChan        chan *exchange.Trade
+	snapshotReceived bool
+	snapshot         *exchange.Snapshot // Latest snapshot not yet handed out (guarded by snapshotMu)
+	snapshotTaken    bool               // GetSnapshot has returned the subscribe snapshot (guarded by snapshotMu)
+	snapshotWanted   bool               // GetSnapshot is waiting for a resubscription snapshot (guarded by snapshotMu)
+	snapshotMu       sync.Mutex
+	book             *localBook // Local copy used to verify checksums (read goroutine only)
+	lastSeqID        int64      // seqId of the last applied books push (read goroutine only)
+	resyncing        bool       // Waiting for a fresh snapshot after a failed check or reconnect (read goroutine only)
+	derivatives      exchange.DerivativesInfo
+	derivativesMu    sync.Mutex
+}
+
+func newStream(name exchange.ExchangeName, instId, instType string, extraArgs []SubscribeArg) *stream {
+	s := &stream{
+		name:        name,
+		instId:      instId,
+		instType:    instType,
+		extraArgs:   extraArgs,
+		updateChan:  make(chan *exchange.DepthUpdate, 5000),
+		tradeChan:   make(chan *exchange.Trade, 1000),
+		book:        newLocalBook(bookDepth),
+		derivatives: exchange.DerivativesInfo{Exchange: name, Symbol: instId},
+	}
+
+	s.ws = supervisor.New(supervisor.Config{
+		Name:         name,
+		URL:          wsURL,
+		Subscribe:    s.subscribe,
+		Resync:       s.awaitSnapshot,
+		OnMessage:    s.handleMessage,
+		Ping:         s.ping,
+		PingInterval: pingInterval,
+	})
+
+	return s
+}
+
+// GetName returns the exchange name
+func (e *stream) GetName() exchange.ExchangeName {
+	return e.name
+}
+
+// GetSymbol returns the trading symbol
+func (e *stream) GetSymbol() string {
+	return e.instId
+}
+
+// Connect establishes WebSocket connection to OKX; the supervisor reconnects it if it drops
+func (e *stream) Connect(ctx context.Context) error {
+	return e.ws.Start(ctx)
+}
+
+// subscribe subscribes to the book, trade and extra channels; it runs on every connect
+func (e *stream) subscribe() error {
+	args := append([]SubscribeArg{
+		{Channel: "books", InstID: e.instId},
+		{Channel: "trades", InstID: e.instId},
+	}, e.extraArgs...)
+
+	if err := e.ws.WriteJSON(SubscribeRequest{Op: "subscribe", Args: args}); err != nil {
+		return err
+	}
+
+	log.Printf("[%s] Subscribed to %d channels for %s", e.GetName(), len(args), e.instId)
+	return nil
+}
+
+// ping sends the text ping OKX expects; it answers with a text pong
+func (e *stream) ping() error {
+	return e.ws.WriteMessage(websocket.TextMessage, []byte("ping"))
+}
+
+// awaitSnapshot runs after a reconnect. Updates are dropped until the new subscription's
+// snapshot replaces the downstream book.
+func (e *stream) awaitSnapshot() error {
+	e.resyncing = true
+	return nil
+}
+
+// Close closes the WebSocket connection
+func (e *stream) Close() error {
+	err := e.ws.Close()
+
+	// The read goroutine has exited, so nothing sends on the channels anymore
+	close(e.updateChan)
+	close(e.tradeChan)
+	return err
+}
+
+// GetSnapshot waits for a books channel snapshot. The first call returns the one sent on
+// subscribe; later calls, e.g. to reinitialize a book that fell behind, have the books channel
+// resubscribed and wait for its fresh snapshot rather than returning one the book has already
+// moved past. A timeout leaves the book to retry on its next check.
+func (e *stream) GetSnapshot(ctx context.Context) (*exchange.Snapshot, error) {
+	log.Printf("[%s] Waiting for orderbook snapshot from WebSocket...", e.GetName())
+
+	e.snapshotMu.Lock()
+	if e.snapshotTaken {
+		e.snapshot = nil
+		e.snapshotWanted = true
+	}
+	e.snapshotTaken = true
+	e.snapshotMu.Unlock()
+
+	timeout := time.NewTimer(10 * time.Second)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout.C:
+			return nil, fmt.Errorf("timeout waiting for snapshot")
+		default:
+			e.snapshotMu.Lock()
+			snap := e.snapshot
+			e.snapshot = nil
+			e.snapshotMu.Unlock()
+
+			if snap != nil {
+				return snap, nil
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+}
+
+// Updates returns a channel that receives depth updates
+func (e *stream) Updates() <-chan *exchange.DepthUpdate {
+	return e.updateChan
+}
+
+// Trades returns a channel that receives executed trades
+func (e *stream) Trades() <-chan *exchange.Trade {
+	return e.tradeChan
+}
+
+// IsConnected checks if the WebSocket connection is active
+func (e *stream) IsConnected() bool {
+	return e.ws.IsConnected()
+}
+
+// Health returns connection health information
+func (e *stream) Health() exchange.HealthStatus {
+	return e.ws.Health()
+}
+
+// handleMessage processes a pong, event or channel push
+func (e *stream) handleMessage(_ int, message []byte) {
+	if string(message) == "pong" {
+		e.ws.UpdateLastPing()
+		return
+	}
+
+	var msg WSMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse message: %v", e.GetName(), err)
+		return
+	}
+
+	switch {
+	case msg.Event == "error":
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Error event: code=%s, msg=%s", e.GetName(), msg.Code, msg.Msg)
+		return
+	case msg.Event != "":
+		// Subscription acknowledgements
+		return
+	}
+
+	e.ws.IncrementMessageCount()
+	e.ws.UpdateLastPing()
+
+	switch msg.Arg.Channel {
+	case "books":
+		e.handleBook(&msg)
+	case "trades":
+		e.handleTrades(msg.Data)
+	default:
+		e.handleDerivatives(&msg)
+	}
+}
+
+// handleBook validates a books push against the local book and forwards it downstream
+func (e *stream) handleBook(msg *WSMessage) {
+	e.snapshotMu.Lock()
+	wanted := e.snapshotWanted
+	e.snapshotWanted = false
+	e.snapshotMu.Unlock()
+	if wanted {
+		// Resubscribed from the read goroutine, which owns the resync state
+		log.Printf("[%s] Fresh snapshot requested. Resyncing book...", e.GetName())
+		e.ws.IncrementResyncCount()
+		e.resync()
+		return
+	}
+
+	var books []BookData
+	if err := json.Unmarshal(msg.Data, &books); err != nil || len(books) == 0 {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse book data: %v", e.GetName(), err)
+		return
+	}
+	data := &books[0]
+
+	switch msg.Action {
+	case "snapshot":
+		e.resyncing = false
+		if err := e.book.reset(data); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to apply snapshot: %v", e.GetName(), err)
+			e.resync()
+			return
+		}
+		if !e.verifyChecksum(data) {
+			return
+		}
+		e.lastSeqID = data.SeqID
+
+		snapshot := e.convertSnapshot(data)
+		e.snapshotMu.Lock()
+		e.snapshot = snapshot
+		e.snapshotMu.Unlock()
+
+		if !e.snapshotReceived {
+			e.snapshotReceived = true
+			return
+		}
+
+		// Snapshots after a resync or reconnect replace the whole downstream book
+		e.sendUpdate(&exchange.DepthUpdate{
+			Exchange:      e.GetName(),
+			Symbol:        e.instId,
+			EventTime:     snapshot.Timestamp,
+			FirstUpdateID: data.SeqID,
+			FinalUpdateID: data.SeqID,
+			Bids:          snapshot.Bids,
+			Asks:          snapshot.Asks,
+			IsSnapshot:    true,
+		})
+
+	case "update":
+		if e.resyncing {
+			// Updates until the fresh snapshot would apply to a book we know is wrong
+			return
+		}
+		if data.PrevSeqID != e.lastSeqID {
+			log.Printf("[%s] Sequence gap: expected prevSeqId=%d, got %d. Resyncing book...",
+				e.GetName(), e.lastSeqID, data.PrevSeqID)
+			e.ws.IncrementResyncCount()
+			e.resync()
+			return
+		}
+		if err := e.book.apply(data); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to apply update: %v", e.GetName(), err)
+			e.resync()
+			return
+		}
+		if !e.verifyChecksum(data) {
+			return
+		}
+		e.lastSeqID = data.SeqID
+
+		// OKX pushes empty updates with an unchanged seqId when the book is idle
+		if len(data.Bids) == 0 && len(data.Asks) == 0 {
+			return
+		}
+		e.sendUpdate(e.convertDepthUpdate(data))
+	}
+}
+
+// verifyChecksum compares the local book against the checksum OKX sent with a push,
+// triggering a resync on mismatch
+func (e *stream) verifyChecksum(data *BookData) bool {
+	local := e.book.checksum()
+	if int64(local) == data.Checksum {
+		return true
+	}
+
+	log.Printf("[%s] Checksum mismatch: expected %d, got %d. Resyncing book...", e.GetName(), data.Checksum, local)
+	e.ws.IncrementResyncCount()
+	e.resync()
+	return false
+}
+
+// resync resubscribes to the books channel so OKX sends a fresh snapshot
+func (e *stream) resync() {
+	e.resyncing = true
+
+	args := []SubscribeArg{{Channel: "books", InstID: e.instId}}
+	for _, op := range []string{"unsubscribe", "subscribe"} {
+		if err := e.ws.WriteJSON(SubscribeRequest{Op: op, Args: args}); err != nil {
+			e.ws.IncrementErrorCount()
+			log.Printf("[%s] Failed to %s books channel: %v", e.GetName(), op, err)
+			return
+		}
+	}
+}
+
+// sendUpdate forwards an update downstream without blocking
+func (e *stream) sendUpdate(update *exchange.DepthUpdate) {
+	select {
+	case e.updateChan <- update:
+	default:
+		log.Printf("[%s] Warning: update channel full, skipping update", e.GetName())
+	}
+}
+
+// handleTrades converts a trades push and forwards each trade without blocking
+func (e *stream) handleTrades(raw json.RawMessage) {
+	var trades []TradeData
+	if err := json.Unmarshal(raw, &trades); err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse trades: %v", e.GetName(), err)
+		return
+	}
+
+	for i := range trades {
+		select {
+		case e.tradeChan <- e.convertTrade(&trades[i]):
+		default:
+			// Trades are dropped if no one is consuming them
+		}
+	}
+}
+
+// handleDerivatives merges funding, mark, index and open interest pushes into the latest derivatives info
+func (e *stream) handleDerivatives(msg *WSMessage) {
+	e.derivativesMu.Lock()
+	defer e.derivativesMu.Unlock()
+
+	var err error
+	switch msg.Arg.Channel {
+	case "funding-rate":
+		var data []FundingRateData
+		if err = json.Unmarshal(msg.Data, &data); err == nil && len(data) > 0 {
+			e.derivatives.FundingRate = data[0].FundingRate
+			if ms, err := strconv.ParseInt(data[0].FundingTime, 10, 64); err == nil {
+				e.derivatives.NextFundingTime = time.UnixMilli(ms)
+			}
+		}
+	case "mark-price":
+		var data []MarkPriceData
+		if err = json.Unmarshal(msg.Data, &data); err == nil && len(data) > 0 {
+			e.derivatives.MarkPrice = data[0].MarkPx
+		}
+	case "index-tickers":
+		var data []IndexTickerData
+		if err = json.Unmarshal(msg.Data, &data); err == nil && len(data) > 0 {
+			e.derivatives.IndexPrice = data[0].IdxPx
+		}
+	case "open-interest":
+		var data []OpenInterestData
+		if err = json.Unmarshal(msg.Data, &data); err == nil && len(data) > 0 {
+			e.derivatives.OpenInterest = data[0].OiCcy
+		}
+	default:
+		return
+	}
+
+	if err != nil {
+		e.ws.IncrementErrorCount()
+		log.Printf("[%s] Failed to parse %s data: %v", e.GetName(), msg.Arg.Channel, err)
+		return
+	}
+	e.derivatives.Timestamp = time.Now()
+}
+
+// latestDerivatives returns the derivatives info merged so far, and whether a mark price has arrived
+func (e *stream) latestDerivatives() (exchange.DerivativesInfo, bool) {
+	e.derivativesMu.Lock()
+	defer e.derivativesMu.Unlock()
+	return e.derivatives, e.derivatives.MarkPrice != ""
+}
+
+// convertSnapshot converts a books snapshot to canonical format
+func (e *stream) convertSnapshot(data *BookData) *exchange.Snapshot {
+	return &exchange.Snapshot{
+		Exchange:     e.GetName(),
+		Symbol:       e.instId,
+		LastUpdateID: data.SeqID,
+		Bids:         e.convertLevels(data.Bids),
+		Asks:         e.convertLevels(data.Asks),
+		Timestamp:    parseMillis(data.Ts),
+	}
+}
+
+// convertDepthUpdate converts a books update to canonical format. seqIds are not consecutive,
+// so the update claims every ID after the previous push.
+func (e *stream) convertDepthUpdate(data *BookData) *exchange.DepthUpdate {
+	return &exchange.DepthUpdate{
+		Exchange:      e.GetName(),
+		Symbol:        e.instId,
+		EventTime:     parseMillis(data.Ts),
+		FirstUpdateID: data.PrevSeqID + 1,
+		FinalUpdateID: data.SeqID,
+		PrevUpdateID:  data.PrevSeqID,
+		Bids:          e.convertLevels(data.Bids),
+		Asks:          e.convertLevels(data.Asks),
+	}
+}
+
+// convertLevels converts OKX levels to canonical price levels in base units
+func (e *stream) convertLevels(levels [][]string) []exchange.PriceLevel {
+	converted := make([]exchange.PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		converted = append(converted, exchange.PriceLevel{
+			Price:    level[0],
+			Quantity: e.baseQuantity(level[0], level[1]),
+		})
+	}
+	return converted
+}
+
+// convertTrade converts an OKX trade to canonical format
+func (e *stream) convertTrade(trade *TradeData) *exchange.Trade {
+	side := exchange.TradeSideBuy
+	if trade.Side == "sell" {
+		side = exchange.TradeSideSell
+	}
+
+	return &exchange.Trade{
+		Exchange:  e.GetName(),
+		Symbol:    trade.InstID,
+		TradeID:   trade.TradeID,
+		Price:     trade.Px,
+		Quantity:  e.baseQuantity(trade.Px, trade.Sz),
+		Side:      side,
+		Timestamp: parseMillis(trade.Ts),
+	}
+}
+
+// baseQuantity converts a size to base units; swap sizes are quoted in contracts
+func (e *stream) baseQuantity(price, size string) string {
+	if e.contract == nil {
+		return size
+	}
+	return e.contract.toBase(price, size)
+}
+
+// parseMillis parses an OKX millisecond timestamp, falling back to now
+func parseMillis(ts string) time.Time {
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMilli(ms)
+}
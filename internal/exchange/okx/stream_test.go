@@ -0,0 +1,235 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	"orderbook/internal/exchange"
+)
+
+// bookMessage builds a books push with a checksum computed from the expected resulting book
+func bookMessage(t *testing.T, action string, prevSeqID, seqID int64, bids, asks [][]string, checksumOver string) *WSMessage {
+	t.Helper()
+
+	data, err := json.Marshal([]BookData{{
+		Bids:      bids,
+		Asks:      asks,
+		Ts:        "1700000000000",
+		Checksum:  int64(int32(crc32.ChecksumIEEE([]byte(checksumOver)))),
+		PrevSeqID: prevSeqID,
+		SeqID:     seqID,
+	}})
+	if err != nil {
+		t.Fatalf("Failed to encode book data: %v", err)
+	}
+	return &WSMessage{Arg: SubscribeArg{Channel: "books"}, Action: action, Data: data}
+}
+
+func TestLocalBookChecksumInterleavesSides(t *testing.T) {
+	book := newLocalBook(bookDepth)
+	err := book.reset(&BookData{
+		Bids: [][]string{{"3366.1", "7", "0", "3"}, {"3366", "6", "3", "4"}},
+		Asks: [][]string{{"3366.8", "9", "10", "3"}},
+	})
+	if err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+
+	expected := int32(crc32.ChecksumIEEE([]byte("3366.1:7:3366.8:9:3366:6")))
+	if got := book.checksum(); got != expected {
+		t.Errorf("Expected checksum %d, got %d", expected, got)
+	}
+}
+
+func TestHandleBook(t *testing.T) {
+	e := NewSpotExchange(Config{Symbol: "BTC-USDT"})
+
+	snapshot := bookMessage(t, "snapshot", -1, 100,
+		[][]string{{"100", "1", "0", "1"}}, [][]string{{"101", "2", "0", "1"}}, "100:1:101:2")
+	e.handleBook(snapshot)
+
+	snap, err := e.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snap.LastUpdateID != 100 || len(snap.Bids) != 1 || len(snap.Asks) != 1 {
+		t.Errorf("Expected snapshot at seqId 100 with one level per side, got %+v", snap)
+	}
+
+	update := bookMessage(t, "update", 100, 105,
+		[][]string{{"100.5", "3", "0", "1"}}, nil, "100.5:3:101:2:100:1")
+	e.handleBook(update)
+
+	select {
+	case u := <-e.Updates():
+		if u.PrevUpdateID != 100 || u.FirstUpdateID != 101 || u.FinalUpdateID != 105 {
+			t.Errorf("Expected pu=100 U=101 u=105, got pu=%d U=%d u=%d", u.PrevUpdateID, u.FirstUpdateID, u.FinalUpdateID)
+		}
+	default:
+		t.Fatal("Expected an update to be forwarded")
+	}
+
+	tests := []struct {
+		name string
+		msg  *WSMessage
+	}{
+		{"sequence gap", bookMessage(t, "update", 104, 110, [][]string{{"99", "1", "0", "1"}}, nil, "")},
+		{"checksum mismatch", bookMessage(t, "update", 105, 110, [][]string{{"99", "1", "0", "1"}}, nil, "wrong")},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.resyncing = false
+			e.handleBook(tt.msg)
+
+			if !e.resyncing {
+				t.Error("Expected a resync to be requested")
+			}
+			if got := e.Health().ResyncCount; got != int64(i+1) {
+				t.Errorf("Expected %d resyncs, got %d", i+1, got)
+			}
+			if len(e.Updates()) != 0 {
+				t.Error("Expected the update to be dropped")
+			}
+		})
+	}
+
+	// The resubscription snapshot replaces the downstream book
+	e.handleBook(bookMessage(t, "snapshot", -1, 200,
+		[][]string{{"90", "1", "0", "1"}}, [][]string{{"91", "1", "0", "1"}}, "90:1:91:1"))
+
+	select {
+	case u := <-e.Updates():
+		if !u.IsSnapshot || u.FinalUpdateID != 200 {
+			t.Errorf("Expected a snapshot update at seqId 200, got %+v", u)
+		}
+	default:
+		t.Fatal("Expected the resync snapshot to be forwarded")
+	}
+	if e.resyncing {
+		t.Error("Expected resync to complete")
+	}
+}
+
+func TestGetSnapshotWaitsForFreshSnapshot(t *testing.T) {
+	e := NewSpotExchange(Config{Symbol: "BTC-USDT"})
+	e.handleBook(bookMessage(t, "snapshot", -1, 100,
+		[][]string{{"100", "1", "0", "1"}}, [][]string{{"101", "2", "0", "1"}}, "100:1:101:2"))
+
+	if snap, err := e.GetSnapshot(context.Background()); err != nil || snap.LastUpdateID != 100 {
+		t.Fatalf("Expected the subscribe snapshot, got %+v, %v", snap, err)
+	}
+
+	type result struct {
+		snap *exchange.Snapshot
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		snap, err := e.GetSnapshot(context.Background())
+		results <- result{snap, err}
+	}()
+
+	// The next books push resubscribes instead of being applied
+	deadline := time.Now().Add(time.Second)
+	for {
+		e.snapshotMu.Lock()
+		wanted := e.snapshotWanted
+		e.snapshotMu.Unlock()
+		if wanted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected GetSnapshot to request a fresh snapshot")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	e.handleBook(bookMessage(t, "update", 100, 105, [][]string{{"100.5", "3", "0", "1"}}, nil, "100.5:3:101:2:100:1"))
+	if !e.resyncing || len(e.Updates()) != 0 {
+		t.Fatal("Expected the push to trigger a resync and be dropped")
+	}
+
+	e.handleBook(bookMessage(t, "snapshot", -1, 200,
+		[][]string{{"90", "1", "0", "1"}}, [][]string{{"91", "1", "0", "1"}}, "90:1:91:1"))
+
+	select {
+	case r := <-results:
+		if r.err != nil || r.snap.LastUpdateID != 200 {
+			t.Errorf("Expected the resubscription snapshot at seqId 200, got %+v, %v", r.snap, r.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected GetSnapshot to return the fresh snapshot")
+	}
+}
+
+func TestContractSpecToBase(t *testing.T) {
+	tests := []struct {
+		name       string
+		instrument InstrumentData
+		price      string
+		size       string
+		expected   string
+	}{
+		{"linear", InstrumentData{CtType: "linear", CtVal: "0.01"}, "50000", "25", "0.25"},
+		{"inverse", InstrumentData{CtType: "inverse", CtVal: "100"}, "50000", "10", "0.02"},
+		{"removal", InstrumentData{CtType: "inverse", CtVal: "100"}, "50000", "0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseContractSpec(&tt.instrument)
+			if err != nil {
+				t.Fatalf("parseContractSpec failed: %v", err)
+			}
+			if got := spec.toBase(tt.price, tt.size); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestToInstrument(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       InstrumentData
+		instType   exchange.InstrumentType
+		base       string
+		quote      string
+		multiplier string
+	}{
+		{
+			name:       "spot",
+			data:       InstrumentData{InstID: "BTC-USDT", InstType: "SPOT", BaseCcy: "BTC", QuoteCcy: "USDT", TickSz: "0.1", LotSz: "0.00000001"},
+			instType:   exchange.InstrumentSpot,
+			base:       "BTC",
+			quote:      "USDT",
+			multiplier: "1",
+		},
+		{
+			name:       "inverse swap",
+			data:       InstrumentData{InstID: "BTC-USD-SWAP", InstType: "SWAP", Uly: "BTC-USD", TickSz: "0.1", LotSz: "1", CtType: "inverse", CtVal: "100"},
+			instType:   exchange.InstrumentInversePerp,
+			base:       "BTC",
+			quote:      "USD",
+			multiplier: "100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst, err := toInstrument(exchange.OKX, &tt.data)
+			if err != nil {
+				t.Fatalf("toInstrument failed: %v", err)
+			}
+			if inst.Type != tt.instType || inst.BaseAsset != tt.base || inst.QuoteAsset != tt.quote {
+				t.Errorf("Expected %s %s/%s, got %s %s/%s", tt.instType, tt.base, tt.quote, inst.Type, inst.BaseAsset, inst.QuoteAsset)
+			}
+			if inst.ContractMultiplier.String() != tt.multiplier {
+				t.Errorf("Expected multiplier %s, got %s", tt.multiplier, inst.ContractMultiplier)
+			}
+		})
+	}
+}
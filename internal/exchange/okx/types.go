@@ -1,20 +1,103 @@
 package okx
 
+import "encoding/json"
+
 // Config holds configuration for OKX exchange
 type Config struct {
-	Symbol string
+	Symbol string // Venue symbol (e.g., BTC-USDT or BTC-USDT-SWAP), as resolved by the symbols package
+}
+
+// SubscribeRequest represents a subscribe or unsubscribe request to the OKX public WebSocket
+type SubscribeRequest struct {
+	Op   string         `json:"op"` // "subscribe" or "unsubscribe"
+	Args []SubscribeArg `json:"args"`
+}
+
+// SubscribeArg identifies a channel subscription
+type SubscribeArg struct {
+	Channel string `json:"channel"`
+	InstID  string `json:"instId"`
+}
+
+// WSMessage represents a WebSocket message from OKX: either an event (subscription
+// acknowledgement or error) or a data push for a channel
+type WSMessage struct {
+	Event  string          `json:"event,omitempty"` // "subscribe", "unsubscribe" or "error"
+	Code   string          `json:"code,omitempty"`
+	Msg    string          `json:"msg,omitempty"`
+	Arg    SubscribeArg    `json:"arg"`
+	Action string          `json:"action,omitempty"` // Books only: "snapshot" or "update"
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// BookData represents a books channel push
+type BookData struct {
+	Asks      [][]string `json:"asks"` // [price, quantity, deprecated, order_count]
+	Bids      [][]string `json:"bids"` // [price, quantity, deprecated, order_count]
+	Ts        string     `json:"ts"`   // timestamp in ms
+	Checksum  int64      `json:"checksum"`
+	PrevSeqID int64      `json:"prevSeqId"` // -1 for snapshots
+	SeqID     int64      `json:"seqId"`
+}
+
+// TradeData represents a single trade on the trades channel
+type TradeData struct {
+	InstID  string `json:"instId"`
+	TradeID string `json:"tradeId"`
+	Px      string `json:"px"`
+	Sz      string `json:"sz"`
+	Side    string `json:"side"` // Taker side: "buy" or "sell"
+	Ts      string `json:"ts"`   // Trade time in ms
+}
+
+// FundingRateData represents a funding-rate channel push
+type FundingRateData struct {
+	InstID      string `json:"instId"`
+	FundingRate string `json:"fundingRate"`
+	FundingTime string `json:"fundingTime"` // Next settlement time in ms
+}
+
+// MarkPriceData represents a mark-price channel push
+type MarkPriceData struct {
+	InstID string `json:"instId"`
+	MarkPx string `json:"markPx"`
+	Ts     string `json:"ts"`
+}
+
+// IndexTickerData represents an index-tickers channel push
+type IndexTickerData struct {
+	InstID string `json:"instId"`
+	IdxPx  string `json:"idxPx"`
+	Ts     string `json:"ts"`
+}
+
+// OpenInterestData represents an open-interest channel push
+type OpenInterestData struct {
+	InstID string `json:"instId"`
+	Oi     string `json:"oi"`    // In contracts
+	OiCcy  string `json:"oiCcy"` // In base currency
+	Ts     string `json:"ts"`
 }
 
-// OrderBookResponse represents the REST API response for OKX order book
-type OrderBookResponse struct {
-	Code string          `json:"code"`
-	Msg  string          `json:"msg"`
-	Data []OrderBookData `json:"data"`
+// InstrumentsResponse represents the REST API response for OKX public instruments
+type InstrumentsResponse struct {
+	Code string           `json:"code"`
+	Msg  string           `json:"msg"`
+	Data []InstrumentData `json:"data"`
 }
 
-// OrderBookData represents the orderbook data in the REST response
-type OrderBookData struct {
-	Asks [][]string `json:"asks"` // [price, quantity, deprecated, order_count]
-	Bids [][]string `json:"bids"` // [price, quantity, deprecated, order_count]
-	Ts   string     `json:"ts"`   // timestamp
+// InstrumentData describes a single instrument
+type InstrumentData struct {
+	InstID    string `json:"instId"`
+	InstType  string `json:"instType"`  // "SPOT" or "SWAP"
+	BaseCcy   string `json:"baseCcy"`   // Spot only
+	QuoteCcy  string `json:"quoteCcy"`  // Spot only
+	SettleCcy string `json:"settleCcy"` // Swap only
+	Uly       string `json:"uly"`       // Swap underlying (e.g., BTC-USDT)
+	TickSz    string `json:"tickSz"`    // Price increment
+	LotSz     string `json:"lotSz"`     // Size increment, in base units (spot) or contracts (swap)
+	MinSz     string `json:"minSz"`     // Minimum order size
+	CtType    string `json:"ctType"`    // "linear" or "inverse"
+	CtVal     string `json:"ctVal"`     // Contract value
+	CtValCcy  string `json:"ctValCcy"`  // Currency the contract value is denominated in
 }
@@ -0,0 +1,449 @@
+// Package supervisor keeps exchange WebSocket connections alive. Adapters describe how to
+// subscribe and how to handle messages; the supervisor dials, pings, detects stale streams
+// and reconnects with jittered exponential backoff, resubscribing and re-syncing the book.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"orderbook/internal/exchange"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	handshakeTimeout = 10 * time.Second
+	writeTimeout     = 5 * time.Second
+
+	defaultPingInterval = 20 * time.Second
+	defaultStaleTimeout = 60 * time.Second
+	defaultMinBackoff   = time.Second
+	defaultMaxBackoff   = 30 * time.Second
+)
+
+// ErrNotConnected is returned by writes while there is no live connection
+var ErrNotConnected = errors.New("websocket not connected")
+
+// Config describes an adapter's WebSocket stream and the hooks the supervisor calls.
+// Subscribe, Resync and OnMessage never run concurrently with each other; after Start,
+// they all run on the supervisor's read goroutine.
+type Config struct {
+	Name   exchange.ExchangeName // Used to prefix log lines
+	URL    string
+	Header http.Header // Sent with every dial
+
+	// Subscribe sends the adapter's subscriptions. It runs after every dial, so
+	// subscriptions are restored on reconnect.
+	Subscribe func() error
+
+	// Resync runs after a reconnect has resubscribed, before any message from the new
+	// connection is handled. Adapters use it to drop state tied to the old stream and
+	// re-sync their book. An error drops the new connection and schedules another attempt.
+	Resync func() error
+
+	// OnMessage handles one message read from the connection
+	OnMessage func(messageType int, message []byte)
+
+	// Ping sends an application-level keepalive; nil sends WebSocket ping frames
+	Ping func() error
+
+	PingInterval time.Duration // Defaults to 20s
+	StaleTimeout time.Duration // Reconnect when nothing is read for this long; defaults to 60s
+	MinBackoff   time.Duration // Delay before the first reconnect attempt; defaults to 1s
+	MaxBackoff   time.Duration // Cap on the reconnect delay; defaults to 30s
+}
+
+// Supervisor owns an adapter's WebSocket connection and its health
+type Supervisor struct {
+	cfg      Config
+	dialer   websocket.Dialer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	conn     *websocket.Conn
+	connMu   sync.Mutex // Guards conn and running, and serializes writes
+	running  bool
+	stopped  chan struct{} // Closed when the read goroutine exits
+	health   exchange.HealthStatus
+	healthMu sync.Mutex
+}
+
+// New creates a supervisor; nothing is dialed until Start
+func New(cfg Config) *Supervisor {
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = defaultPingInterval
+	}
+	if cfg.StaleTimeout <= 0 {
+		cfg.StaleTimeout = defaultStaleTimeout
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaultMaxBackoff, cfg.MinBackoff)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Supervisor{
+		cfg:     cfg,
+		dialer:  websocket.Dialer{HandshakeTimeout: handshakeTimeout},
+		ctx:     ctx,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+}
+
+// Start dials and subscribes, then keeps the connection alive in the background until Close.
+// Only the first connection is attempted synchronously; its failure is returned.
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.connMu.Lock()
+	running := s.running
+	s.connMu.Unlock()
+	if running {
+		return fmt.Errorf("supervisor already started")
+	}
+
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.ctx.Err() != nil {
+		// Closed while subscribing
+		s.dropConnLocked(conn)
+		return fmt.Errorf("websocket connection failed: %w", s.ctx.Err())
+	}
+	s.running = true
+
+	s.setConnected(true)
+	log.Printf("[%s] WebSocket connected successfully", s.cfg.Name)
+
+	go s.run(conn)
+	return nil
+}
+
+// Close stops reconnecting, closes the connection gracefully and waits for the read
+// goroutine to exit, so no hook runs once Close returns
+func (s *Supervisor) Close() error {
+	s.cancel()
+
+	s.connMu.Lock()
+	conn := s.conn
+	s.conn = nil
+	running := s.running
+	s.connMu.Unlock()
+
+	var err error
+	if conn != nil {
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		if writeErr := conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeTimeout)); writeErr != nil {
+			log.Printf("[%s] Error sending close message: %v", s.cfg.Name, writeErr)
+		}
+		err = conn.Close()
+	}
+
+	if running {
+		<-s.stopped
+	}
+	s.setConnected(false)
+	return err
+}
+
+// WriteJSON sends v as JSON on the current connection
+func (s *Supervisor) WriteJSON(v interface{}) error {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if s.conn == nil {
+		return ErrNotConnected
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.conn.WriteJSON(v)
+}
+
+// WriteMessage sends a message of the given type on the current connection
+func (s *Supervisor) WriteMessage(messageType int, data []byte) error {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if s.conn == nil {
+		return ErrNotConnected
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.conn.WriteMessage(messageType, data)
+}
+
+// IsConnected reports whether there is a live connection
+func (s *Supervisor) IsConnected() bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	return s.conn != nil
+}
+
+// connect dials a new connection and sends the adapter's subscriptions on it
+func (s *Supervisor) connect(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := s.dialer.DialContext(ctx, s.cfg.URL, s.cfg.Header)
+	if err != nil {
+		s.IncrementErrorCount()
+		return nil, fmt.Errorf("websocket connection failed: %w", err)
+	}
+
+	s.connMu.Lock()
+	if s.ctx.Err() != nil {
+		// Closed while dialing
+		s.connMu.Unlock()
+		conn.Close()
+		return nil, fmt.Errorf("websocket connection failed: %w", s.ctx.Err())
+	}
+	s.conn = conn
+	s.connMu.Unlock()
+
+	if s.cfg.Subscribe != nil {
+		if err := s.cfg.Subscribe(); err != nil {
+			s.IncrementErrorCount()
+			s.dropConn(conn)
+			return nil, fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// run reads from conn and keeps replacing it when it drops, until Close
+func (s *Supervisor) run(conn *websocket.Conn) {
+	defer close(s.stopped)
+
+	for {
+		s.readMessages(conn)
+		s.dropConn(conn)
+		s.setConnected(false)
+
+		if conn = s.reconnect(); conn == nil {
+			return
+		}
+	}
+}
+
+// reconnect dials until a connection is subscribed and re-synced, waiting a jittered
+// exponential backoff before each attempt. It returns nil once the supervisor is closed.
+func (s *Supervisor) reconnect() *websocket.Conn {
+	for attempt := 1; ; attempt++ {
+		if s.ctx.Err() != nil {
+			return nil
+		}
+
+		delay := backoff(attempt, s.cfg.MinBackoff, s.cfg.MaxBackoff)
+		log.Printf("[%s] Reconnection attempt %d in %v", s.cfg.Name, attempt, delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, handshakeTimeout)
+		conn, err := s.connect(ctx)
+		cancel()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("[%s] Reconnection attempt %d failed: %v", s.cfg.Name, attempt, err)
+			continue
+		}
+
+		if s.cfg.Resync != nil {
+			if err := s.cfg.Resync(); err != nil {
+				s.IncrementErrorCount()
+				s.dropConn(conn)
+				if s.ctx.Err() != nil {
+					return nil
+				}
+				log.Printf("[%s] Resync after reconnect failed: %v", s.cfg.Name, err)
+				continue
+			}
+		}
+
+		s.recordReconnect()
+		log.Printf("[%s] Reconnected after %d attempt(s)", s.cfg.Name, attempt)
+		return conn
+	}
+}
+
+// readMessages hands messages to the adapter until the connection fails or goes stale
+func (s *Supervisor) readMessages(conn *websocket.Conn) {
+	extendDeadline := func() {
+		conn.SetReadDeadline(time.Now().Add(s.cfg.StaleTimeout))
+	}
+
+	// Control frames count as activity, so quiet markets are not mistaken for stale streams
+	conn.SetPongHandler(func(string) error {
+		extendDeadline()
+		s.UpdateLastPing()
+		return nil
+	})
+	conn.SetPingHandler(func(data string) error {
+		extendDeadline()
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeTimeout))
+		var netErr net.Error
+		if err == websocket.ErrCloseSent || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil
+		}
+		return err
+	})
+
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go s.pingLoop(conn, pingDone)
+
+	extendDeadline()
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			s.IncrementErrorCount()
+
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				log.Printf("[%s] No messages for %v, connection is stale", s.cfg.Name, s.cfg.StaleTimeout)
+			} else {
+				log.Printf("[%s] WebSocket read error: %v", s.cfg.Name, err)
+			}
+			return
+		}
+
+		extendDeadline()
+		if s.cfg.OnMessage != nil {
+			s.cfg.OnMessage(messageType, message)
+		}
+	}
+}
+
+// pingLoop sends keepalives on conn until done is closed. A failed ping closes the
+// connection, which ends the read loop and triggers a reconnect.
+func (s *Supervisor) pingLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(s.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			var err error
+			if s.cfg.Ping != nil {
+				err = s.cfg.Ping()
+			} else {
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+			}
+
+			if err != nil {
+				if s.ctx.Err() == nil {
+					log.Printf("[%s] Failed to send ping: %v", s.cfg.Name, err)
+				}
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// dropConn closes conn and clears it if it is still the current connection
+func (s *Supervisor) dropConn(conn *websocket.Conn) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	s.dropConnLocked(conn)
+}
+
+func (s *Supervisor) dropConnLocked(conn *websocket.Conn) {
+	if s.conn == conn {
+		s.conn = nil
+	}
+	conn.Close()
+}
+
+// backoff returns the delay before reconnect attempt n (starting at 1): doubling from
+// minDelay up to maxDelay, with the upper half jittered so adapters do not reconnect in lockstep
+func backoff(attempt int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)+1))
+}
+
+// Health returns connection health information
+func (s *Supervisor) Health() exchange.HealthStatus {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	return s.health
+}
+
+// IncrementMessageCount increments the message count in health
+func (s *Supervisor) IncrementMessageCount() {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		status.MessageCount++
+	})
+}
+
+// IncrementErrorCount increments the error count in health
+func (s *Supervisor) IncrementErrorCount() {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		status.ErrorCount++
+	})
+}
+
+// IncrementResyncCount increments the resync count in health
+func (s *Supervisor) IncrementResyncCount() {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		status.ResyncCount++
+	})
+}
+
+// UpdateLastPing updates the last ping time in health
+func (s *Supervisor) UpdateLastPing() {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		status.LastPing = time.Now()
+	})
+}
+
+// setConnected updates the connection status in health
+func (s *Supervisor) setConnected(connected bool) {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		status.Connected = connected
+	})
+}
+
+// recordReconnect marks a re-established connection in health
+func (s *Supervisor) recordReconnect() {
+	s.updateHealth(func(status *exchange.HealthStatus) {
+		now := time.Now()
+		status.Connected = true
+		status.ReconnectCount++
+		status.ReconnectTime = &now
+	})
+}
+
+func (s *Supervisor) updateHealth(fn func(status *exchange.HealthStatus)) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	fn(&s.health)
+}
@@ -0,0 +1,221 @@
+package supervisor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestServer starts a WebSocket server that hands the nth connection (starting at 1) to handle
+func newTestServer(t *testing.T, handle func(conn *websocket.Conn, n int)) string {
+	t.Helper()
+
+	var upgrader websocket.Upgrader
+	var count atomic.Int32
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		wg.Add(1)
+		defer wg.Done()
+		defer conn.Close()
+
+		go func() {
+			<-done
+			conn.Close()
+		}()
+		handle(conn, int(count.Add(1)))
+	}))
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+		wg.Wait()
+	})
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestReconnectResubscribesAndResyncs(t *testing.T) {
+	url := newTestServer(t, func(conn *websocket.Conn, n int) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		if n == 1 {
+			// Drop the first connection right after it subscribes
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	var s *Supervisor
+	var subscribes, resyncs atomic.Int32
+	messages := make(chan string, 10)
+	s = New(Config{
+		Name: "test",
+		URL:  url,
+		Subscribe: func() error {
+			subscribes.Add(1)
+			return s.WriteMessage(websocket.TextMessage, []byte("subscribe"))
+		},
+		Resync: func() error {
+			resyncs.Add(1)
+			return nil
+		},
+		OnMessage: func(_ int, message []byte) {
+			messages <- string(message)
+		},
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Close()
+
+	select {
+	case msg := <-messages:
+		if msg != "hello" {
+			t.Errorf("Expected hello, got %s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a message on the new connection")
+	}
+
+	if got := subscribes.Load(); got != 2 {
+		t.Errorf("Expected 2 subscribes, got %d", got)
+	}
+	if got := resyncs.Load(); got != 1 {
+		t.Errorf("Expected 1 resync, got %d", got)
+	}
+
+	health := s.Health()
+	if !health.Connected || health.ReconnectCount != 1 || health.ReconnectTime == nil {
+		t.Errorf("Expected a connected status after 1 reconnect, got %+v", health)
+	}
+}
+
+func TestStaleConnectionReconnects(t *testing.T) {
+	connected := make(chan int, 10)
+	url := newTestServer(t, func(conn *websocket.Conn, n int) {
+		connected <- n
+		// Never write, so the stream goes quiet
+		conn.ReadMessage()
+	})
+
+	s := New(Config{
+		Name:         "test",
+		URL:          url,
+		PingInterval: time.Hour,
+		StaleTimeout: 50 * time.Millisecond,
+		MinBackoff:   10 * time.Millisecond,
+		MaxBackoff:   20 * time.Millisecond,
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Close()
+
+	for want := 1; want <= 2; want++ {
+		select {
+		case n := <-connected:
+			if n != want {
+				t.Fatalf("Expected connection %d, got %d", want, n)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for connection %d", want)
+		}
+	}
+}
+
+func TestCloseStopsMessages(t *testing.T) {
+	url := newTestServer(t, func(conn *websocket.Conn, n int) {
+		for {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte("tick")); err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	var closed atomic.Bool
+	var late atomic.Int32
+	received := make(chan struct{}, 1)
+	s := New(Config{
+		Name: "test",
+		URL:  url,
+		OnMessage: func(int, []byte) {
+			if closed.Load() {
+				late.Add(1)
+			}
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a message")
+	}
+
+	s.Close()
+	closed.Store(true)
+	time.Sleep(20 * time.Millisecond)
+
+	if got := late.Load(); got != 0 {
+		t.Errorf("Expected no messages after Close, got %d", got)
+	}
+	if s.IsConnected() || s.Health().Connected {
+		t.Error("Expected the supervisor to be disconnected after Close")
+	}
+	if err := s.WriteMessage(websocket.TextMessage, nil); err != ErrNotConnected {
+		t.Errorf("Expected ErrNotConnected, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{50, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := backoff(tt.attempt, time.Second, 30*time.Second)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("Attempt %d: expected delay in [%v, %v], got %v", tt.attempt, tt.max/2, tt.max, got)
+			}
+		}
+	}
+}
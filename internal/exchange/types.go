@@ -3,6 +3,8 @@ package exchange
 import (
 	"context"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // ExchangeName represents supported exchange identifiers
@@ -16,6 +18,7 @@ const (
 	Kraken       ExchangeName = "kraken"
 	Hyperliquidf ExchangeName = "hyperliquidf"
 	OKX          ExchangeName = "okx"
+	OKXf         ExchangeName = "okxf"
 	Coinbase     ExchangeName = "coinbase"
 	Asterdexf    ExchangeName = "asterdexf"
 	BingX        ExchangeName = "bingx"
@@ -49,6 +52,27 @@ type Exchange interface {
 	Health() HealthStatus
 }
 
+// TradeProvider is implemented by adapters that stream executed trades alongside depth.
+// Check for it with a type assertion on an Exchange.
+type TradeProvider interface {
+	// Trades returns a channel that receives executed trades in canonical format
+	Trades() <-chan *Trade
+}
+
+// DerivativesProvider is implemented by perpetual futures adapters that expose
+// funding and contract data. Check for it with a type assertion on an Exchange.
+type DerivativesProvider interface {
+	// GetDerivativesInfo fetches the current funding rate, mark/index price and open interest
+	GetDerivativesInfo(ctx context.Context) (*DerivativesInfo, error)
+}
+
+// InstrumentProvider is implemented by adapters that can look up the trading rules of their
+// symbol. Check for it with a type assertion on an Exchange.
+type InstrumentProvider interface {
+	// GetInstrument fetches tick size, lot size, minimum notional and contract terms
+	GetInstrument(ctx context.Context) (*Instrument, error)
+}
+
 // Snapshot represents a canonical orderbook snapshot (normalized across exchanges)
 type Snapshot struct {
 	Exchange     ExchangeName // Exchange name
@@ -72,6 +96,62 @@ type DepthUpdate struct {
 	IsSnapshot    bool         // If true, this replaces the entire orderbook (not incremental)
 }
 
+// TradeSide is the aggressor (taker) side of a trade
+type TradeSide string
+
+const (
+	TradeSideBuy  TradeSide = "buy"  // Taker bought, lifting the ask
+	TradeSideSell TradeSide = "sell" // Taker sold, hitting the bid
+)
+
+// Trade represents a canonical executed trade (normalized across exchanges)
+type Trade struct {
+	Exchange  ExchangeName // Exchange name
+	Symbol    string       // Trading symbol
+	TradeID   string       // Venue trade ID (formats differ across venues)
+	Price     string       // Price as string to avoid precision loss
+	Quantity  string       // Quantity in base asset units
+	Side      TradeSide    // Aggressor side
+	Timestamp time.Time    // Execution time reported by the venue
+}
+
+// DerivativesInfo represents perpetual contract market data (normalized across exchanges)
+type DerivativesInfo struct {
+	Exchange        ExchangeName // Exchange name
+	Symbol          string       // Trading symbol
+	FundingRate     string       // Current funding rate per interval as a fraction (0.0001 = 0.01%)
+	NextFundingTime time.Time    // Next funding settlement
+	MarkPrice       string       // Mark price used for PnL and liquidations
+	IndexPrice      string       // Spot index (oracle) price
+	OpenInterest    string       // Open interest in base asset units
+	Timestamp       time.Time    // Time the data was fetched
+}
+
+// InstrumentType is the kind of market an adapter streams
+type InstrumentType string
+
+const (
+	InstrumentSpot        InstrumentType = "spot"
+	InstrumentLinearPerp  InstrumentType = "linear_perp"  // Margined and settled in the quote asset
+	InstrumentInversePerp InstrumentType = "inverse_perp" // Margined and settled in the base asset, contracts worth a fixed quote amount
+)
+
+// Instrument represents the trading rules of a symbol on one venue (normalized across exchanges).
+// Adapters of venues quoting sizes in contracts convert them to base units themselves and report
+// it through BaseSizes; the registry rejects instruments streamed in contracts.
+type Instrument struct {
+	Exchange           ExchangeName    // Exchange name
+	Symbol             string          // Venue symbol (e.g., BTC-USDT-SWAP)
+	Type               InstrumentType  // Spot, linear or inverse perpetual
+	BaseAsset          string          // Asset being traded (e.g., BTC)
+	QuoteAsset         string          // Asset prices are quoted in (e.g., USDT)
+	TickSize           decimal.Decimal // Minimum price increment
+	LotSize            decimal.Decimal // Minimum order size increment, in the venue's order unit
+	MinNotional        decimal.Decimal // Minimum order value in the quote asset; zero if the venue has none
+	ContractMultiplier decimal.Decimal // Base (linear) or quote (inverse) units per contract; 1 for spot
+	BaseSizes          bool            // Book and trade sizes are streamed in base units, not contracts
+}
+
 // PriceLevel represents a single price level [price, quantity]
 type PriceLevel struct {
 	Price    string // Price as string to avoid precision loss
@@ -80,9 +160,11 @@ type PriceLevel struct {
 
 // HealthStatus represents connection health information
 type HealthStatus struct {
-	Connected     bool
-	LastPing      time.Time
-	MessageCount  int64
-	ErrorCount    int64
-	ReconnectTime *time.Time
+	Connected      bool
+	LastPing       time.Time
+	MessageCount   int64
+	ErrorCount     int64
+	ResyncCount    int64      // Book resyncs triggered by a failed integrity check (checksum or sequence gap)
+	ReconnectCount int64      // Connections re-established after a drop
+	ReconnectTime  *time.Time // Time of the last reconnect
 }
@@ -12,70 +12,92 @@ import (
 	"orderbook/internal/exchange/hyperliquid"
 	"orderbook/internal/exchange/kraken"
 	"orderbook/internal/exchange/okx"
+	"orderbook/internal/symbols"
 )
 
+// defaultMapper resolves symbols when the configuration brings no mapper of its own
+var defaultMapper = symbols.NewMapper()
+
 // ExchangeConfig holds configuration for creating an exchange
 type ExchangeConfig struct {
 	Name   exchange.ExchangeName
-	Symbol string
+	Symbol symbols.Symbol
+	Mapper *symbols.Mapper // Optional; defaults to the built-in venue rules
 }
 
-// NewExchange creates a new exchange instance based on the configuration
+// NewExchange creates a new exchange instance for the venue's market of config.Symbol.
+// It returns an error wrapping symbols.ErrUnsupported if the venue does not list it.
 func NewExchange(config ExchangeConfig) (exchange.Exchange, error) {
+	mapper := config.Mapper
+	if mapper == nil {
+		mapper = defaultMapper
+	}
+
+	market, err := mapper.Resolve(config.Name, config.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	symbol := market.Native
+
 	switch config.Name {
 	case exchange.Binancef:
 		return binance.NewFuturesExchange(binance.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Binance:
 		return binance.NewSpotExchange(binance.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Bybitf:
 		return bybit.NewFuturesExchange(bybit.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Bybit:
 		return bybit.NewSpotExchange(bybit.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Kraken:
 		return kraken.NewSpotExchange(kraken.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.OKX:
 		return okx.NewSpotExchange(okx.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
+		}), nil
+
+	case exchange.OKXf:
+		return okx.NewFuturesExchange(okx.Config{
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Coinbase:
 		return coinbase.NewSpotExchange(coinbase.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Asterdexf:
 		return asterdex.NewFuturesExchange(asterdex.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.BingX:
 		return bingx.NewSpotExchange(bingx.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.BingXf:
 		return bingx.NewFuturesExchange(bingx.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	case exchange.Hyperliquidf:
 		return hyperliquid.NewFuturesExchange(hyperliquid.Config{
-			Symbol: config.Symbol,
+			Symbol: symbol,
 		}), nil
 
 	default:
@@ -86,7 +108,7 @@ func NewExchange(config ExchangeConfig) (exchange.Exchange, error) {
 // ValidateExchangeName checks if the exchange name is supported
 func ValidateExchangeName(name string) bool {
 	switch exchange.ExchangeName(name) {
-	case exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf:
+	case exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.OKXf, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf:
 		return true
 	default:
 		return false
@@ -95,10 +117,10 @@ func ValidateExchangeName(name string) bool {
 
 // GetSupportedExchanges returns a list of all supported exchanges
 func GetSupportedExchanges() []exchange.ExchangeName {
-	return []exchange.ExchangeName{exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf}
+	return []exchange.ExchangeName{exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.OKXf, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf}
 }
 
 // GetImplementedExchanges returns a list of currently implemented exchanges
 func GetImplementedExchanges() []exchange.ExchangeName {
-	return []exchange.ExchangeName{exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf}
+	return []exchange.ExchangeName{exchange.Binancef, exchange.Binance, exchange.Bybitf, exchange.Bybit, exchange.Kraken, exchange.Hyperliquidf, exchange.OKX, exchange.OKXf, exchange.Coinbase, exchange.Asterdexf, exchange.BingX, exchange.BingXf}
 }
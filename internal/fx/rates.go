@@ -0,0 +1,153 @@
+package fx
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// USD is the anchor every rate is expressed against
+const USD = "USD"
+
+// parQuotes are assumed to trade at one dollar until the books say otherwise
+var parQuotes = map[string]bool{"USD": true, "USDT": true, "USDC": true, "FDUSD": true}
+
+// Observation is one venue's mid price for the streamed base asset in the venue's quote
+type Observation struct {
+	Quote string
+	Mid   decimal.Decimal
+	Spot  bool // Perpetual mids carry a basis, so spot venues are preferred when deriving rates
+}
+
+// Rate is the dollar value of one unit of a quote asset
+type Rate struct {
+	Quote     string
+	USD       decimal.Decimal
+	Derived   bool      // False when the rate is assumed at par because no book priced it against USD
+	UpdatedAt time.Time // When the rate was last derived; zero for assumed rates
+}
+
+// Rates converts prices between the quote assets the venues trade in. Every venue streams the
+// same base asset, so the ratio between the median mids in two quotes is their exchange rate:
+// with BTC at 100,000 USD on Coinbase and 100,050 USDT on Binance, one USDT is worth 0.9995 USD.
+type Rates struct {
+	mu    sync.RWMutex
+	rates map[string]Rate
+}
+
+// NewRates creates a converter with the dollar stablecoins at par
+func NewRates() *Rates {
+	r := &Rates{rates: make(map[string]Rate)}
+	for quote := range parQuotes {
+		r.rates[quote] = Rate{Quote: quote, USD: decimal.NewFromInt(1)}
+	}
+	return r
+}
+
+// Update derives the rate of every observed quote against USD. Quotes that cannot be priced
+// this round, e.g. because no USD venue is running, keep their previous rate.
+func (r *Rates) Update(observations []Observation, at time.Time) {
+	usdMid, ok := medianMid(observations, USD)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, quote := range quotesOf(observations) {
+		if quote == USD {
+			continue
+		}
+		mid, ok := medianMid(observations, quote)
+		if !ok {
+			continue
+		}
+		r.rates[quote] = Rate{Quote: quote, USD: usdMid.Div(mid), Derived: true, UpdatedAt: at}
+	}
+}
+
+// Rate returns the value of one unit of from in to, and false if either quote is unknown
+func (r *Rates) Rate(from, to string) (decimal.Decimal, bool) {
+	if from == to {
+		return decimal.NewFromInt(1), true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return decimal.Zero, false
+	}
+	toRate, ok := r.rates[to]
+	if !ok {
+		return decimal.Zero, false
+	}
+	return fromRate.USD.Div(toRate.USD), true
+}
+
+// Known reports whether prices can be converted to or from quote
+func (r *Rates) Known(quote string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.rates[quote]
+	return ok
+}
+
+// All returns a copy of every rate against USD
+func (r *Rates) All() map[string]Rate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make(map[string]Rate, len(r.rates))
+	for quote, rate := range r.rates {
+		all[quote] = rate
+	}
+	return all
+}
+
+// quotesOf returns the distinct quotes observed
+func quotesOf(observations []Observation) []string {
+	seen := make(map[string]bool)
+	var quotes []string
+	for _, o := range observations {
+		if !seen[o.Quote] {
+			seen[o.Quote] = true
+			quotes = append(quotes, o.Quote)
+		}
+	}
+	return quotes
+}
+
+// medianMid returns the median mid of the venues quoting in quote, using only spot venues
+// when there are any
+func medianMid(observations []Observation, quote string) (decimal.Decimal, bool) {
+	var spot, all []decimal.Decimal
+	for _, o := range observations {
+		if o.Quote != quote || !o.Mid.IsPositive() {
+			continue
+		}
+		all = append(all, o.Mid)
+		if o.Spot {
+			spot = append(spot, o.Mid)
+		}
+	}
+
+	mids := all
+	if len(spot) > 0 {
+		mids = spot
+	}
+	if len(mids) == 0 {
+		return decimal.Zero, false
+	}
+
+	sort.Slice(mids, func(i, j int) bool { return mids[i].LessThan(mids[j]) })
+	n := len(mids)
+	if n%2 == 1 {
+		return mids[n/2], true
+	}
+	return mids[n/2-1].Add(mids[n/2]).Div(decimal.NewFromInt(2)), true
+}
@@ -0,0 +1,65 @@
+package fx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func observation(quote, mid string, spot bool) Observation {
+	return Observation{Quote: quote, Mid: decimal.RequireFromString(mid), Spot: spot}
+}
+
+func TestUpdateDerivesRatesFromMids(t *testing.T) {
+	rates := NewRates()
+	rates.Update([]Observation{
+		observation("USD", "100000", true),
+		observation("USD", "100010", true),
+		observation("USDT", "100050", true),
+		observation("USDT", "100200", false), // Perp basis is ignored while spot venues exist
+		observation("USDC", "99990", false),
+	}, time.Now())
+
+	tests := []struct {
+		from, to string
+		expected string
+	}{
+		{"USDT", "USD", "0.9995502248875562"},
+		{"USDC", "USD", "1.000150015001500150"},
+		{"USD", "USD", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"/"+tt.to, func(t *testing.T) {
+			rate, ok := rates.Rate(tt.from, tt.to)
+			if !ok {
+				t.Fatalf("Expected a rate for %s/%s", tt.from, tt.to)
+			}
+			if !rate.Round(12).Equal(decimal.RequireFromString(tt.expected).Round(12)) {
+				t.Errorf("Expected %s, got %s", tt.expected, rate)
+			}
+		})
+	}
+
+	if rate := rates.All()["USDT"]; !rate.Derived {
+		t.Error("Expected USDT to be derived from the books")
+	}
+}
+
+func TestUpdateWithoutUSDVenueKeepsRates(t *testing.T) {
+	rates := NewRates()
+	rates.Update([]Observation{observation("USDT", "100000", true)}, time.Now())
+
+	rate, ok := rates.Rate("USDT", "USD")
+	if !ok || !rate.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected USDT to stay at par, got %s (found=%v)", rate, ok)
+	}
+	if rates.All()["USDT"].Derived {
+		t.Error("Expected the par rate to be marked as assumed")
+	}
+
+	if _, ok := rates.Rate("EUR", "USD"); ok {
+		t.Error("Expected no rate for an unobserved quote")
+	}
+}
@@ -0,0 +1,208 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sample is the stats of one exchange at a point in time, with prices in the quote asset they
+// were recorded in so they can be converted when served
+type Sample struct {
+	Time  time.Time
+	Quote string // Empty if unknown
+	Stats types.Stats
+}
+
+// ring is a fixed-capacity buffer of samples, oldest first once full
+type ring struct {
+	samples []Sample
+	next    int // Slot the next sample is written to
+	full    bool
+}
+
+// add stores a sample, overwriting the oldest once the buffer is full
+func (r *ring) add(sample Sample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// last returns the newest sample
+func (r *ring) last() (Sample, bool) {
+	if !r.full && r.next == 0 {
+		return Sample{}, false
+	}
+	return r.samples[(r.next-1+len(r.samples))%len(r.samples)], true
+}
+
+// ordered returns the samples oldest first
+func (r *ring) ordered() []Sample {
+	if !r.full {
+		return r.samples[:r.next]
+	}
+	return append(r.samples[r.next:len(r.samples):len(r.samples)], r.samples[:r.next]...)
+}
+
+// Store keeps a ring buffer of periodic stats samples per exchange. Samples closer together
+// than the resolution are dropped, and the oldest are overwritten once the retention is covered.
+type Store struct {
+	mu         sync.RWMutex
+	resolution time.Duration
+	capacity   int
+	series     map[string]*ring
+}
+
+// NewStore creates a store sampling every resolution and keeping retention worth of samples
+func NewStore(resolution, retention time.Duration) *Store {
+	capacity := int(retention / resolution)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Store{
+		resolution: resolution,
+		capacity:   capacity,
+		series:     make(map[string]*ring),
+	}
+}
+
+// Resolution returns the interval between samples
+func (s *Store) Resolution() time.Duration {
+	return s.resolution
+}
+
+// Record adds a sample for an exchange, with prices in quote, unless the previous one is less
+// than a resolution old
+func (s *Store) Record(exchange, quote string, stats types.Stats, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.series[exchange]
+	if !ok {
+		r = &ring{samples: make([]Sample, s.capacity)}
+		s.series[exchange] = r
+	}
+	// Allow some jitter so a ticker firing at the resolution is never skipped
+	if last, ok := r.last(); ok && at.Sub(last.Time) < s.resolution*9/10 {
+		return
+	}
+	r.add(Sample{Time: at, Quote: quote, Stats: stats})
+}
+
+// Range returns the samples of an exchange between from and to inclusive, oldest first.
+// A zero from or to leaves that end open.
+func (s *Store) Range(exchange string, from, to time.Time) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.series[exchange]
+	if !ok {
+		return nil
+	}
+
+	samples := r.ordered()
+	start := 0
+	if !from.IsZero() {
+		start = sort.Search(len(samples), func(i int) bool { return !samples[i].Time.Before(from) })
+	}
+	end := len(samples)
+	if !to.IsZero() {
+		end = sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(to) })
+	}
+	if start >= end {
+		return nil
+	}
+	return append([]Sample(nil), samples[start:end]...)
+}
+
+// Exchanges returns the exchanges with recorded samples in name order
+func (s *Store) Exchanges() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.series))
+	for name := range s.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Clear drops every sample, e.g. when the symbol changes and the old series no longer apply
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series = make(map[string]*ring)
+}
+
+// metrics extract book-wide values from stats, keyed by their StatsMessage field name
+var metrics = map[string]func(types.Stats) decimal.Decimal{
+	"mid": func(s types.Stats) decimal.Decimal {
+		if s.BestBid.IsZero() || s.BestAsk.IsZero() {
+			return decimal.Zero
+		}
+		return s.BestBid.Add(s.BestAsk).Div(decimal.NewFromInt(2))
+	},
+	"spread":             func(s types.Stats) decimal.Decimal { return s.Spread },
+	"bestBid":            func(s types.Stats) decimal.Decimal { return s.BestBid },
+	"bestAsk":            func(s types.Stats) decimal.Decimal { return s.BestAsk },
+	"microprice":         func(s types.Stats) decimal.Decimal { return s.Microprice },
+	"weightedMid":        func(s types.Stats) decimal.Decimal { return s.WeightedMid },
+	"topImbalance":       func(s types.Stats) decimal.Decimal { return s.TopImbalance },
+	"orderFlowImbalance": func(s types.Stats) decimal.Decimal { return s.OrderFlowImbalance },
+	"totalBidsQty":       func(s types.Stats) decimal.Decimal { return s.TotalBidsQty },
+	"totalAsksQty":       func(s types.Stats) decimal.Decimal { return s.TotalAsksQty },
+	"totalDelta":         func(s types.Stats) decimal.Decimal { return s.TotalDelta },
+	"totalBidsNotional":  func(s types.Stats) decimal.Decimal { return s.TotalBidsNotional },
+	"totalAsksNotional":  func(s types.Stats) decimal.Decimal { return s.TotalAsksNotional },
+	"totalNotionalDelta": func(s types.Stats) decimal.Decimal { return s.TotalNotionalDelta },
+}
+
+// depthMetrics extract values of a single depth band
+var depthMetrics = map[string]func(types.DepthLiquidity) decimal.Decimal{
+	"bidQty":        func(d types.DepthLiquidity) decimal.Decimal { return d.BidQty },
+	"askQty":        func(d types.DepthLiquidity) decimal.Decimal { return d.AskQty },
+	"deltaQty":      func(d types.DepthLiquidity) decimal.Decimal { return d.DeltaQty },
+	"bidNotional":   func(d types.DepthLiquidity) decimal.Decimal { return d.BidNotional },
+	"askNotional":   func(d types.DepthLiquidity) decimal.Decimal { return d.AskNotional },
+	"deltaNotional": func(d types.DepthLiquidity) decimal.Decimal { return d.DeltaNotional },
+}
+
+// Metrics returns the names Metric accepts in name order
+func Metrics() []string {
+	names := make([]string, 0, len(metrics)+len(depthMetrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	for name := range depthMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Metric extracts a single value from stats. Depth metrics (bidQty, deltaNotional, ...) read
+// the band with the given label ("25bps", "2%"); it is ignored for the others.
+func Metric(stats types.Stats, name, band string) (decimal.Decimal, error) {
+	if metric, ok := metrics[name]; ok {
+		return metric(stats), nil
+	}
+
+	metric, ok := depthMetrics[name]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown metric %q", name)
+	}
+	for _, depth := range stats.Depth {
+		if depth.Band.String() == band {
+			return metric(depth), nil
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no %q depth band", band)
+}
@@ -0,0 +1,80 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+func statsWithSpread(spread int64) types.Stats {
+	return types.Stats{Spread: decimal.NewFromInt(spread)}
+}
+
+func TestRecordKeepsResolutionAndRetention(t *testing.T) {
+	store := NewStore(time.Second, 3*time.Second)
+	start := time.Unix(1_700_000_000, 0)
+
+	store.Record("binancef", "USDT", statsWithSpread(1), start)
+	store.Record("binancef", "USDT", statsWithSpread(99), start.Add(200*time.Millisecond)) // Within the resolution, dropped
+	for i := int64(2); i <= 4; i++ {
+		store.Record("binancef", "USDT", statsWithSpread(i), start.Add(time.Duration(i-1)*time.Second))
+	}
+
+	samples := store.Range("binancef", time.Time{}, time.Time{})
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 retained samples, got %d", len(samples))
+	}
+	for i, sample := range samples {
+		if expected := int64(i + 2); !sample.Stats.Spread.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("Sample %d: expected spread %d, got %s", i, expected, sample.Stats.Spread)
+		}
+	}
+
+	ranged := store.Range("binancef", start.Add(2*time.Second), start.Add(2*time.Second))
+	if len(ranged) != 1 || !ranged[0].Stats.Spread.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Expected only the sample at +2s, got %+v", ranged)
+	}
+
+	if samples := store.Range("bybit", time.Time{}, time.Time{}); samples != nil {
+		t.Errorf("Expected no samples for an unknown exchange, got %d", len(samples))
+	}
+}
+
+func TestMetric(t *testing.T) {
+	stats := types.Stats{
+		BestBid: decimal.NewFromInt(100),
+		BestAsk: decimal.NewFromInt(102),
+		Depth: []types.DepthLiquidity{
+			{Band: types.Bps(25), BidQty: decimal.NewFromInt(3)},
+			{Band: types.Percent(2), BidQty: decimal.NewFromInt(7)},
+		},
+	}
+
+	tests := []struct {
+		metric   string
+		band     string
+		expected string
+		wantErr  bool
+	}{
+		{metric: "mid", expected: "101"},
+		{metric: "bidQty", band: "2%", expected: "7"},
+		{metric: "bidQty", band: "25bps", expected: "3"},
+		{metric: "bidQty", band: "10%", wantErr: true},
+		{metric: "volume", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric+tt.band, func(t *testing.T) {
+			got, err := Metric(stats, tt.metric, tt.band)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !got.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package instruments
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+// Registry holds the trading rules of the instrument each exchange streams
+type Registry struct {
+	mu          sync.RWMutex
+	instruments map[string]exchange.Instrument
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{instruments: make(map[string]exchange.Instrument)}
+}
+
+// Load fetches an exchange's instrument, validates it and records it
+func (r *Registry) Load(ctx context.Context, provider exchange.InstrumentProvider) (exchange.Instrument, error) {
+	inst, err := provider.GetInstrument(ctx)
+	if err != nil {
+		return exchange.Instrument{}, err
+	}
+	if !inst.TickSize.IsPositive() {
+		return exchange.Instrument{}, fmt.Errorf("invalid tick size %s for %s", inst.TickSize, inst.Symbol)
+	}
+	// Depth sums and arbitrage sizes add quantities across venues, so they must share a unit
+	if !inst.BaseSizes {
+		return exchange.Instrument{}, fmt.Errorf("%s streams sizes in contracts of %s, not base units", inst.Symbol, inst.ContractMultiplier)
+	}
+
+	r.Set(*inst)
+	return *inst, nil
+}
+
+// Set records the instrument for its exchange
+func (r *Registry) Set(inst exchange.Instrument) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instruments[string(inst.Exchange)] = inst
+}
+
+// Get returns the instrument for an exchange
+func (r *Registry) Get(exchangeName string) (exchange.Instrument, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	inst, ok := r.instruments[exchangeName]
+	return inst, ok
+}
+
+// All returns a copy of the instruments of every exchange
+func (r *Registry) All() map[string]exchange.Instrument {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make(map[string]exchange.Instrument, len(r.instruments))
+	for name, inst := range r.instruments {
+		all[name] = inst
+	}
+	return all
+}
+
+// Delete removes an exchange, e.g. when its connection shuts down
+func (r *Registry) Delete(exchangeName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.instruments, exchangeName)
+}
+
+// CoarsestTick returns the largest tick size across all exchanges, so buckets built from it
+// are never finer than any venue's price grid
+func (r *Registry) CoarsestTick() (decimal.Decimal, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	coarsest := decimal.Zero
+	for _, inst := range r.instruments {
+		if inst.TickSize.GreaterThan(coarsest) {
+			coarsest = inst.TickSize
+		}
+	}
+	return coarsest, coarsest.IsPositive()
+}
@@ -0,0 +1,92 @@
+package instruments
+
+import (
+	"context"
+	"testing"
+
+	"orderbook/internal/exchange"
+
+	"github.com/shopspring/decimal"
+)
+
+type fakeProvider struct {
+	instrument exchange.Instrument
+}
+
+func (p *fakeProvider) GetInstrument(ctx context.Context) (*exchange.Instrument, error) {
+	inst := p.instrument
+	return &inst, nil
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		instrument exchange.Instrument
+		wantErr    bool
+	}{
+		{
+			name:       "valid spot",
+			instrument: exchange.Instrument{Exchange: exchange.Binance, TickSize: decimal.RequireFromString("0.01"), BaseSizes: true},
+		},
+		{
+			name: "swap converted to base units",
+			instrument: exchange.Instrument{
+				Exchange:           exchange.OKXf,
+				Type:               exchange.InstrumentLinearPerp,
+				TickSize:           decimal.RequireFromString("0.1"),
+				ContractMultiplier: decimal.RequireFromString("0.01"),
+				BaseSizes:          true,
+			},
+		},
+		{
+			name: "swap streamed in contracts",
+			instrument: exchange.Instrument{
+				Exchange:           exchange.OKXf,
+				Type:               exchange.InstrumentLinearPerp,
+				TickSize:           decimal.RequireFromString("0.1"),
+				ContractMultiplier: decimal.RequireFromString("0.01"),
+			},
+			wantErr: true,
+		},
+		{
+			name:       "missing tick size",
+			instrument: exchange.Instrument{Exchange: exchange.Binance, BaseSizes: true},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			_, err := registry.Load(context.Background(), &fakeProvider{instrument: tt.instrument})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+
+			_, ok := registry.Get(string(tt.instrument.Exchange))
+			if ok == tt.wantErr {
+				t.Errorf("Expected stored=%v, got %v", !tt.wantErr, ok)
+			}
+		})
+	}
+}
+
+func TestCoarsestTick(t *testing.T) {
+	registry := NewRegistry()
+	if _, ok := registry.CoarsestTick(); ok {
+		t.Error("Expected no tick for an empty registry")
+	}
+
+	registry.Set(exchange.Instrument{Exchange: exchange.Binance, TickSize: decimal.RequireFromString("0.01")})
+	registry.Set(exchange.Instrument{Exchange: exchange.Kraken, TickSize: decimal.RequireFromString("0.1")})
+
+	tick, ok := registry.CoarsestTick()
+	if !ok || !tick.Equal(decimal.RequireFromString("0.1")) {
+		t.Errorf("Expected coarsest tick 0.1, got %s (found=%v)", tick, ok)
+	}
+
+	registry.Delete("kraken")
+	if tick, _ := registry.CoarsestTick(); !tick.Equal(decimal.RequireFromString("0.01")) {
+		t.Errorf("Expected coarsest tick 0.01 after delete, got %s", tick)
+	}
+}
@@ -0,0 +1,68 @@
+package orderbook
+
+import (
+	"sort"
+	"sync"
+)
+
+// NamedBook is a venue's book together with the exchange it belongs to
+type NamedBook struct {
+	Exchange string
+	Book     *OrderBook
+}
+
+// Books is the set of venue books of one symbol, keyed by exchange. Venues join it once their
+// book is initialized and leave it when their connection closes, while the server, the
+// consolidated book and the arbitrage detector read it, so every access goes through its lock.
+type Books struct {
+	mu    sync.RWMutex
+	books map[string]*OrderBook
+}
+
+// NewBooks creates an empty set of venue books
+func NewBooks() *Books {
+	return &Books{books: make(map[string]*OrderBook)}
+}
+
+// Set adds a venue's book, replacing any book it had before
+func (b *Books) Set(exchange string, ob *OrderBook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.books[exchange] = ob
+}
+
+// Delete removes a venue's book
+func (b *Books) Delete(exchange string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.books, exchange)
+}
+
+// Get returns a venue's book
+func (b *Books) Get(exchange string) (*OrderBook, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ob, ok := b.books[exchange]
+	return ob, ok
+}
+
+// Len returns the number of venues in the set
+func (b *Books) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.books)
+}
+
+// Snapshot returns a copy of the set sorted by exchange, which callers may range over while
+// venues come and go
+func (b *Books) Snapshot() []NamedBook {
+	b.mu.RLock()
+	books := make([]NamedBook, 0, len(b.books))
+	for exchange, ob := range b.books {
+		books = append(books, NamedBook{Exchange: exchange, Book: ob})
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(books, func(i, j int) bool { return books[i].Exchange < books[j].Exchange })
+	return books
+}
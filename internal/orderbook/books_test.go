@@ -0,0 +1,57 @@
+package orderbook
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestBooksSnapshot(t *testing.T) {
+	books := NewBooks()
+	books.Set("okx", New())
+	books.Set("binance", New())
+	books.Set("bybit", New())
+	books.Delete("okx")
+
+	snapshot := books.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].Exchange != "binance" || snapshot[1].Exchange != "bybit" {
+		t.Fatalf("Expected binance and bybit in order, got %+v", snapshot)
+	}
+
+	books.Delete("binance")
+	if len(snapshot) != 2 {
+		t.Error("Expected a snapshot not to change once taken")
+	}
+	if _, ok := books.Get("binance"); ok {
+		t.Error("Expected a deleted venue not to be found")
+	}
+	if books.Len() != 1 {
+		t.Errorf("Expected 1 venue, got %d", books.Len())
+	}
+}
+
+func TestBooksConcurrentAccess(t *testing.T) {
+	books := NewBooks()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("venue%d", i)
+			for range 100 {
+				books.Set(name, New())
+				books.Delete(name)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				for _, venue := range books.Snapshot() {
+					books.Get(venue.Exchange)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,319 @@
+package orderbook
+
+import (
+	"math/rand/v2"
+
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// maxSkipLevel bounds the skip list height; 2^24 levels is far beyond any exchange depth
+	maxSkipLevel = 24
+	// skipProbability is the chance a node is promoted to the next level
+	skipProbability = 0.25
+)
+
+// levelNode is a single price level inside the skip list
+type levelNode struct {
+	level types.PriceLevel
+	raw   string // Price as the exchange sent it
+	next  []*levelNode
+}
+
+// priceLevels is one side of the book kept as a skip list ordered best price first.
+// Bids are ordered descending and asks ascending, so the head is always the best price
+// and top-N / range walks never touch levels outside the requested window.
+type priceLevels struct {
+	head       *levelNode
+	height     int
+	length     int
+	total      decimal.Decimal
+	notional   decimal.Decimal // Sum of price × quantity
+	bands      bandSums
+	descending bool
+}
+
+// bandSums is the quantity and notional of the levels inside each depth band. A level is inside
+// a band when it does not rank behind the band's edge, the price the band reaches out to.
+type bandSums struct {
+	edges    []decimal.Decimal // Narrowest band first; nil until SetBands is called
+	qty      []decimal.Decimal // Cumulative: each band includes the narrower ones
+	notional []decimal.Decimal
+}
+
+// newPriceLevels creates an empty side; descending is true for bids
+func newPriceLevels(descending bool) *priceLevels {
+	return &priceLevels{
+		head:       &levelNode{next: make([]*levelNode, maxSkipLevel)},
+		height:     1,
+		total:      decimal.Zero,
+		notional:   decimal.Zero,
+		descending: descending,
+	}
+}
+
+// before reports whether price a ranks ahead of price b on this side
+func (l *priceLevels) before(a, b decimal.Decimal) bool {
+	if l.descending {
+		return a.GreaterThan(b)
+	}
+	return a.LessThan(b)
+}
+
+// randomHeight picks the height of a new node
+func randomHeight() int {
+	h := 1
+	for h < maxSkipLevel && rand.Float64() < skipProbability {
+		h++
+	}
+	return h
+}
+
+// findPredecessors fills update with the last node on each level that ranks ahead of price
+func (l *priceLevels) findPredecessors(price decimal.Decimal, update []*levelNode) *levelNode {
+	x := l.head
+	for i := l.height - 1; i >= 0; i-- {
+		for x.next[i] != nil && l.before(x.next[i].level.Price, price) {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// Set inserts or replaces the level at price
+func (l *priceLevels) Set(price, qty decimal.Decimal) {
+	l.SetRaw(price.String(), price, qty)
+}
+
+// SetRaw inserts or replaces the level at price, keeping raw as the exchange's text for it.
+// A replaced level takes the text of the latest update.
+func (l *priceLevels) SetRaw(raw string, price, qty decimal.Decimal) {
+	var update [maxSkipLevel]*levelNode
+	candidate := l.findPredecessors(price, update[:])
+
+	if candidate != nil && candidate.level.Price.Equal(price) {
+		l.total = l.total.Sub(candidate.level.Quantity).Add(qty)
+		l.notional = l.notional.Add(price.Mul(qty.Sub(candidate.level.Quantity)))
+		l.addToBands(price, qty.Sub(candidate.level.Quantity))
+		candidate.level.Quantity = qty
+		candidate.raw = raw
+		return
+	}
+
+	h := randomHeight()
+	if h > l.height {
+		for i := l.height; i < h; i++ {
+			update[i] = l.head
+		}
+		l.height = h
+	}
+
+	node := &levelNode{
+		level: types.PriceLevel{Price: price, Quantity: qty},
+		raw:   raw,
+		next:  make([]*levelNode, h),
+	}
+	for i := 0; i < h; i++ {
+		node.next[i] = update[i].next[i]
+		update[i].next[i] = node
+	}
+
+	l.length++
+	l.total = l.total.Add(qty)
+	l.notional = l.notional.Add(price.Mul(qty))
+	l.addToBands(price, qty)
+}
+
+// Delete removes the level at price and reports whether it existed
+func (l *priceLevels) Delete(price decimal.Decimal) bool {
+	var update [maxSkipLevel]*levelNode
+	candidate := l.findPredecessors(price, update[:])
+
+	if candidate == nil || !candidate.level.Price.Equal(price) {
+		return false
+	}
+
+	for i := 0; i < l.height; i++ {
+		if update[i].next[i] != candidate {
+			break
+		}
+		update[i].next[i] = candidate.next[i]
+	}
+
+	for l.height > 1 && l.head.next[l.height-1] == nil {
+		l.height--
+	}
+
+	l.length--
+	l.total = l.total.Sub(candidate.level.Quantity)
+	l.notional = l.notional.Sub(candidate.level.Price.Mul(candidate.level.Quantity))
+	l.addToBands(price, candidate.level.Quantity.Neg())
+	return true
+}
+
+// Get returns the level at price if present
+func (l *priceLevels) Get(price decimal.Decimal) (types.PriceLevel, bool) {
+	candidate := l.findPredecessors(price, nil)
+	if candidate != nil && candidate.level.Price.Equal(price) {
+		return candidate.level, true
+	}
+	return types.PriceLevel{}, false
+}
+
+// Best returns the best level on this side (highest bid or lowest ask)
+func (l *priceLevels) Best() (types.PriceLevel, bool) {
+	if first := l.head.next[0]; first != nil {
+		return first.level, true
+	}
+	return types.PriceLevel{}, false
+}
+
+// Len returns the number of price levels
+func (l *priceLevels) Len() int {
+	return l.length
+}
+
+// Total returns the summed quantity of every level
+func (l *priceLevels) Total() decimal.Decimal {
+	return l.total
+}
+
+// Notional returns the summed price × quantity of every level
+func (l *priceLevels) Notional() decimal.Decimal {
+	return l.notional
+}
+
+// Raw returns a copy of every level keyed by the price text the exchange sent
+func (l *priceLevels) Raw() map[string]types.PriceLevel {
+	levels := make(map[string]types.PriceLevel, l.length)
+	for x := l.head.next[0]; x != nil; x = x.next[0] {
+		levels[x.raw] = x.level
+	}
+	return levels
+}
+
+// Clear removes every level
+func (l *priceLevels) Clear() {
+	for i := range l.head.next {
+		l.head.next[i] = nil
+	}
+	l.height = 1
+	l.length = 0
+	l.total = decimal.Zero
+	l.notional = decimal.Zero
+	for i := range l.bands.edges {
+		l.bands.qty[i] = decimal.Zero
+		l.bands.notional[i] = decimal.Zero
+	}
+}
+
+// SetBands moves the edge of each depth band, narrowest first. The sums inside each band are
+// kept up to date by Set and Delete, so moving an edge only walks the levels it passes over.
+func (l *priceLevels) SetBands(edges []decimal.Decimal) {
+	if len(edges) != len(l.bands.edges) {
+		l.bands = bandSums{
+			edges:    make([]decimal.Decimal, len(edges)),
+			qty:      make([]decimal.Decimal, len(edges)),
+			notional: make([]decimal.Decimal, len(edges)),
+		}
+		for i, edge := range edges {
+			l.bands.edges[i] = edge
+			l.Ascend(func(level types.PriceLevel) bool {
+				if l.before(edge, level.Price) {
+					return false
+				}
+				l.bands.qty[i] = l.bands.qty[i].Add(level.Quantity)
+				l.bands.notional[i] = l.bands.notional[i].Add(level.Price.Mul(level.Quantity))
+				return true
+			})
+		}
+		return
+	}
+
+	for i, edge := range edges {
+		old := l.bands.edges[i]
+		switch {
+		case l.before(old, edge):
+			qty, notional := l.sumBetween(old, edge)
+			l.bands.qty[i] = l.bands.qty[i].Add(qty)
+			l.bands.notional[i] = l.bands.notional[i].Add(notional)
+		case l.before(edge, old):
+			qty, notional := l.sumBetween(edge, old)
+			l.bands.qty[i] = l.bands.qty[i].Sub(qty)
+			l.bands.notional[i] = l.bands.notional[i].Sub(notional)
+		}
+		l.bands.edges[i] = edge
+	}
+}
+
+// Band returns the quantity and notional inside the i-th depth band set by SetBands
+func (l *priceLevels) Band(i int) (decimal.Decimal, decimal.Decimal) {
+	return l.bands.qty[i], l.bands.notional[i]
+}
+
+// addToBands adds a quantity change at price to every band the price is inside
+func (l *priceLevels) addToBands(price, qty decimal.Decimal) {
+	for i, edge := range l.bands.edges {
+		if l.before(edge, price) {
+			continue
+		}
+		l.bands.qty[i] = l.bands.qty[i].Add(qty)
+		l.bands.notional[i] = l.bands.notional[i].Add(price.Mul(qty))
+	}
+}
+
+// sumBetween returns the quantity and notional of the levels ranking behind from, up to and
+// including to
+func (l *priceLevels) sumBetween(from, to decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	qty, notional := decimal.Zero, decimal.Zero
+	l.AscendFrom(from, func(level types.PriceLevel) bool {
+		if level.Price.Equal(from) {
+			return true
+		}
+		if l.before(to, level.Price) {
+			return false
+		}
+		qty = qty.Add(level.Quantity)
+		notional = notional.Add(level.Price.Mul(level.Quantity))
+		return true
+	})
+	return qty, notional
+}
+
+// Ascend walks levels best price first until fn returns false
+func (l *priceLevels) Ascend(fn func(level types.PriceLevel) bool) {
+	for x := l.head.next[0]; x != nil; x = x.next[0] {
+		if !fn(x.level) {
+			return
+		}
+	}
+}
+
+// AscendFrom walks levels starting at the first one that does not rank ahead of price
+func (l *priceLevels) AscendFrom(price decimal.Decimal, fn func(level types.PriceLevel) bool) {
+	for x := l.findPredecessors(price, nil); x != nil; x = x.next[0] {
+		if !fn(x.level) {
+			return
+		}
+	}
+}
+
+// Top returns up to n levels best price first (all levels when n <= 0)
+func (l *priceLevels) Top(n int) []types.PriceLevel {
+	size := l.length
+	if n > 0 && n < size {
+		size = n
+	}
+
+	levels := make([]types.PriceLevel, 0, size)
+	l.Ascend(func(level types.PriceLevel) bool {
+		levels = append(levels, level)
+		return len(levels) < size
+	})
+	return levels
+}
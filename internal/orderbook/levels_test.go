@@ -0,0 +1,237 @@
+package orderbook
+
+import (
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/types"
+)
+
+func TestPriceLevelsOrdering(t *testing.T) {
+	tests := []struct {
+		name       string
+		descending bool
+		prices     []float64
+		expected   []float64
+	}{
+		{
+			name:       "Bids best first",
+			descending: true,
+			prices:     []float64{100, 102.5, 99, 101},
+			expected:   []float64{102.5, 101, 100, 99},
+		},
+		{
+			name:       "Asks best first",
+			descending: false,
+			prices:     []float64{103, 101.5, 105, 102},
+			expected:   []float64{101.5, 102, 103, 105},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			side := newPriceLevels(tt.descending)
+			for _, p := range tt.prices {
+				side.Set(decimal.NewFromFloat(p), decimal.NewFromInt(1))
+			}
+
+			levels := side.Top(0)
+			if len(levels) != len(tt.expected) {
+				t.Fatalf("Expected %d levels, got %d", len(tt.expected), len(levels))
+			}
+			for i, level := range levels {
+				if !level.Price.Equal(decimal.NewFromFloat(tt.expected[i])) {
+					t.Errorf("Level %d: expected price %g, got %s", i, tt.expected[i], level.Price.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPriceLevelsSetDelete(t *testing.T) {
+	side := newPriceLevels(false)
+
+	side.Set(decimal.NewFromInt(100), decimal.NewFromInt(1))
+	side.Set(decimal.NewFromInt(101), decimal.NewFromInt(2))
+	side.Set(decimal.RequireFromString("100.0"), decimal.NewFromInt(3)) // Same price, different string form
+
+	if side.Len() != 2 {
+		t.Errorf("Expected 2 levels, got %d", side.Len())
+	}
+	if !side.Total().Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected total 5, got %s", side.Total().String())
+	}
+	if !side.Notional().Equal(decimal.NewFromInt(502)) {
+		t.Errorf("Expected notional 502, got %s", side.Notional().String())
+	}
+
+	if !side.Delete(decimal.NewFromInt(100)) {
+		t.Error("Expected delete of existing level to succeed")
+	}
+	if side.Delete(decimal.NewFromInt(100)) {
+		t.Error("Expected delete of missing level to fail")
+	}
+
+	best, ok := side.Best()
+	if !ok || !best.Price.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Expected best 101, got %s", best.Price.String())
+	}
+	if !side.Total().Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected total 2, got %s", side.Total().String())
+	}
+	if !side.Notional().Equal(decimal.NewFromInt(202)) {
+		t.Errorf("Expected notional 202, got %s", side.Notional().String())
+	}
+}
+
+func TestPriceLevelsAscendFrom(t *testing.T) {
+	bids := newPriceLevels(true)
+	for i := 90; i <= 100; i++ {
+		bids.Set(decimal.NewFromInt(int64(i)), decimal.NewFromInt(1))
+	}
+
+	var got []int64
+	bids.AscendFrom(decimal.RequireFromString("97.5"), func(level types.PriceLevel) bool {
+		got = append(got, level.Price.IntPart())
+		return level.Price.GreaterThan(decimal.NewFromInt(95))
+	})
+
+	expected := []int64{97, 96, 95}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, got)
+			break
+		}
+	}
+}
+
+func TestPriceLevelsRandomized(t *testing.T) {
+	side := newPriceLevels(true)
+	reference := make(map[int64]int64)
+
+	for i := 0; i < 5000; i++ {
+		price := rand.Int64N(500)
+		if rand.IntN(3) == 0 {
+			side.Delete(decimal.NewFromInt(price))
+			delete(reference, price)
+		} else {
+			qty := rand.Int64N(10) + 1
+			side.Set(decimal.NewFromInt(price), decimal.NewFromInt(qty))
+			reference[price] = qty
+		}
+	}
+
+	prices := make([]int64, 0, len(reference))
+	total := int64(0)
+	for p, q := range reference {
+		prices = append(prices, p)
+		total += q
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] > prices[j] })
+
+	levels := side.Top(0)
+	if len(levels) != len(prices) {
+		t.Fatalf("Expected %d levels, got %d", len(prices), len(levels))
+	}
+	for i, level := range levels {
+		if level.Price.IntPart() != prices[i] || level.Quantity.IntPart() != reference[prices[i]] {
+			t.Fatalf("Level %d mismatch: got %s@%s", i, level.Quantity.String(), level.Price.String())
+		}
+	}
+	if side.Total().IntPart() != total {
+		t.Errorf("Expected total %d, got %s", total, side.Total().String())
+	}
+}
+
+func TestPriceLevelsBandsRandomized(t *testing.T) {
+	for _, descending := range []bool{true, false} {
+		side := newPriceLevels(descending)
+		reference := make(map[int64]int64)
+
+		// inside reports whether price does not rank behind edge
+		inside := func(price, edge int64) bool {
+			if descending {
+				return price >= edge
+			}
+			return price <= edge
+		}
+
+		edges := make([]decimal.Decimal, 3)
+		for i := 0; i < 5000; i++ {
+			price := rand.Int64N(500)
+			switch rand.IntN(4) {
+			case 0:
+				side.Delete(decimal.NewFromInt(price))
+				delete(reference, price)
+			case 1:
+				// Move the edges as a moving mid would, keeping them nested
+				start := rand.Int64N(500)
+				for j := range edges {
+					step := int64(j * 20)
+					if descending {
+						step = -step
+					}
+					edges[j] = decimal.NewFromInt(start + step)
+				}
+				side.SetBands(edges)
+			default:
+				qty := rand.Int64N(10) + 1
+				side.Set(decimal.NewFromInt(price), decimal.NewFromInt(qty))
+				reference[price] = qty
+			}
+		}
+		side.SetBands(edges)
+
+		for j, edge := range edges {
+			var qty, notional int64
+			for p, q := range reference {
+				if inside(p, edge.IntPart()) {
+					qty += q
+					notional += p * q
+				}
+			}
+			gotQty, gotNotional := side.Band(j)
+			if gotQty.IntPart() != qty || gotNotional.IntPart() != notional {
+				t.Errorf("Descending=%v band %d: expected %d / %d, got %s / %s", descending, j, qty, notional, gotQty, gotNotional)
+			}
+		}
+	}
+}
+
+// Benchmarks
+
+func BenchmarkPriceLevelsSet(b *testing.B) {
+	side := newPriceLevels(true)
+	prices := make([]decimal.Decimal, 1000)
+	for i := range prices {
+		prices[i] = decimal.NewFromFloat(50000 - float64(i)*0.1)
+	}
+	qty := decimal.NewFromInt(1)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		side.Set(prices[i%len(prices)], qty)
+	}
+}
+
+func BenchmarkPriceLevelsSetWithBands(b *testing.B) {
+	side := newPriceLevels(true)
+	prices := make([]decimal.Decimal, 1000)
+	for i := range prices {
+		prices[i] = decimal.NewFromFloat(50000 - float64(i)*0.1)
+	}
+	qty := decimal.NewFromInt(1)
+	side.SetBands([]decimal.Decimal{decimal.NewFromInt(49990), decimal.NewFromInt(49970), decimal.NewFromInt(49950)})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		side.Set(prices[i%len(prices)], qty)
+	}
+}
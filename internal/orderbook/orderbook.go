@@ -3,6 +3,7 @@ package orderbook
 import (
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -16,29 +17,25 @@ import (
 // OrderBook manages the real-time order book state
 type OrderBook struct {
 	mu           sync.RWMutex
-	bids         map[string]types.PriceLevel
-	asks         map[string]types.PriceLevel
+	bids         *priceLevels // Sorted by price descending (best bid first)
+	asks         *priceLevels // Sorted by price ascending (best ask first)
 	lastUpdateID int64
 	eventBuffer  []*exchange.DepthUpdate
 	initialized  bool
 	stats        types.Stats
 	currentTick  types.TickLevel
-	// Cached best bid/ask for performance
-	bestBid   decimal.Decimal
-	bestAsk   decimal.Decimal
-	bidLevels int
-	askLevels int
+	depthBands   []types.DepthBand // Sorted narrowest first
+	flow         orderFlow
 }
 
 // New creates a new OrderBook instance
 func New() *OrderBook {
 	return &OrderBook{
-		bids:        make(map[string]types.PriceLevel),
-		asks:        make(map[string]types.PriceLevel),
+		bids:        newPriceLevels(true),
+		asks:        newPriceLevels(false),
 		eventBuffer: make([]*exchange.DepthUpdate, 0),
 		currentTick: types.Tick1, // Default to 1.0 tick size
-		bestBid:     decimal.Zero,
-		bestAsk:     decimal.Zero,
+		depthBands:  types.DefaultDepthBands,
 		stats: types.Stats{
 			ConnectionTime: time.Now(),
 		},
@@ -51,10 +48,8 @@ func (ob *OrderBook) LoadSnapshot(snapshot *exchange.Snapshot) error {
 	defer ob.mu.Unlock()
 
 	ob.lastUpdateID = snapshot.LastUpdateID
-	ob.bids = make(map[string]types.PriceLevel)
-	ob.asks = make(map[string]types.PriceLevel)
-	ob.bestBid = decimal.Zero
-	ob.bestAsk = decimal.NewFromFloat(999999999)
+	ob.bids.Clear()
+	ob.asks.Clear()
 
 	for _, bid := range snapshot.Bids {
 		price, err := decimal.NewFromString(bid.Price)
@@ -66,11 +61,7 @@ func (ob *OrderBook) LoadSnapshot(snapshot *exchange.Snapshot) error {
 			return fmt.Errorf("invalid bid quantity %s: %w", bid.Quantity, err)
 		}
 		if !qty.IsZero() {
-			ob.bids[bid.Price] = types.PriceLevel{Price: price, Quantity: qty}
-			// Update best bid
-			if price.GreaterThan(ob.bestBid) {
-				ob.bestBid = price
-			}
+			ob.bids.SetRaw(bid.Price, price, qty)
 		}
 	}
 
@@ -84,15 +75,12 @@ func (ob *OrderBook) LoadSnapshot(snapshot *exchange.Snapshot) error {
 			return fmt.Errorf("invalid ask quantity %s: %w", ask.Quantity, err)
 		}
 		if !qty.IsZero() {
-			ob.asks[ask.Price] = types.PriceLevel{Price: price, Quantity: qty}
-			// Update best ask
-			if price.LessThan(ob.bestAsk) {
-				ob.bestAsk = price
-			}
+			ob.asks.SetRaw(ask.Price, price, qty)
 		}
 	}
 
-	ob.updateStats()
+	ob.flow.reset(ob.topOfBook())
+	ob.updateCachedStats()
 	return nil
 }
 
@@ -106,6 +94,16 @@ func (ob *OrderBook) HandleDepthUpdate(update *exchange.DepthUpdate) {
 		return
 	}
 
+	// A full book replacement does not depend on the events before it. Events buffered
+	// behind a gap may chain on from it; the rest are now stale.
+	if update.IsSnapshot {
+		ob.applyUpdate(update)
+		if len(ob.eventBuffer) > 0 {
+			ob.applyBufferedEvents()
+		}
+		return
+	}
+
 	expectedPrevID := ob.lastUpdateID
 	if update.PrevUpdateID != expectedPrevID {
 		if update.FirstUpdateID <= expectedPrevID+1 && update.FinalUpdateID > expectedPrevID {
@@ -127,7 +125,7 @@ func (ob *OrderBook) ProcessBufferedEvents() {
 	ob.mu.Lock()
 	defer ob.mu.Unlock()
 
-	// For exchanges without sequence IDs (like Coinbase), just apply all buffered events
+	// For exchanges without sequence IDs (like Kraken), just apply all buffered events
 	// and mark as initialized
 	if ob.lastUpdateID == 0 {
 		log.Printf("Exchange doesn't use update IDs, applying all %d buffered events", len(ob.eventBuffer))
@@ -139,6 +137,15 @@ func (ob *OrderBook) ProcessBufferedEvents() {
 		return
 	}
 
+	applied := ob.applyBufferedEvents()
+
+	ob.initialized = true
+	log.Printf("Orderbook initialized with %d valid events", applied)
+}
+
+// applyBufferedEvents applies the buffered events that chain on from lastUpdateID, in order,
+// and empties the buffer; events already covered or after a gap are dropped (must be called with mutex locked)
+func (ob *OrderBook) applyBufferedEvents() int {
 	validEvents := make([]*exchange.DepthUpdate, 0)
 
 	for _, event := range ob.eventBuffer {
@@ -148,34 +155,34 @@ func (ob *OrderBook) ProcessBufferedEvents() {
 			continue
 		}
 
-		if event.FirstUpdateID <= ob.lastUpdateID+1 && event.FinalUpdateID > ob.lastUpdateID {
-			validEvents = append(validEvents, event)
-			log.Printf("Found valid buffered event: U=%d, u=%d, lastUpdateId=%d",
-				event.FirstUpdateID, event.FinalUpdateID, ob.lastUpdateID)
-		}
+		validEvents = append(validEvents, event)
 	}
+	ob.eventBuffer = nil
 
 	if len(validEvents) == 0 {
 		log.Printf("No valid events found in buffer, dropping all and starting fresh")
-		ob.eventBuffer = nil
-		ob.initialized = true
-		return
+		return 0
 	}
 
 	sort.Slice(validEvents, func(i, j int) bool {
 		return validEvents[i].FirstUpdateID < validEvents[j].FirstUpdateID
 	})
 
-	ob.eventBuffer = nil
-
-	for _, event := range validEvents {
-		if event.FirstUpdateID <= ob.lastUpdateID+1 {
-			ob.applyUpdate(event)
+	// Apply the events that chain on from the snapshot; anything after a gap is dropped
+	applied := 0
+	for i, event := range validEvents {
+		if event.FinalUpdateID <= ob.lastUpdateID {
+			continue
+		}
+		if event.FirstUpdateID > ob.lastUpdateID+1 {
+			log.Printf("Gap in buffered events: U=%d, lastUpdateId=%d, dropping %d events",
+				event.FirstUpdateID, ob.lastUpdateID, len(validEvents)-i)
+			break
 		}
+		ob.applyUpdate(event)
+		applied++
 	}
-
-	ob.initialized = true
-	log.Printf("Orderbook initialized with %d valid events", len(validEvents))
+	return applied
 }
 
 // CheckAndReinitialize checks if the orderbook needs reinitialization
@@ -223,28 +230,91 @@ func (ob *OrderBook) GetTickLevel() types.TickLevel {
 	return ob.currentTick
 }
 
-// GetBids returns a copy of the current bid levels
+// SetDepthBands sets the distances from mid within which liquidity stats are measured
+func (ob *OrderBook) SetDepthBands(bands []types.DepthBand) {
+	sorted := slices.Clone(bands)
+	slices.Sort(sorted)
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.depthBands = slices.Compact(sorted)
+	ob.updateCachedStats()
+}
+
+// GetBids returns a copy of the current bid levels, keyed by the price text the exchange sent
 func (ob *OrderBook) GetBids() map[string]types.PriceLevel {
 	ob.mu.RLock()
 	defer ob.mu.RUnlock()
-
-	bids := make(map[string]types.PriceLevel)
-	for k, v := range ob.bids {
-		bids[k] = v
-	}
-	return bids
+	return ob.bids.Raw()
 }
 
-// GetAsks returns a copy of the current ask levels
+// GetAsks returns a copy of the current ask levels, keyed by the price text the exchange sent
 func (ob *OrderBook) GetAsks() map[string]types.PriceLevel {
 	ob.mu.RLock()
 	defer ob.mu.RUnlock()
+	return ob.asks.Raw()
+}
 
-	asks := make(map[string]types.PriceLevel)
-	for k, v := range ob.asks {
-		asks[k] = v
-	}
-	return asks
+// TopBids returns up to n best bid levels sorted by price descending (all levels when n <= 0)
+func (ob *OrderBook) TopBids(n int) []types.PriceLevel {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	return ob.bids.Top(n)
+}
+
+// TopAsks returns up to n best ask levels sorted by price ascending (all levels when n <= 0)
+func (ob *OrderBook) TopAsks(n int) []types.PriceLevel {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	return ob.asks.Top(n)
+}
+
+// BidsInRange returns bid levels with low <= price <= high, sorted by price descending
+func (ob *OrderBook) BidsInRange(low, high decimal.Decimal) []types.PriceLevel {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	levels := make([]types.PriceLevel, 0)
+	ob.bids.AscendFrom(high, func(level types.PriceLevel) bool {
+		if level.Price.LessThan(low) {
+			return false
+		}
+		levels = append(levels, level)
+		return true
+	})
+	return levels
+}
+
+// AsksInRange returns ask levels with low <= price <= high, sorted by price ascending
+func (ob *OrderBook) AsksInRange(low, high decimal.Decimal) []types.PriceLevel {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	levels := make([]types.PriceLevel, 0)
+	ob.asks.AscendFrom(low, func(level types.PriceLevel) bool {
+		if level.Price.GreaterThan(high) {
+			return false
+		}
+		levels = append(levels, level)
+		return true
+	})
+	return levels
+}
+
+// WalkBids calls fn for each bid level, best price first, until fn returns false.
+// The read lock is held for the whole walk, so fn must not call back into the orderbook.
+func (ob *OrderBook) WalkBids(fn func(level types.PriceLevel) bool) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	ob.bids.Ascend(fn)
+}
+
+// WalkAsks calls fn for each ask level, best price first, until fn returns false.
+// The read lock is held for the whole walk, so fn must not call back into the orderbook.
+func (ob *OrderBook) WalkAsks(fn func(level types.PriceLevel) bool) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	ob.asks.Ascend(fn)
 }
 
 // GetStats returns a copy of the current statistics
@@ -272,247 +342,134 @@ func (ob *OrderBook) GetBufferLength() int {
 func (ob *OrderBook) applyUpdate(update *exchange.DepthUpdate) {
 	// If this is a snapshot update, clear existing orderbook first
 	if update.IsSnapshot {
-		ob.bids = make(map[string]types.PriceLevel)
-		ob.asks = make(map[string]types.PriceLevel)
-		ob.bestBid = decimal.Zero
-		ob.bestAsk = decimal.NewFromFloat(999999999)
-	}
-
-	bestBidChanged := false
-	bestAskChanged := false
-
-	for _, bid := range update.Bids {
-		price := bid.Price
-		qty, _ := decimal.NewFromString(bid.Quantity)
-		priceDecimal, _ := decimal.NewFromString(price)
-
-		if qty.IsZero() {
-			// Remove bid level
-			if _, exists := ob.bids[price]; exists {
-				delete(ob.bids, price)
-				// Check if this was the best bid
-				if priceDecimal.Equal(ob.bestBid) {
-					bestBidChanged = true
-				}
-			}
-		} else {
-			// Add/update bid level
-			ob.bids[price] = types.PriceLevel{Price: priceDecimal, Quantity: qty}
-			// Check if this is a new best bid
-			if priceDecimal.GreaterThan(ob.bestBid) {
-				ob.bestBid = priceDecimal
-			}
-		}
+		ob.bids.Clear()
+		ob.asks.Clear()
 	}
 
-	for _, ask := range update.Asks {
-		price := ask.Price
-		qty, _ := decimal.NewFromString(ask.Quantity)
-		priceDecimal, _ := decimal.NewFromString(price)
-
-		if qty.IsZero() {
-			// Remove ask level
-			if _, exists := ob.asks[price]; exists {
-				delete(ob.asks, price)
-				// Check if this was the best ask
-				if priceDecimal.Equal(ob.bestAsk) {
-					bestAskChanged = true
-				}
-			}
-		} else {
-			// Add/update ask level
-			ob.asks[price] = types.PriceLevel{Price: priceDecimal, Quantity: qty}
-			// Check if this is a new best ask
-			if priceDecimal.LessThan(ob.bestAsk) {
-				ob.bestAsk = priceDecimal
-			}
-		}
-	}
-
-	// Recalculate best prices only if needed
-	if bestBidChanged {
-		ob.recalculateBestBid()
-	}
-	if bestAskChanged {
-		ob.recalculateBestAsk()
-	}
+	applyLevels(ob.bids, update.Bids)
+	applyLevels(ob.asks, update.Asks)
 
 	ob.lastUpdateID = update.FinalUpdateID
 	ob.stats.EventsProcessed++
 	ob.stats.LastEventTime = update.EventTime
-	ob.updateCachedStats()
-}
-
-// updateStats recalculates orderbook statistics (must be called with mutex locked)
-func (ob *OrderBook) updateStats() {
-	ob.bidLevels = len(ob.bids)
-	ob.askLevels = len(ob.asks)
-
-	ob.bestBid = decimal.Zero
-	ob.bestAsk = decimal.NewFromFloat(999999999)
-
-	if len(ob.bids) > 0 {
-		for _, level := range ob.bids {
-			if level.Price.GreaterThan(ob.bestBid) {
-				ob.bestBid = level.Price
-			}
-		}
-	}
 
-	if len(ob.asks) > 0 {
-		for _, level := range ob.asks {
-			if level.Price.LessThan(ob.bestAsk) {
-				ob.bestAsk = level.Price
-			}
-		}
-		if ob.bestAsk.Equal(decimal.NewFromFloat(999999999)) {
-			log.Printf("BUG: bestAsk still 999999999 after processing %d asks - first ask: %+v",
-				len(ob.asks), ob.getFirstAsk())
-			ob.bestAsk = decimal.Zero
-		}
+	// A replaced book says nothing about the flow since the previous one
+	bid, ask := ob.topOfBook()
+	if update.IsSnapshot {
+		ob.flow.reset(bid, ask)
 	} else {
-		ob.bestAsk = decimal.Zero
+		ob.flow.observe(bid, ask, ob.eventTime())
 	}
-
-	// Only log if there's a problem (bestAsk is zero but we have asks)
-	if ob.bestAsk.IsZero() && len(ob.asks) > 0 {
-		log.Printf("WARNING: bestAsk is ZERO but we have %d asks! bestBid=%s", len(ob.asks), ob.bestBid.String())
-	}
-
 	ob.updateCachedStats()
 }
 
-// getFirstAsk returns the first ask for debugging (must be called with mutex locked)
-func (ob *OrderBook) getFirstAsk() types.PriceLevel {
-	for _, level := range ob.asks {
-		return level
+// applyLevels writes changed levels into one side of the book, removing zero quantities
+func applyLevels(side *priceLevels, levels []exchange.PriceLevel) {
+	for _, level := range levels {
+		price, err := decimal.NewFromString(level.Price)
+		if err != nil {
+			continue
+		}
+		qty, _ := decimal.NewFromString(level.Quantity)
+
+		if qty.IsZero() {
+			side.Delete(price)
+		} else {
+			side.SetRaw(level.Price, price, qty)
+		}
 	}
-	return types.PriceLevel{}
 }
 
 // updateCachedStats updates the stats structure with cached values (must be called with mutex locked)
 func (ob *OrderBook) updateCachedStats() {
-	ob.stats.BidLevels = ob.bidLevels
-	ob.stats.AskLevels = ob.askLevels
+	bestBid, bestAsk := ob.bestPrices()
+
+	ob.stats.BidLevels = ob.bids.Len()
+	ob.stats.AskLevels = ob.asks.Len()
 	ob.stats.BufferedEvents = len(ob.eventBuffer)
-	ob.stats.BestBid = ob.bestBid
-	ob.stats.BestAsk = ob.bestAsk
+	ob.stats.BestBid = bestBid
+	ob.stats.BestAsk = bestAsk
 
-	if !ob.bestBid.IsZero() && !ob.bestAsk.IsZero() && ob.bestAsk.GreaterThan(ob.bestBid) {
-		ob.stats.Spread = ob.bestAsk.Sub(ob.bestBid)
+	if !bestBid.IsZero() && !bestAsk.IsZero() && bestAsk.GreaterThan(bestBid) {
+		ob.stats.Spread = bestAsk.Sub(bestBid)
 	} else {
 		ob.stats.Spread = decimal.Zero
 	}
 
 	// Calculate liquidity depth metrics
 	ob.calculateLiquidityDepth()
+	ob.calculateSignals()
 }
 
-// calculateLiquidityDepth calculates liquidity at various depth percentages (must be called with mutex locked)
+// bestPrices returns the best bid and ask, or zero for an empty side (must be called with mutex locked)
+func (ob *OrderBook) bestPrices() (decimal.Decimal, decimal.Decimal) {
+	bestBid, bestAsk := decimal.Zero, decimal.Zero
+	if level, ok := ob.bids.Best(); ok {
+		bestBid = level.Price
+	}
+	if level, ok := ob.asks.Best(); ok {
+		bestAsk = level.Price
+	}
+	return bestBid, bestAsk
+}
+
+// calculateLiquidityDepth calculates liquidity within each depth band (must be called with mutex locked)
 func (ob *OrderBook) calculateLiquidityDepth() {
-	if ob.bestBid.IsZero() || ob.bestAsk.IsZero() {
-		ob.stats.BidLiquidity05Pct = decimal.Zero
-		ob.stats.AskLiquidity05Pct = decimal.Zero
-		ob.stats.BidLiquidity2Pct = decimal.Zero
-		ob.stats.AskLiquidity2Pct = decimal.Zero
-		ob.stats.BidLiquidity10Pct = decimal.Zero
-		ob.stats.AskLiquidity10Pct = decimal.Zero
-		ob.stats.DeltaLiquidity05Pct = decimal.Zero
-		ob.stats.DeltaLiquidity2Pct = decimal.Zero
-		ob.stats.DeltaLiquidity10Pct = decimal.Zero
+	// Built fresh on every update since GetStats hands the slice out to callers
+	depth := make([]types.DepthLiquidity, len(ob.depthBands))
+	for i, band := range ob.depthBands {
+		depth[i] = types.DepthLiquidity{Band: band}
+	}
+
+	bestBid, bestAsk := ob.stats.BestBid, ob.stats.BestAsk
+	if bestBid.IsZero() || bestAsk.IsZero() {
+		ob.stats.Depth = depth
 		ob.stats.TotalBidsQty = decimal.Zero
 		ob.stats.TotalAsksQty = decimal.Zero
+		ob.stats.TotalDelta = decimal.Zero
+		ob.stats.TotalBidsNotional = decimal.Zero
+		ob.stats.TotalAsksNotional = decimal.Zero
+		ob.stats.TotalNotionalDelta = decimal.Zero
 		return
 	}
 
-	// Calculate mid price
-	midPrice := ob.bestBid.Add(ob.bestAsk).Div(decimal.NewFromInt(2))
-
-	// Calculate price thresholds
-	threshold05Pct := midPrice.Mul(decimal.NewFromFloat(0.005))
-	threshold2Pct := midPrice.Mul(decimal.NewFromFloat(0.02))
-	threshold10Pct := midPrice.Mul(decimal.NewFromFloat(0.10))
-
-	// Calculate bid side liquidity
-	bidLiq05 := decimal.Zero
-	bidLiq2 := decimal.Zero
-	bidLiq10 := decimal.Zero
-	totalBidsQty := decimal.Zero
-	minBid05Pct := midPrice.Sub(threshold05Pct)
-	minBid2Pct := midPrice.Sub(threshold2Pct)
-	minBid10Pct := midPrice.Sub(threshold10Pct)
-
-	for _, level := range ob.bids {
-		totalBidsQty = totalBidsQty.Add(level.Quantity)
-		if level.Price.GreaterThanOrEqual(minBid05Pct) {
-			bidLiq05 = bidLiq05.Add(level.Quantity)
-		}
-		if level.Price.GreaterThanOrEqual(minBid2Pct) {
-			bidLiq2 = bidLiq2.Add(level.Quantity)
-		}
-		if level.Price.GreaterThanOrEqual(minBid10Pct) {
-			bidLiq10 = bidLiq10.Add(level.Quantity)
-		}
+	// Calculate mid price and the price bounds of each band
+	midPrice := bestBid.Add(bestAsk).Div(decimal.NewFromInt(2))
+	minBids := make([]decimal.Decimal, len(ob.depthBands))
+	maxAsks := make([]decimal.Decimal, len(ob.depthBands))
+	for i, band := range ob.depthBands {
+		threshold := midPrice.Mul(band.Fraction())
+		minBids[i] = midPrice.Sub(threshold)
+		maxAsks[i] = midPrice.Add(threshold)
 	}
 
-	// Calculate ask side liquidity
-	askLiq05 := decimal.Zero
-	askLiq2 := decimal.Zero
-	askLiq10 := decimal.Zero
-	totalAsksQty := decimal.Zero
-	maxAsk05Pct := midPrice.Add(threshold05Pct)
-	maxAsk2Pct := midPrice.Add(threshold2Pct)
-	maxAsk10Pct := midPrice.Add(threshold10Pct)
-
-	for _, level := range ob.asks {
-		totalAsksQty = totalAsksQty.Add(level.Quantity)
-		if level.Price.LessThanOrEqual(maxAsk05Pct) {
-			askLiq05 = askLiq05.Add(level.Quantity)
-		}
-		if level.Price.LessThanOrEqual(maxAsk2Pct) {
-			askLiq2 = askLiq2.Add(level.Quantity)
-		}
-		if level.Price.LessThanOrEqual(maxAsk10Pct) {
-			askLiq10 = askLiq10.Add(level.Quantity)
-		}
+	// Band sums are maintained by the price level index; only the edges move with the mid
+	ob.bids.SetBands(minBids)
+	ob.asks.SetBands(maxAsks)
+
+	// Deltas: positive = more bid liquidity = bullish pressure
+	for i := range depth {
+		bidQty, bidNotional := ob.bids.Band(i)
+		askQty, askNotional := ob.asks.Band(i)
+		depth[i].BidQty = bidQty
+		depth[i].AskQty = askQty
+		depth[i].DeltaQty = bidQty.Sub(askQty)
+		depth[i].BidNotional = bidNotional
+		depth[i].AskNotional = askNotional
+		depth[i].DeltaNotional = bidNotional.Sub(askNotional)
 	}
+	ob.stats.Depth = depth
+
+	// Totals are maintained incrementally by the price level index
+	totalBidsQty := ob.bids.Total()
+	totalAsksQty := ob.asks.Total()
+	totalBidsNotional := ob.bids.Notional()
+	totalAsksNotional := ob.asks.Notional()
 
-	// Update stats
-	ob.stats.BidLiquidity05Pct = bidLiq05
-	ob.stats.AskLiquidity05Pct = askLiq05
-	ob.stats.BidLiquidity2Pct = bidLiq2
-	ob.stats.AskLiquidity2Pct = askLiq2
-	ob.stats.BidLiquidity10Pct = bidLiq10
-	ob.stats.AskLiquidity10Pct = askLiq10
 	ob.stats.TotalBidsQty = totalBidsQty
 	ob.stats.TotalAsksQty = totalAsksQty
-
-	// Calculate deltas (positive = more bid liquidity = bullish pressure)
-	ob.stats.DeltaLiquidity05Pct = bidLiq05.Sub(askLiq05)
-	ob.stats.DeltaLiquidity2Pct = bidLiq2.Sub(askLiq2)
-	ob.stats.DeltaLiquidity10Pct = bidLiq10.Sub(askLiq10)
 	ob.stats.TotalDelta = totalBidsQty.Sub(totalAsksQty)
-}
-
-// recalculateBestBid recalculates the best bid when the current best is removed
-func (ob *OrderBook) recalculateBestBid() {
-	ob.bestBid = decimal.Zero
-	for _, level := range ob.bids {
-		if level.Price.GreaterThan(ob.bestBid) {
-			ob.bestBid = level.Price
-		}
-	}
-}
-
-// recalculateBestAsk recalculates the best ask when the current best is removed
-func (ob *OrderBook) recalculateBestAsk() {
-	ob.bestAsk = decimal.NewFromFloat(999999999)
-	for _, level := range ob.asks {
-		if level.Price.LessThan(ob.bestAsk) {
-			ob.bestAsk = level.Price
-		}
-	}
-	if ob.bestAsk.Equal(decimal.NewFromFloat(999999999)) {
-		ob.bestAsk = decimal.Zero
-	}
+	ob.stats.TotalBidsNotional = totalBidsNotional
+	ob.stats.TotalAsksNotional = totalAsksNotional
+	ob.stats.TotalNotionalDelta = totalBidsNotional.Sub(totalAsksNotional)
 }
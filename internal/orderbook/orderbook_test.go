@@ -0,0 +1,417 @@
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+	"orderbook/internal/types"
+)
+
+// newTestBook builds an initialized book from [price, quantity] pairs
+func newTestBook(t *testing.T, bids, asks [][2]string) *OrderBook {
+	t.Helper()
+
+	snapshot := &exchange.Snapshot{LastUpdateID: 1}
+	for _, b := range bids {
+		snapshot.Bids = append(snapshot.Bids, exchange.PriceLevel{Price: b[0], Quantity: b[1]})
+	}
+	for _, a := range asks {
+		snapshot.Asks = append(snapshot.Asks, exchange.PriceLevel{Price: a[0], Quantity: a[1]})
+	}
+
+	ob := New()
+	if err := ob.LoadSnapshot(snapshot); err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	ob.ProcessBufferedEvents()
+	return ob
+}
+
+func TestLoadSnapshotStats(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}, {"99", "2"}, {"95", "3"}, {"80", "4"}},
+		[][2]string{{"101", "1"}, {"102", "2"}, {"105", "3"}, {"120", "4"}},
+	)
+
+	stats := ob.GetStats()
+	if len(stats.Depth) != 3 {
+		t.Fatalf("Expected the 3 default depth bands, got %d", len(stats.Depth))
+	}
+
+	checks := []struct {
+		name     string
+		got      decimal.Decimal
+		expected string
+	}{
+		{"BestBid", stats.BestBid, "100"},
+		{"BestAsk", stats.BestAsk, "101"},
+		{"Spread", stats.Spread, "1"},
+		{"BidQty 0.5%", stats.Depth[0].BidQty, "1"},
+		{"AskQty 0.5%", stats.Depth[0].AskQty, "1"},
+		{"BidQty 2%", stats.Depth[1].BidQty, "3"},
+		{"AskQty 2%", stats.Depth[1].AskQty, "3"},
+		{"BidQty 10%", stats.Depth[2].BidQty, "6"},
+		{"AskQty 10%", stats.Depth[2].AskQty, "6"},
+		{"TotalBidsQty", stats.TotalBidsQty, "10"},
+		{"TotalAsksQty", stats.TotalAsksQty, "10"},
+		{"BidNotional 0.5%", stats.Depth[0].BidNotional, "100"},
+		{"AskNotional 0.5%", stats.Depth[0].AskNotional, "101"},
+		{"BidNotional 2%", stats.Depth[1].BidNotional, "298"},
+		{"AskNotional 2%", stats.Depth[1].AskNotional, "305"},
+		{"BidNotional 10%", stats.Depth[2].BidNotional, "583"},
+		{"AskNotional 10%", stats.Depth[2].AskNotional, "620"},
+		{"DeltaNotional 10%", stats.Depth[2].DeltaNotional, "-37"},
+		{"TotalBidsNotional", stats.TotalBidsNotional, "903"},
+		{"TotalAsksNotional", stats.TotalAsksNotional, "1100"},
+	}
+
+	for _, c := range checks {
+		if !c.got.Equal(decimal.RequireFromString(c.expected)) {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, c.got.String())
+		}
+	}
+}
+
+func TestSetDepthBands(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}, {"99.8", "2"}, {"99.6", "3"}, {"99", "4"}},
+		[][2]string{{"100.2", "1"}, {"100.3", "2"}, {"101", "3"}},
+	)
+	ob.SetDepthBands([]types.DepthBand{types.Bps(50), types.Bps(10), types.Bps(25), types.Bps(10)})
+
+	// Mid is 100.1, so the bands reach 100.0, 99.85 and 99.6 below it
+	tests := []struct {
+		band   string
+		bidQty string
+		askQty string
+	}{
+		{"10bps", "1", "1"},
+		{"25bps", "1", "3"},
+		{"50bps", "6", "3"},
+	}
+
+	stats := ob.GetStats()
+	if len(stats.Depth) != len(tests) {
+		t.Fatalf("Expected %d sorted, de-duplicated bands, got %d", len(tests), len(stats.Depth))
+	}
+	for i, tt := range tests {
+		t.Run(tt.band, func(t *testing.T) {
+			depth := stats.Depth[i]
+			if depth.Band.String() != tt.band {
+				t.Errorf("Expected band %s, got %s", tt.band, depth.Band)
+			}
+			if !depth.BidQty.Equal(decimal.RequireFromString(tt.bidQty)) || !depth.AskQty.Equal(decimal.RequireFromString(tt.askQty)) {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.bidQty, tt.askQty, depth.BidQty, depth.AskQty)
+			}
+		})
+	}
+}
+
+func TestHandleDepthUpdateBestPrices(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}, {"99", "2"}},
+		[][2]string{{"101", "1"}, {"102", "2"}},
+	)
+
+	// Remove the best bid and ask, add a new deeper ask
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 2,
+		FinalUpdateID: 2,
+		PrevUpdateID:  1,
+		Bids:          []exchange.PriceLevel{{Price: "100", Quantity: "0"}},
+		Asks:          []exchange.PriceLevel{{Price: "101.0", Quantity: "0"}, {Price: "103", Quantity: "5"}},
+	})
+
+	stats := ob.GetStats()
+	if !stats.BestBid.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected best bid 99, got %s", stats.BestBid.String())
+	}
+	if !stats.BestAsk.Equal(decimal.NewFromInt(102)) {
+		t.Errorf("Expected best ask 102, got %s", stats.BestAsk.String())
+	}
+	if stats.BidLevels != 1 || stats.AskLevels != 2 {
+		t.Errorf("Expected 1 bid and 2 ask levels, got %d and %d", stats.BidLevels, stats.AskLevels)
+	}
+	if !stats.TotalAsksQty.Equal(decimal.NewFromInt(7)) {
+		t.Errorf("Expected total asks 7, got %s", stats.TotalAsksQty.String())
+	}
+}
+
+func TestSnapshotUpdateReplacesBook(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}},
+		[][2]string{{"101", "1"}},
+	)
+
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 2,
+		FinalUpdateID: 2,
+		PrevUpdateID:  1,
+		Bids:          []exchange.PriceLevel{{Price: "90", Quantity: "1"}},
+		Asks:          []exchange.PriceLevel{{Price: "91", Quantity: "1"}},
+		IsSnapshot:    true,
+	})
+
+	bids := ob.GetBids()
+	if len(bids) != 1 {
+		t.Fatalf("Expected 1 bid after snapshot update, got %d", len(bids))
+	}
+	if _, ok := bids["90"]; !ok {
+		t.Errorf("Expected bid at 90, got %v", bids)
+	}
+}
+
+func TestSnapshotUpdateIgnoresSequence(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}},
+		[][2]string{{"101", "1"}},
+	)
+
+	// A resync snapshot does not chain on from the previous update
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 50,
+		FinalUpdateID: 50,
+		Bids:          []exchange.PriceLevel{{Price: "90", Quantity: "1"}},
+		Asks:          []exchange.PriceLevel{{Price: "91", Quantity: "1"}},
+		IsSnapshot:    true,
+	})
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 51,
+		FinalUpdateID: 51,
+		PrevUpdateID:  50,
+		Bids:          []exchange.PriceLevel{{Price: "90.5", Quantity: "1"}},
+	})
+
+	if ob.GetBufferLength() != 0 {
+		t.Errorf("Expected no buffered events, got %d", ob.GetBufferLength())
+	}
+	if stats := ob.GetStats(); !stats.BestBid.Equal(decimal.RequireFromString("90.5")) {
+		t.Errorf("Expected best bid 90.5, got %s", stats.BestBid.String())
+	}
+}
+
+func TestSnapshotUpdateAppliesBufferedChain(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}},
+		[][2]string{{"101", "1"}},
+	)
+
+	// After a reconnect, events from the new stream arrive behind a gap until the
+	// re-sync snapshot replaces the book
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{FirstUpdateID: 40, FinalUpdateID: 45, PrevUpdateID: 39,
+		Bids: []exchange.PriceLevel{{Price: "80", Quantity: "1"}}})
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{FirstUpdateID: 46, FinalUpdateID: 52, PrevUpdateID: 45,
+		Bids: []exchange.PriceLevel{{Price: "90.5", Quantity: "1"}}})
+	if ob.GetBufferLength() != 2 {
+		t.Fatalf("Expected 2 buffered events, got %d", ob.GetBufferLength())
+	}
+
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 50,
+		FinalUpdateID: 50,
+		Bids:          []exchange.PriceLevel{{Price: "90", Quantity: "1"}},
+		Asks:          []exchange.PriceLevel{{Price: "91", Quantity: "1"}},
+		IsSnapshot:    true,
+	})
+
+	if ob.GetBufferLength() != 0 {
+		t.Errorf("Expected the buffer to be drained, got %d events", ob.GetBufferLength())
+	}
+	bids := ob.GetBids()
+	if _, ok := bids["80"]; ok {
+		t.Error("Expected the event covered by the snapshot to be discarded")
+	}
+	if stats := ob.GetStats(); !stats.BestBid.Equal(decimal.RequireFromString("90.5")) {
+		t.Errorf("Expected best bid 90.5 from the chained event, got %s", stats.BestBid.String())
+	}
+}
+
+func TestProcessBufferedEventsAppliesChain(t *testing.T) {
+	ob := New()
+	updates := []*exchange.DepthUpdate{
+		{FirstUpdateID: 5, FinalUpdateID: 8, PrevUpdateID: 4, Bids: []exchange.PriceLevel{{Price: "98", Quantity: "1"}}},
+		{FirstUpdateID: 9, FinalUpdateID: 12, PrevUpdateID: 8, Bids: []exchange.PriceLevel{{Price: "99", Quantity: "1"}}},
+		{FirstUpdateID: 13, FinalUpdateID: 13, PrevUpdateID: 12, Bids: []exchange.PriceLevel{{Price: "100", Quantity: "2"}}},
+		{FirstUpdateID: 20, FinalUpdateID: 21, PrevUpdateID: 19, Bids: []exchange.PriceLevel{{Price: "101", Quantity: "1"}}},
+	}
+	for _, u := range updates {
+		ob.HandleDepthUpdate(u)
+	}
+
+	snapshot := &exchange.Snapshot{
+		LastUpdateID: 10,
+		Bids:         []exchange.PriceLevel{{Price: "100", Quantity: "1"}},
+		Asks:         []exchange.PriceLevel{{Price: "102", Quantity: "1"}},
+	}
+	if err := ob.LoadSnapshot(snapshot); err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+	ob.ProcessBufferedEvents()
+
+	// 5-8 predates the snapshot, 9-12 and 13 chain on from it, 20-21 comes after a gap
+	bids := ob.GetBids()
+	if _, ok := bids["98"]; ok {
+		t.Error("Expected stale event to be discarded")
+	}
+	if _, ok := bids["99"]; !ok {
+		t.Error("Expected overlapping event to be applied")
+	}
+	if level, ok := bids["100"]; !ok || !level.Quantity.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected bid 100x2 from the chained event, got %v", bids)
+	}
+	if _, ok := bids["101"]; ok {
+		t.Error("Expected event after the gap to be dropped")
+	}
+}
+
+func TestSnapshotUpdateResyncPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastID   int64 // Sequence of the book before the resync; zero for venues without IDs
+		snapshot exchange.DepthUpdate
+	}{
+		{
+			// Binance futures: a REST snapshot far ahead of the stream after a reconnect
+			name:     "snapshot ahead of the book",
+			lastID:   1,
+			snapshot: exchange.DepthUpdate{FirstUpdateID: 5000, FinalUpdateID: 5000},
+		},
+		{
+			// OKX: a resubscription restarts seqIds below the last one applied
+			name:     "snapshot behind the book",
+			lastID:   1,
+			snapshot: exchange.DepthUpdate{FirstUpdateID: 0, FinalUpdateID: 0, PrevUpdateID: -1},
+		},
+		{
+			// Kraken: no sequence numbers at all
+			name:     "venue without update IDs",
+			snapshot: exchange.DepthUpdate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := New()
+			err := ob.LoadSnapshot(&exchange.Snapshot{
+				LastUpdateID: tt.lastID,
+				Bids:         []exchange.PriceLevel{{Price: "100", Quantity: "1"}},
+				Asks:         []exchange.PriceLevel{{Price: "101", Quantity: "1"}},
+			})
+			if err != nil {
+				t.Fatalf("LoadSnapshot failed: %v", err)
+			}
+			ob.ProcessBufferedEvents()
+
+			update := tt.snapshot
+			update.Bids = []exchange.PriceLevel{{Price: "90", Quantity: "2"}}
+			update.Asks = []exchange.PriceLevel{{Price: "91", Quantity: "2"}}
+			update.IsSnapshot = true
+			ob.HandleDepthUpdate(&update)
+
+			if ob.GetBufferLength() != 0 {
+				t.Errorf("Expected the snapshot to be applied, got %d buffered events", ob.GetBufferLength())
+			}
+			bids := ob.GetBids()
+			if _, ok := bids["100"]; ok || len(bids) != 1 {
+				t.Errorf("Expected the snapshot to replace the book, got %v", bids)
+			}
+		})
+	}
+}
+
+func TestSnapshotUpdateDropsEventsAfterGap(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}},
+		[][2]string{{"101", "1"}},
+	)
+
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{FirstUpdateID: 51, FinalUpdateID: 52, PrevUpdateID: 50,
+		Bids: []exchange.PriceLevel{{Price: "90.5", Quantity: "1"}}})
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{FirstUpdateID: 60, FinalUpdateID: 61, PrevUpdateID: 59,
+		Bids: []exchange.PriceLevel{{Price: "90.8", Quantity: "1"}}})
+
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 50,
+		FinalUpdateID: 50,
+		Bids:          []exchange.PriceLevel{{Price: "90", Quantity: "1"}},
+		Asks:          []exchange.PriceLevel{{Price: "91", Quantity: "1"}},
+		IsSnapshot:    true,
+	})
+
+	// 51-52 chains on from the snapshot; 60-61 follows a gap and is stale
+	if ob.GetBufferLength() != 0 {
+		t.Errorf("Expected the buffer to be emptied, got %d events", ob.GetBufferLength())
+	}
+	bids := ob.GetBids()
+	if _, ok := bids["90.5"]; !ok {
+		t.Error("Expected the chained event to be applied")
+	}
+	if _, ok := bids["90.8"]; ok {
+		t.Error("Expected the event after the gap to be dropped")
+	}
+}
+
+func TestTopAndRangeQueries(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}, {"99", "2"}, {"98", "3"}, {"97", "4"}},
+		[][2]string{{"101", "1"}, {"102", "2"}, {"103", "3"}, {"104", "4"}},
+	)
+
+	topBids := ob.TopBids(2)
+	if len(topBids) != 2 || !topBids[0].Price.Equal(decimal.NewFromInt(100)) || !topBids[1].Price.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected top bids [100 99], got %v", topBids)
+	}
+
+	topAsks := ob.TopAsks(10)
+	if len(topAsks) != 4 || !topAsks[0].Price.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("Expected 4 asks starting at 101, got %v", topAsks)
+	}
+
+	bids := ob.BidsInRange(decimal.RequireFromString("97.5"), decimal.RequireFromString("99.5"))
+	if len(bids) != 2 || !bids[0].Price.Equal(decimal.NewFromInt(99)) || !bids[1].Price.Equal(decimal.NewFromInt(98)) {
+		t.Errorf("Expected bids [99 98], got %v", bids)
+	}
+
+	asks := ob.AsksInRange(decimal.NewFromInt(102), decimal.NewFromInt(103))
+	if len(asks) != 2 || !asks[0].Price.Equal(decimal.NewFromInt(102)) || !asks[1].Price.Equal(decimal.NewFromInt(103)) {
+		t.Errorf("Expected asks [102 103], got %v", asks)
+	}
+
+	if empty := ob.AsksInRange(decimal.NewFromInt(200), decimal.NewFromInt(300)); len(empty) != 0 {
+		t.Errorf("Expected no asks in range, got %v", empty)
+	}
+}
+
+func TestGetBidsKeepsExchangePriceText(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100.10", "1"}, {"99.50", "2"}},
+		[][2]string{{"101.00", "1"}},
+	)
+
+	bids := ob.GetBids()
+	if _, ok := bids["100.10"]; !ok {
+		t.Errorf("Expected bids keyed by the exchange's price text, got %v", bids)
+	}
+	if _, ok := bids["100.1"]; ok {
+		t.Error("Expected no key in normalized decimal form")
+	}
+	if _, ok := ob.GetAsks()["101.00"]; !ok {
+		t.Errorf("Expected asks keyed by the exchange's price text, got %v", ob.GetAsks())
+	}
+
+	ob.HandleDepthUpdate(&exchange.DepthUpdate{
+		FirstUpdateID: 2,
+		FinalUpdateID: 2,
+		PrevUpdateID:  1,
+		Bids:          []exchange.PriceLevel{{Price: "100.10", Quantity: "0"}, {Price: "99.5", Quantity: "3"}},
+	})
+
+	bids = ob.GetBids()
+	if _, ok := bids["100.10"]; ok {
+		t.Error("Expected the removed level to be gone")
+	}
+	if level, ok := bids["99.5"]; !ok || len(bids) != 1 || !level.Quantity.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Expected the replaced level under the latest text, got %v", bids)
+	}
+}
@@ -0,0 +1,153 @@
+package orderbook
+
+import (
+	"time"
+
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// imbalanceLevels is the number of levels per side behind the top-K imbalance and weighted mid
+	imbalanceLevels = 10
+
+	// ofiBuckets one-second buckets make up the order flow imbalance window
+	ofiBuckets = 10
+)
+
+// ofiBucket holds the order flow of one second
+type ofiBucket struct {
+	second int64
+	flow   decimal.Decimal
+}
+
+// orderFlow accumulates order flow imbalance (Cont, Kukanov & Stoikov) over a sliding window.
+// Each update contributes the size added to the best bid minus the size added to the best ask,
+// where a price improving counts its whole queue and a price retreating removes the old one.
+type orderFlow struct {
+	bid, ask types.PriceLevel // Top of book after the previous update, sizes in base units
+	primed   bool             // False until a top of book to compare against has been seen
+	buckets  [ofiBuckets]ofiBucket
+}
+
+// reset takes a new top of book as the baseline without counting the jump as flow, e.g. after
+// a snapshot replaced the book
+func (f *orderFlow) reset(bid, ask types.PriceLevel) {
+	f.bid, f.ask = bid, ask
+	f.primed = !bid.Price.IsZero() && !ask.Price.IsZero()
+}
+
+// observe adds the flow between the previous top of book and this one
+func (f *orderFlow) observe(bid, ask types.PriceLevel, at time.Time) {
+	if !f.primed || bid.Price.IsZero() || ask.Price.IsZero() {
+		f.reset(bid, ask)
+		return
+	}
+
+	flow := decimal.Zero
+	if bid.Price.GreaterThanOrEqual(f.bid.Price) {
+		flow = flow.Add(bid.Quantity)
+	}
+	if bid.Price.LessThanOrEqual(f.bid.Price) {
+		flow = flow.Sub(f.bid.Quantity)
+	}
+	if ask.Price.LessThanOrEqual(f.ask.Price) {
+		flow = flow.Sub(ask.Quantity)
+	}
+	if ask.Price.GreaterThanOrEqual(f.ask.Price) {
+		flow = flow.Add(f.ask.Quantity)
+	}
+	f.bid, f.ask = bid, ask
+
+	second := at.Unix()
+	bucket := &f.buckets[second%ofiBuckets]
+	if bucket.second != second {
+		bucket.second, bucket.flow = second, decimal.Zero
+	}
+	bucket.flow = bucket.flow.Add(flow)
+}
+
+// sum returns the flow over the window ending at at
+func (f *orderFlow) sum(at time.Time) decimal.Decimal {
+	total := decimal.Zero
+	now := at.Unix()
+	for _, bucket := range f.buckets {
+		if bucket.second > now-ofiBuckets && bucket.second <= now {
+			total = total.Add(bucket.flow)
+		}
+	}
+	return total
+}
+
+// eventTime is the time of the latest update, falling back to the clock for venues that do not
+// stamp their events (must be called with mutex locked)
+func (ob *OrderBook) eventTime() time.Time {
+	if ob.stats.LastEventTime.IsZero() {
+		return time.Now()
+	}
+	return ob.stats.LastEventTime
+}
+
+// topOfBook returns the best level of each side, zero for an empty side (must be called with mutex locked)
+func (ob *OrderBook) topOfBook() (types.PriceLevel, types.PriceLevel) {
+	var bid, ask types.PriceLevel
+	if level, ok := ob.bids.Best(); ok {
+		bid = level
+	}
+	if level, ok := ob.asks.Best(); ok {
+		ask = level
+	}
+	return bid, ask
+}
+
+// calculateSignals updates the short-horizon price signals (must be called with mutex locked)
+func (ob *OrderBook) calculateSignals() {
+	ob.stats.OrderFlowImbalance = ob.flow.sum(ob.eventTime())
+
+	bid, ask := ob.topOfBook()
+	if bid.Price.IsZero() || ask.Price.IsZero() {
+		ob.stats.Microprice = decimal.Zero
+		ob.stats.WeightedMid = decimal.Zero
+		ob.stats.TopBidsQty = decimal.Zero
+		ob.stats.TopAsksQty = decimal.Zero
+		ob.stats.TopImbalance = decimal.Zero
+		return
+	}
+
+	// The microprice leans toward the side more likely to trade through: a thin ask pulls it up
+	topQty := bid.Quantity.Add(ask.Quantity)
+	if topQty.IsPositive() {
+		ob.stats.Microprice = bid.Price.Mul(ask.Quantity).Add(ask.Price.Mul(bid.Quantity)).Div(topQty)
+	} else {
+		ob.stats.Microprice = bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2))
+	}
+
+	bidQty, bidNotional := ob.sumTopLevels(ob.bids)
+	askQty, askNotional := ob.sumTopLevels(ob.asks)
+	totalQty := bidQty.Add(askQty)
+
+	ob.stats.TopBidsQty = bidQty
+	ob.stats.TopAsksQty = askQty
+	if totalQty.IsPositive() {
+		ob.stats.WeightedMid = bidNotional.Add(askNotional).Div(totalQty)
+		ob.stats.TopImbalance = bidQty.Sub(askQty).Div(totalQty)
+	} else {
+		ob.stats.WeightedMid = decimal.Zero
+		ob.stats.TopImbalance = decimal.Zero
+	}
+}
+
+// sumTopLevels returns the base size and notional of the best imbalanceLevels levels of a side
+// (must be called with mutex locked)
+func (ob *OrderBook) sumTopLevels(side *priceLevels) (decimal.Decimal, decimal.Decimal) {
+	qty, notional := decimal.Zero, decimal.Zero
+	n := 0
+	side.Ascend(func(level types.PriceLevel) bool {
+		qty = qty.Add(level.Quantity)
+		notional = notional.Add(level.Price.Mul(level.Quantity))
+		n++
+		return n < imbalanceLevels
+	})
+	return qty, notional
+}
@@ -0,0 +1,76 @@
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+)
+
+func TestSignals(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "6"}, {"99", "2"}},
+		[][2]string{{"101", "1"}, {"102", "1"}},
+	)
+
+	stats := ob.GetStats()
+	checks := []struct {
+		name     string
+		got      decimal.Decimal
+		expected string
+	}{
+		{"Microprice", stats.Microprice, "100.8571"}, // (100*1 + 101*6) / 7, pulled toward the thin ask
+		{"WeightedMid", stats.WeightedMid, "100.1"},  // (600 + 198 + 101 + 102) / 10
+		{"TopBidsQty", stats.TopBidsQty, "8"},
+		{"TopAsksQty", stats.TopAsksQty, "2"},
+		{"TopImbalance", stats.TopImbalance, "0.6"},
+		{"OrderFlowImbalance", stats.OrderFlowImbalance, "0"},
+	}
+
+	for _, c := range checks {
+		if !c.got.Round(4).Equal(decimal.RequireFromString(c.expected)) {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, c.got.String())
+		}
+	}
+}
+
+func TestOrderFlowImbalance(t *testing.T) {
+	ob := newTestBook(t,
+		[][2]string{{"100", "1"}, {"99", "2"}},
+		[][2]string{{"101", "1"}, {"102", "2"}},
+	)
+	start := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name     string
+		offset   time.Duration
+		bids     []exchange.PriceLevel
+		asks     []exchange.PriceLevel
+		expected string // Flow over the window after the update
+	}{
+		{"best bid grows", 0, []exchange.PriceLevel{{Price: "100", Quantity: "3"}}, nil, "2"},
+		{"best ask lifted", time.Second, nil, []exchange.PriceLevel{{Price: "101", Quantity: "0"}}, "3"},
+		{"bid improves", 2 * time.Second, []exchange.PriceLevel{{Price: "100.5", Quantity: "1"}}, nil, "4"},
+		{"earlier flow expires", 30 * time.Second, []exchange.PriceLevel{{Price: "100.5", Quantity: "2"}}, nil, "1"},
+		{"ask joins below", 31 * time.Second, nil, []exchange.PriceLevel{{Price: "101.5", Quantity: "4"}}, "-3"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(i + 2)
+			ob.HandleDepthUpdate(&exchange.DepthUpdate{
+				EventTime:     start.Add(tt.offset),
+				FirstUpdateID: id,
+				FinalUpdateID: id,
+				PrevUpdateID:  id - 1,
+				Bids:          tt.bids,
+				Asks:          tt.asks,
+			})
+
+			if got := ob.GetStats().OrderFlowImbalance; !got.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("Expected OFI %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"orderbook/internal/exchange"
+)
+
+// Kind identifies what a record holds
+type Kind string
+
+const (
+	KindSnapshot Kind = "snapshot"
+	KindUpdate   Kind = "update"
+)
+
+const (
+	// queueSize is the number of records buffered between the feed goroutines and the writer
+	queueSize = 10000
+	// flushInterval bounds how much data is lost if the process dies
+	flushInterval = time.Second
+	// fileTimeLayout names one file per UTC hour
+	fileTimeLayout = "2006-01-02T15"
+)
+
+// Record is one line of a recording file
+type Record struct {
+	Kind       Kind                  `json:"kind"`
+	Exchange   exchange.ExchangeName `json:"exchange"`
+	Symbol     string                `json:"symbol"`
+	ReceivedAt time.Time             `json:"receivedAt"` // Local receive time
+	Snapshot   *exchange.Snapshot    `json:"snapshot,omitempty"`
+	Update     *exchange.DepthUpdate `json:"update,omitempty"`
+}
+
+// Recorder tees canonical snapshots and depth updates to hourly JSONL.gz files laid out as
+// <dir>/<exchange>/<symbol>/<YYYY-MM-DDTHH>.jsonl.gz. Files are append-only: reopening an
+// hour after a restart adds a new gzip member, which gzip readers treat as one stream.
+//
+// Record calls never block the feed; if the writer falls behind, records are dropped and counted.
+// Records arriving after Close are ignored.
+type Recorder struct {
+	dir     string
+	records chan Record
+	done    chan struct{}
+	closeMu sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+	files   map[string]*hourFile
+}
+
+// hourFile is the currently open file of one exchange/symbol stream
+type hourFile struct {
+	hour string
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// New creates the output directory and starts the writer goroutine
+func New(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	r := &Recorder{
+		dir:     dir,
+		records: make(chan Record, queueSize),
+		done:    make(chan struct{}),
+		files:   make(map[string]*hourFile),
+	}
+	go r.run()
+	return r, nil
+}
+
+// RecordSnapshot records a snapshot as received now
+func (r *Recorder) RecordSnapshot(name exchange.ExchangeName, symbol string, snapshot *exchange.Snapshot) {
+	r.enqueue(Record{Kind: KindSnapshot, Exchange: name, Symbol: symbol, ReceivedAt: time.Now(), Snapshot: snapshot})
+}
+
+// RecordUpdate records a depth update as received now
+func (r *Recorder) RecordUpdate(name exchange.ExchangeName, symbol string, update *exchange.DepthUpdate) {
+	r.enqueue(Record{Kind: KindUpdate, Exchange: name, Symbol: symbol, ReceivedAt: time.Now(), Update: update})
+}
+
+// Dropped returns the number of records dropped because the writer could not keep up
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close writes out queued records and closes all files
+func (r *Recorder) Close() {
+	r.closeMu.Lock()
+	if r.closed {
+		r.closeMu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.records)
+	r.closeMu.Unlock()
+
+	<-r.done
+}
+
+func (r *Recorder) enqueue(rec Record) {
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+	if r.closed {
+		return
+	}
+
+	select {
+	case r.records <- rec:
+	default:
+		if r.dropped.Add(1)%1000 == 1 {
+			log.Printf("[recorder] Warning: writer falling behind, %d records dropped so far", r.dropped.Load())
+		}
+	}
+}
+
+// run writes records until the queue is closed
+func (r *Recorder) run() {
+	defer close(r.done)
+	defer r.closeFiles()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case rec, ok := <-r.records:
+			if !ok {
+				return
+			}
+			if err := r.write(rec); err != nil {
+				log.Printf("[recorder] Failed to write %s record for %s: %v", rec.Kind, rec.Exchange, err)
+			}
+		case <-ticker.C:
+			r.flush()
+		}
+	}
+}
+
+// write appends a record to its stream's file, rotating when the hour changes
+func (r *Recorder) write(rec Record) error {
+	key := string(rec.Exchange) + "/" + SanitizeSymbol(rec.Symbol)
+	hour := rec.ReceivedAt.UTC().Format(fileTimeLayout)
+
+	f, ok := r.files[key]
+	if !ok || f.hour != hour {
+		if ok {
+			if err := f.close(); err != nil {
+				log.Printf("[recorder] Failed to close %s: %v", f.file.Name(), err)
+			}
+			delete(r.files, key)
+		}
+
+		var err error
+		f, err = r.open(key, hour)
+		if err != nil {
+			return err
+		}
+		r.files[key] = f
+	}
+
+	return f.enc.Encode(rec)
+}
+
+// open opens (or appends to) the file for a stream and hour
+func (r *Recorder) open(key, hour string) (*hourFile, error) {
+	streamDir := filepath.Join(r.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(streamDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create stream directory: %w", err)
+	}
+
+	path := filepath.Join(streamDir, hour+".jsonl.gz")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	gz := gzip.NewWriter(file)
+	return &hourFile{hour: hour, file: file, gz: gz, enc: json.NewEncoder(gz)}, nil
+}
+
+func (r *Recorder) flush() {
+	for _, f := range r.files {
+		if err := f.gz.Flush(); err != nil {
+			log.Printf("[recorder] Failed to flush %s: %v", f.file.Name(), err)
+		}
+	}
+}
+
+func (r *Recorder) closeFiles() {
+	for key, f := range r.files {
+		if err := f.close(); err != nil {
+			log.Printf("[recorder] Failed to close %s: %v", f.file.Name(), err)
+		}
+		delete(r.files, key)
+	}
+}
+
+func (f *hourFile) close() error {
+	if err := f.gz.Close(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
+
+// SanitizeSymbol makes venue symbols such as BTC/USD safe to use as a directory name
+func SanitizeSymbol(symbol string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(symbol)
+}
@@ -0,0 +1,102 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"orderbook/internal/exchange"
+)
+
+// readRecords decodes every record in a recording file
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to open gzip stream: %v", err)
+	}
+	defer gz.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("Failed to decode record: %v", err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	return records
+}
+
+func TestRecorderRotatesHourly(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	hour := time.Date(2024, 3, 1, 10, 59, 59, 0, time.UTC)
+	r.enqueue(Record{
+		Kind: KindSnapshot, Exchange: exchange.Kraken, Symbol: "BTC/USD", ReceivedAt: hour,
+		Snapshot: &exchange.Snapshot{Exchange: exchange.Kraken, Bids: []exchange.PriceLevel{{Price: "100", Quantity: "1"}}},
+	})
+	r.enqueue(Record{
+		Kind: KindUpdate, Exchange: exchange.Kraken, Symbol: "BTC/USD", ReceivedAt: hour.Add(time.Second),
+		Update: &exchange.DepthUpdate{Exchange: exchange.Kraken, FinalUpdateID: 7},
+	})
+	r.Close()
+
+	first := readRecords(t, filepath.Join(dir, "kraken", "BTC-USD", "2024-03-01T10.jsonl.gz"))
+	if len(first) != 1 || first[0].Kind != KindSnapshot {
+		t.Fatalf("Expected one snapshot in the 10:00 file, got %+v", first)
+	}
+	if first[0].Snapshot.Bids[0].Price != "100" || !first[0].ReceivedAt.Equal(hour) {
+		t.Errorf("Snapshot did not round-trip: %+v", first[0])
+	}
+
+	second := readRecords(t, filepath.Join(dir, "kraken", "BTC-USD", "2024-03-01T11.jsonl.gz"))
+	if len(second) != 1 || second[0].Kind != KindUpdate || second[0].Update.FinalUpdateID != 7 {
+		t.Fatalf("Expected one update in the 11:00 file, got %+v", second)
+	}
+}
+
+func TestRecorderAppendsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := int64(1); i <= 2; i++ {
+		r, err := New(dir)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		r.enqueue(Record{
+			Kind: KindUpdate, Exchange: exchange.Binancef, Symbol: "BTCUSDT", ReceivedAt: at,
+			Update: &exchange.DepthUpdate{FinalUpdateID: i},
+		})
+		r.Close()
+	}
+
+	records := readRecords(t, filepath.Join(dir, "binancef", "BTCUSDT", "2024-03-01T10.jsonl.gz"))
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records after restart, got %d", len(records))
+	}
+	if records[0].Update.FinalUpdateID != 1 || records[1].Update.FinalUpdateID != 2 {
+		t.Errorf("Expected records in write order, got %d then %d",
+			records[0].Update.FinalUpdateID, records[1].Update.FinalUpdateID)
+	}
+}
@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"orderbook/internal/orderbook"
+	"orderbook/internal/recorder"
+)
+
+// Player drives recorded snapshots and depth updates through one OrderBook per exchange,
+// in the same order the live feed applied them
+type Player struct {
+	reader   *Reader
+	speed    float64
+	books    map[string]*orderbook.OrderBook
+	position atomic.Int64 // ReceivedAt of the last applied record, in unix nanoseconds
+}
+
+// NewPlayer creates a player for a reader. Speed 1 replays in real time, 10 ten times faster,
+// and 0 (or less) as fast as possible.
+func NewPlayer(reader *Reader, speed float64) *Player {
+	books := make(map[string]*orderbook.OrderBook)
+	for _, name := range reader.Exchanges() {
+		books[string(name)] = orderbook.New()
+	}
+	return &Player{reader: reader, speed: speed, books: books}
+}
+
+// Books returns the replayed orderbooks keyed by exchange name. The map is fixed when the
+// player is created, so it can be handed to the websocket server before Run starts.
+func (p *Player) Books() map[string]*orderbook.OrderBook {
+	return p.books
+}
+
+// Position returns the recorded receive time of the last applied record
+func (p *Player) Position() time.Time {
+	nanos := p.position.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// Run replays every record, pacing them by their recorded receive times. It returns nil once the
+// recordings are exhausted, or the context's error if it is cancelled first.
+func (p *Player) Run(ctx context.Context) error {
+	var first, start time.Time
+
+	for {
+		rec, err := p.reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if p.speed > 0 {
+			if first.IsZero() {
+				first = rec.ReceivedAt
+				start = time.Now()
+			}
+			offset := time.Duration(float64(rec.ReceivedAt.Sub(first)) / p.speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := p.Apply(rec); err != nil {
+			log.Printf("[%s] Replay: %v", rec.Exchange, err)
+		}
+	}
+}
+
+// Apply feeds a single record to its exchange's orderbook. Snapshots are loaded and then the
+// buffered events are processed, mirroring the live startup and reinitialization sequence.
+func (p *Player) Apply(rec recorder.Record) error {
+	ob, ok := p.books[string(rec.Exchange)]
+	if !ok {
+		return fmt.Errorf("no orderbook for exchange %s", rec.Exchange)
+	}
+
+	switch rec.Kind {
+	case recorder.KindSnapshot:
+		if rec.Snapshot == nil {
+			return errors.New("snapshot record without a snapshot")
+		}
+		if err := ob.LoadSnapshot(rec.Snapshot); err != nil {
+			return fmt.Errorf("failed to load snapshot: %w", err)
+		}
+		ob.ProcessBufferedEvents()
+	case recorder.KindUpdate:
+		if rec.Update == nil {
+			return errors.New("update record without an update")
+		}
+		ob.HandleDepthUpdate(rec.Update)
+	default:
+		return fmt.Errorf("unknown record kind %q", rec.Kind)
+	}
+
+	p.position.Store(rec.ReceivedAt.UnixNano())
+	return nil
+}
@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"orderbook/internal/exchange"
+	"orderbook/internal/recorder"
+)
+
+// levels builds price levels from [price, quantity] pairs
+func levels(pairs ...[2]string) []exchange.PriceLevel {
+	out := make([]exchange.PriceLevel, len(pairs))
+	for i, p := range pairs {
+		out[i] = exchange.PriceLevel{Price: p[0], Quantity: p[1]}
+	}
+	return out
+}
+
+// replayAll records the given records for one exchange and replays them at max speed
+func replayAll(t *testing.T, records ...recorder.Record) *Player {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeRecording(t, dir, records...)
+
+	r, err := Open(dir, records[0].Symbol)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+
+	p := NewPlayer(r, 0)
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	return p
+}
+
+// Binance streams updates before the REST snapshot arrives; the ones that straddle the
+// snapshot's lastUpdateId must be applied from the buffer and the stale ones dropped
+func TestReplayBuffersUpdatesUntilSnapshot(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	rec := func(offset time.Duration, r recorder.Record) recorder.Record {
+		r.Exchange, r.Symbol, r.ReceivedAt = exchange.Binancef, "BTCUSDT", at.Add(offset)
+		return r
+	}
+
+	p := replayAll(t,
+		rec(0, recorder.Record{Kind: recorder.KindUpdate, Update: &exchange.DepthUpdate{
+			FirstUpdateID: 95, FinalUpdateID: 100, PrevUpdateID: 94, Bids: levels([2]string{"99", "9"}),
+		}}),
+		rec(time.Millisecond, recorder.Record{Kind: recorder.KindUpdate, Update: &exchange.DepthUpdate{
+			FirstUpdateID: 101, FinalUpdateID: 105, PrevUpdateID: 100, Bids: levels([2]string{"100", "3"}),
+		}}),
+		rec(2*time.Millisecond, recorder.Record{Kind: recorder.KindSnapshot, Snapshot: &exchange.Snapshot{
+			LastUpdateID: 103,
+			Bids:         levels([2]string{"100", "1"}, [2]string{"99", "1"}),
+			Asks:         levels([2]string{"101", "1"}),
+		}}),
+		rec(3*time.Millisecond, recorder.Record{Kind: recorder.KindUpdate, Update: &exchange.DepthUpdate{
+			FirstUpdateID: 106, FinalUpdateID: 110, PrevUpdateID: 105, Asks: levels([2]string{"101", "0"}, [2]string{"102", "2"}),
+		}}),
+	)
+
+	ob := p.Books()["binancef"]
+	if !ob.IsInitialized() {
+		t.Fatal("Expected orderbook to be initialized")
+	}
+	if ob.GetBufferLength() != 0 {
+		t.Errorf("Expected empty buffer, got %d events", ob.GetBufferLength())
+	}
+
+	bids := ob.TopBids(2)
+	if len(bids) != 2 || !bids[0].Quantity.Equal(decimal.NewFromInt(3)) || !bids[1].Quantity.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected bids 100x3 and 99x1 (stale update dropped), got %+v", bids)
+	}
+	stats := ob.GetStats()
+	if !stats.BestAsk.Equal(decimal.NewFromInt(102)) {
+		t.Errorf("Expected best ask 102, got %s", stats.BestAsk.String())
+	}
+	if !p.Position().Equal(at.Add(3 * time.Millisecond)) {
+		t.Errorf("Expected position at the last record, got %v", p.Position())
+	}
+}
+
+// Hyperliquid sends the full top of book in every update; applying them incrementally left
+// stale levels behind and crossed the book (see logs/2025-10-22-hyperliquid-reconnection-race-condition.md)
+func TestReplayHyperliquidUpdatesReplaceBook(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	book := func(offset time.Duration, bid, ask string) recorder.Record {
+		return recorder.Record{
+			Kind: recorder.KindUpdate, Exchange: exchange.Hyperliquidf, Symbol: "BTC", ReceivedAt: at.Add(offset),
+			Update: &exchange.DepthUpdate{
+				Bids:       levels([2]string{bid, "1"}),
+				Asks:       levels([2]string{ask, "1"}),
+				IsSnapshot: true,
+			},
+		}
+	}
+
+	p := replayAll(t,
+		recorder.Record{
+			Kind: recorder.KindSnapshot, Exchange: exchange.Hyperliquidf, Symbol: "BTC", ReceivedAt: at,
+			Snapshot: &exchange.Snapshot{Bids: levels([2]string{"108155", "1"}), Asks: levels([2]string{"108156", "1"})},
+		},
+		book(time.Second, "108158", "108159"),
+		book(2*time.Second, "108074", "108075"),
+	)
+
+	stats := p.Books()["hyperliquidf"].GetStats()
+	if !stats.BestBid.Equal(decimal.NewFromInt(108074)) || !stats.BestAsk.Equal(decimal.NewFromInt(108075)) {
+		t.Errorf("Expected 108074/108075, got %s/%s", stats.BestBid.String(), stats.BestAsk.String())
+	}
+	if !stats.TotalBidsQty.Equal(decimal.NewFromInt(1)) || !stats.TotalAsksQty.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Expected only the latest levels, got %s bid and %s ask quantity",
+			stats.TotalBidsQty.String(), stats.TotalAsksQty.String())
+	}
+}
+
+func TestRunPacing(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		gap     time.Duration
+		speed   float64
+		minTime time.Duration
+		maxTime time.Duration
+	}{
+		{"max speed ignores gaps", time.Hour, 0, 0, time.Second},
+		{"accelerated", 400 * time.Millisecond, 4, 100 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeRecording(t, dir, update(exchange.Bybitf, at, 1), update(exchange.Bybitf, at.Add(tt.gap), 2))
+
+			r, err := Open(dir, "BTCUSDT")
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer r.Close()
+
+			start := time.Now()
+			if err := NewPlayer(r, tt.speed).Run(context.Background()); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minTime || elapsed > tt.maxTime {
+				t.Errorf("Expected replay to take between %v and %v, took %v", tt.minTime, tt.maxTime, elapsed)
+			}
+		})
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeRecording(t, dir, update(exchange.Bybitf, at, 1), update(exchange.Bybitf, at.Add(time.Hour), 2))
+
+	r, err := Open(dir, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := NewPlayer(r, 1).Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"orderbook/internal/exchange"
+	"orderbook/internal/recorder"
+)
+
+// Reader merges the recordings of several exchanges into a single stream ordered by receive time.
+// Records with equal receive times are returned in exchange name order, so a replay is deterministic.
+type Reader struct {
+	streams []*stream
+}
+
+// stream reads one exchange's hourly recording files in order
+type stream struct {
+	name  exchange.ExchangeName
+	files []string
+	file  *os.File
+	gz    *gzip.Reader
+	dec   *json.Decoder
+	head  *recorder.Record // Next record to return, nil once the stream is exhausted
+}
+
+// Open opens the recordings of symbol under a recorder directory. If no exchanges are given,
+// every exchange that recorded the symbol is read.
+func Open(dir, symbol string, names ...exchange.ExchangeName) (*Reader, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, exchange.ExchangeName(entry.Name()))
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	r := &Reader{}
+	for _, name := range names {
+		files, err := filepath.Glob(filepath.Join(dir, string(name), recorder.SanitizeSymbol(symbol), "*.jsonl.gz"))
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to list %s recordings: %w", name, err)
+		}
+		if len(files) == 0 {
+			continue
+		}
+		// Hourly file names sort chronologically
+		sort.Strings(files)
+
+		s := &stream{name: name, files: files}
+		if err := s.advance(); err != nil {
+			s.close()
+			r.Close()
+			return nil, err
+		}
+		if s.head != nil {
+			r.streams = append(r.streams, s)
+		}
+	}
+
+	if len(r.streams) == 0 {
+		return nil, fmt.Errorf("no recordings of %s found in %s", symbol, dir)
+	}
+	return r, nil
+}
+
+// Exchanges returns the exchanges being replayed, in name order
+func (r *Reader) Exchanges() []exchange.ExchangeName {
+	names := make([]exchange.ExchangeName, len(r.streams))
+	for i, s := range r.streams {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Next returns the earliest pending record, or io.EOF once every recording is exhausted
+func (r *Reader) Next() (recorder.Record, error) {
+	var earliest *stream
+	for _, s := range r.streams {
+		if s.head == nil {
+			continue
+		}
+		if earliest == nil || s.head.ReceivedAt.Before(earliest.head.ReceivedAt) {
+			earliest = s
+		}
+	}
+	if earliest == nil {
+		return recorder.Record{}, io.EOF
+	}
+
+	rec := *earliest.head
+	if err := earliest.advance(); err != nil {
+		return recorder.Record{}, err
+	}
+	return rec, nil
+}
+
+// Close closes any open recording files
+func (r *Reader) Close() {
+	for _, s := range r.streams {
+		s.close()
+	}
+}
+
+// advance reads the next record into head, moving on to the next file when one is exhausted
+func (s *stream) advance() error {
+	s.head = nil
+	for {
+		if s.dec == nil {
+			if len(s.files) == 0 {
+				return nil
+			}
+			path := s.files[0]
+			s.files = s.files[1:]
+			if err := s.open(path); err != nil {
+				return err
+			}
+			if s.dec == nil {
+				continue
+			}
+		}
+
+		var rec recorder.Record
+		err := s.dec.Decode(&rec)
+		if err == nil {
+			s.head = &rec
+			return nil
+		}
+
+		// A file cut off by a crash is still worth replaying up to the point it was flushed
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			log.Printf("[replay] Warning: %s ends with a truncated record", s.file.Name())
+		} else if err != io.EOF {
+			return fmt.Errorf("failed to decode %s: %w", s.file.Name(), err)
+		}
+		s.close()
+	}
+}
+
+func (s *stream) open(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	gz, err := gzip.NewReader(file)
+	if err == io.EOF {
+		// The recorder created the file but never flushed anything to it
+		file.Close()
+		return nil
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open gzip stream %s: %w", path, err)
+	}
+
+	s.file = file
+	s.gz = gz
+	s.dec = json.NewDecoder(gz)
+	return nil
+}
+
+func (s *stream) close() {
+	if s.gz != nil {
+		s.gz.Close()
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, s.gz, s.dec = nil, nil, nil
+}
@@ -0,0 +1,124 @@
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"orderbook/internal/exchange"
+	"orderbook/internal/recorder"
+)
+
+// writeRecording writes records to hourly files laid out the way the recorder does
+func writeRecording(t *testing.T, dir string, records ...recorder.Record) {
+	t.Helper()
+
+	for _, rec := range records {
+		streamDir := filepath.Join(dir, string(rec.Exchange), recorder.SanitizeSymbol(rec.Symbol))
+		if err := os.MkdirAll(streamDir, 0o755); err != nil {
+			t.Fatalf("Failed to create %s: %v", streamDir, err)
+		}
+
+		path := filepath.Join(streamDir, rec.ReceivedAt.UTC().Format("2006-01-02T15")+".jsonl.gz")
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", path, err)
+		}
+		gz := gzip.NewWriter(file)
+		if err := json.NewEncoder(gz).Encode(rec); err != nil {
+			t.Fatalf("Failed to encode record: %v", err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Fatalf("Failed to close gzip stream: %v", err)
+		}
+		file.Close()
+	}
+}
+
+// update builds an update record for an exchange at a receive time
+func update(name exchange.ExchangeName, at time.Time, finalID int64) recorder.Record {
+	return recorder.Record{
+		Kind: recorder.KindUpdate, Exchange: name, Symbol: "BTCUSDT", ReceivedAt: at,
+		Update: &exchange.DepthUpdate{Exchange: name, FinalUpdateID: finalID},
+	}
+}
+
+func TestReaderMergesExchangesAcrossHours(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 3, 1, 10, 59, 59, 0, time.UTC)
+
+	writeRecording(t, dir,
+		update(exchange.Kraken, base, 1),
+		update(exchange.Binancef, base, 1),
+		update(exchange.Binancef, base.Add(2*time.Second), 2),
+		update(exchange.Kraken, base.Add(time.Second), 2),
+	)
+
+	r, err := Open(dir, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+
+	if names := r.Exchanges(); len(names) != 2 || names[0] != exchange.Binancef || names[1] != exchange.Kraken {
+		t.Errorf("Expected [binancef kraken], got %v", names)
+	}
+
+	expected := []struct {
+		name    exchange.ExchangeName
+		finalID int64
+	}{
+		{exchange.Binancef, 1}, // Ties go to the first exchange by name
+		{exchange.Kraken, 1},
+		{exchange.Kraken, 2}, // 11:00:00, from the next hour's file
+		{exchange.Binancef, 2},
+	}
+	for i, want := range expected {
+		rec, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next %d failed: %v", i, err)
+		}
+		if rec.Exchange != want.name || rec.Update.FinalUpdateID != want.finalID {
+			t.Errorf("Record %d: expected %s u=%d, got %s u=%d", i, want.name, want.finalID, rec.Exchange, rec.Update.FinalUpdateID)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReaderSkipsEmptyFiles(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	writeRecording(t, dir, update(exchange.Bybitf, at, 7))
+
+	// The recorder may have created the previous hour's file without flushing anything
+	empty := filepath.Join(dir, "bybitf", "BTCUSDT", "2024-03-01T10.jsonl.gz")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatalf("Failed to create empty file: %v", err)
+	}
+
+	r, err := Open(dir, "BTCUSDT", exchange.Bybitf)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer r.Close()
+
+	rec, err := r.Next()
+	if err != nil || rec.Update.FinalUpdateID != 7 {
+		t.Errorf("Expected update 7, got %+v (err=%v)", rec, err)
+	}
+}
+
+func TestOpenWithoutRecordings(t *testing.T) {
+	dir := t.TempDir()
+	writeRecording(t, dir, update(exchange.Bybitf, time.Now(), 1))
+
+	if _, err := Open(dir, "ETHUSDT"); err == nil {
+		t.Error("Expected error for a symbol that was never recorded, got nil")
+	}
+}
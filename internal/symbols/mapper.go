@@ -0,0 +1,115 @@
+package symbols
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"orderbook/internal/exchange"
+)
+
+// ErrUnsupported is returned when a venue does not list a market for the requested symbol
+var ErrUnsupported = errors.New("market not listed")
+
+// Market is a symbol resolved for one venue
+type Market struct {
+	Exchange exchange.ExchangeName
+	Symbol   Symbol // As listed on the venue; the quote may differ from the request (see Venue.QuoteMap)
+	Native   string // Venue format (e.g., BTC-USDT-SWAP)
+}
+
+// Venue describes which markets an exchange lists and how it names them
+type Venue struct {
+	Quotes   map[string]exchange.InstrumentType // Listed quote assets and the market type of each
+	QuoteMap map[string]string                  // Requested quote -> quote streamed instead (e.g., USDT -> USD where the USD book is the deep one)
+	Format   func(base, quote string) string    // Builds the venue symbol
+}
+
+// overrideKey identifies a requested base/quote on one venue
+type overrideKey struct {
+	exchange exchange.ExchangeName
+	base     string
+	quote    string
+}
+
+// Mapper resolves canonical symbols to venue markets. The venue rules cover regular listings;
+// overrides pin individual markets the rules get wrong (renamed or rebased assets).
+type Mapper struct {
+	mu        sync.RWMutex
+	venues    map[exchange.ExchangeName]Venue
+	overrides map[overrideKey]Market
+}
+
+// NewMapper creates a mapper with the default rules of every supported venue
+func NewMapper() *Mapper {
+	return &Mapper{
+		venues:    DefaultVenues(),
+		overrides: make(map[overrideKey]Market),
+	}
+}
+
+// SetVenue replaces the rules of an exchange
+func (m *Mapper) SetVenue(name exchange.ExchangeName, venue Venue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.venues[name] = venue
+}
+
+// Override maps a requested symbol on market.Exchange straight to market, bypassing the venue rules
+func (m *Mapper) Override(requested Symbol, market Market) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overrides[overrideKey{market.Exchange, requested.Base, requested.Quote}] = market
+}
+
+// Resolve returns the market of symbol on an exchange, or an error wrapping ErrUnsupported
+// if the venue does not list it
+func (m *Mapper) Resolve(name exchange.ExchangeName, symbol Symbol) (Market, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if market, ok := m.overrides[overrideKey{name, symbol.Base, symbol.Quote}]; ok {
+		if symbol.Type != "" && symbol.Type != market.Symbol.Type {
+			return Market{}, fmt.Errorf("%w: %s has no %s market", ErrUnsupported, name, symbol)
+		}
+		return market, nil
+	}
+
+	venue, ok := m.venues[name]
+	if !ok {
+		return Market{}, fmt.Errorf("unknown exchange: %s", name)
+	}
+
+	quote := symbol.Quote
+	if mapped, ok := venue.QuoteMap[quote]; ok {
+		quote = mapped
+	}
+
+	instType, ok := venue.Quotes[quote]
+	if !ok || (symbol.Type != "" && symbol.Type != instType) {
+		return Market{}, fmt.Errorf("%w: %s has no %s market", ErrUnsupported, name, symbol)
+	}
+
+	return Market{
+		Exchange: name,
+		Symbol:   Symbol{Base: symbol.Base, Quote: quote, Type: instType},
+		Native:   venue.Format(symbol.Base, quote),
+	}, nil
+}
+
+// ResolveAll resolves symbol on each exchange, returning the markets found and the reason
+// each remaining exchange was skipped
+func (m *Mapper) ResolveAll(names []exchange.ExchangeName, symbol Symbol) ([]Market, map[exchange.ExchangeName]error) {
+	markets := make([]Market, 0, len(names))
+	skipped := make(map[exchange.ExchangeName]error)
+
+	for _, name := range names {
+		market, err := m.Resolve(name, symbol)
+		if err != nil {
+			skipped[name] = err
+			continue
+		}
+		markets = append(markets, market)
+	}
+	return markets, skipped
+}
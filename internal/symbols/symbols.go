@@ -0,0 +1,77 @@
+package symbols
+
+import (
+	"fmt"
+	"strings"
+
+	"orderbook/internal/exchange"
+)
+
+// knownQuotes are the quote assets recognised at the end of an unseparated symbol such as
+// BTCUSDT, longest first so USDT wins over USD
+var knownQuotes = []string{"FDUSD", "USDT", "USDC", "USD", "EUR", "GBP", "BTC", "ETH"}
+
+// Symbol identifies an instrument independently of any venue
+type Symbol struct {
+	Base  string                  // e.g., BTC
+	Quote string                  // e.g., USDT
+	Type  exchange.InstrumentType // Empty matches spot and perpetual markets alike
+}
+
+// Parse reads a symbol such as BTCUSDT, BTC-USDT, BTC/USDT or BTC-USDT-PERP. A -PERP or
+// -SWAP suffix restricts the symbol to perpetuals (inverse when quoted in USD).
+func Parse(s string) (Symbol, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(s))
+
+	var instType exchange.InstrumentType
+	for _, suffix := range []string{"-PERP", "-SWAP"} {
+		if strings.HasSuffix(symbol, suffix) {
+			symbol = strings.TrimSuffix(symbol, suffix)
+			instType = exchange.InstrumentLinearPerp
+			break
+		}
+	}
+
+	base, quote, ok := cutSymbol(symbol)
+	if !ok {
+		return Symbol{}, fmt.Errorf("cannot parse symbol %q: expected BASE/QUOTE or a known quote suffix", s)
+	}
+	if instType == exchange.InstrumentLinearPerp && quote == "USD" {
+		instType = exchange.InstrumentInversePerp
+	}
+
+	return Symbol{Base: base, Quote: quote, Type: instType}, nil
+}
+
+// MustParse is like Parse but panics on error; use it for constants
+func MustParse(s string) Symbol {
+	symbol, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return symbol
+}
+
+// cutSymbol splits a symbol at a separator or, failing that, before a known quote suffix
+func cutSymbol(symbol string) (base, quote string, ok bool) {
+	for _, sep := range []string{"/", "-", "_"} {
+		if base, quote, ok := strings.Cut(symbol, sep); ok {
+			return base, quote, base != "" && quote != "" && !strings.ContainsAny(quote, "/-_")
+		}
+	}
+
+	for _, quote := range knownQuotes {
+		if base, ok := strings.CutSuffix(symbol, quote); ok && base != "" {
+			return base, quote, true
+		}
+	}
+	return "", "", false
+}
+
+// String returns the symbol as BASE/QUOTE, with the market type when it is restricted
+func (s Symbol) String() string {
+	if s.Type == "" {
+		return s.Base + "/" + s.Quote
+	}
+	return fmt.Sprintf("%s/%s (%s)", s.Base, s.Quote, s.Type)
+}
@@ -0,0 +1,119 @@
+package symbols
+
+import (
+	"errors"
+	"testing"
+
+	"orderbook/internal/exchange"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Symbol
+		wantErr  bool
+	}{
+		{input: "BTCUSDT", expected: Symbol{Base: "BTC", Quote: "USDT"}},
+		{input: "ethusd", expected: Symbol{Base: "ETH", Quote: "USD"}},
+		{input: "SOL/USDC", expected: Symbol{Base: "SOL", Quote: "USDC"}},
+		{input: "ETHBTC", expected: Symbol{Base: "ETH", Quote: "BTC"}},
+		{input: "BTC-USDT-SWAP", expected: Symbol{Base: "BTC", Quote: "USDT", Type: exchange.InstrumentLinearPerp}},
+		{input: "BTC-USD-PERP", expected: Symbol{Base: "BTC", Quote: "USD", Type: exchange.InstrumentInversePerp}},
+		{input: "BTC", wantErr: true},
+		{input: "BTC-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange exchange.ExchangeName
+		symbol   string
+		native   string
+		quote    string
+		instType exchange.InstrumentType
+	}{
+		{"binance futures", exchange.Binancef, "BTCUSDT", "BTCUSDT", "USDT", exchange.InstrumentLinearPerp},
+		{"okx linear swap", exchange.OKXf, "BTCUSDT", "BTC-USDT-SWAP", "USDT", exchange.InstrumentLinearPerp},
+		{"okx inverse swap", exchange.OKXf, "eth-usd", "ETH-USD-SWAP", "USD", exchange.InstrumentInversePerp},
+		{"kraken streams USD for USDT", exchange.Kraken, "BTCUSDT", "BTC/USD", "USD", exchange.InstrumentSpot},
+		{"kraken keeps EUR", exchange.Kraken, "ETHEUR", "ETH/EUR", "EUR", exchange.InstrumentSpot},
+		{"coinbase", exchange.Coinbase, "BTCUSDC", "BTC-USDC", "USDC", exchange.InstrumentSpot},
+		{"bybit USDC perp", exchange.Bybitf, "BTCUSDC", "BTCPERP", "USDC", exchange.InstrumentLinearPerp},
+		{"hyperliquid coin", exchange.Hyperliquidf, "SOLUSDT", "SOL", "USDC", exchange.InstrumentLinearPerp},
+	}
+
+	mapper := NewMapper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market, err := mapper.Resolve(tt.exchange, MustParse(tt.symbol))
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if market.Native != tt.native || market.Symbol.Quote != tt.quote || market.Symbol.Type != tt.instType {
+				t.Errorf("Expected %s (%s, %s), got %s (%s, %s)",
+					tt.native, tt.quote, tt.instType, market.Native, market.Symbol.Quote, market.Symbol.Type)
+			}
+		})
+	}
+}
+
+func TestResolveUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange exchange.ExchangeName
+		symbol   Symbol
+	}{
+		{"no BTC quote on hyperliquid", exchange.Hyperliquidf, MustParse("ETHBTC")},
+		{"no spot on a perp venue", exchange.Binancef, Symbol{Base: "BTC", Quote: "USDT", Type: exchange.InstrumentSpot}},
+		{"no perp on a spot venue", exchange.Kraken, MustParse("BTC-USD-PERP")},
+		{"no inverse perp on binance", exchange.Binancef, MustParse("BTC-USD-SWAP")},
+	}
+
+	mapper := NewMapper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := mapper.Resolve(tt.exchange, tt.symbol)
+			if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Expected ErrUnsupported, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOverride(t *testing.T) {
+	mapper := NewMapper()
+	symbol := MustParse("PEPEUSDT")
+	mapper.Override(symbol, Market{
+		Exchange: exchange.Binancef,
+		Symbol:   Symbol{Base: "1000PEPE", Quote: "USDT", Type: exchange.InstrumentLinearPerp},
+		Native:   "1000PEPEUSDT",
+	})
+
+	market, err := mapper.Resolve(exchange.Binancef, symbol)
+	if err != nil || market.Native != "1000PEPEUSDT" {
+		t.Errorf("Expected the override 1000PEPEUSDT, got %q (err=%v)", market.Native, err)
+	}
+
+	// Other venues keep the regular rules
+	if market, _ := mapper.Resolve(exchange.Binance, symbol); market.Native != "PEPEUSDT" {
+		t.Errorf("Expected PEPEUSDT on spot, got %q", market.Native)
+	}
+
+	markets, skipped := mapper.ResolveAll([]exchange.ExchangeName{exchange.Binancef, exchange.Asterdexf, exchange.Hyperliquidf}, MustParse("ETHBTC"))
+	if len(markets) != 0 || len(skipped) != 3 {
+		t.Errorf("Expected every venue to be skipped for ETH/BTC, got %d markets and %d skipped", len(markets), len(skipped))
+	}
+}
@@ -0,0 +1,105 @@
+package symbols
+
+import "orderbook/internal/exchange"
+
+// DefaultVenues returns the listing rules of every supported venue. Quote maps stream the
+// venue's deepest book for a stablecoin request; the quote conversion layer reconciles prices.
+func DefaultVenues() map[exchange.ExchangeName]Venue {
+	return map[exchange.ExchangeName]Venue{
+		exchange.Binance: {
+			Quotes: quotes(exchange.InstrumentSpot, "USDT", "USDC", "FDUSD", "BTC", "ETH", "EUR"),
+			Format: concat,
+		},
+		exchange.Binancef: {
+			Quotes: quotes(exchange.InstrumentLinearPerp, "USDT", "USDC"),
+			Format: concat,
+		},
+		exchange.Bybit: {
+			Quotes: quotes(exchange.InstrumentSpot, "USDT", "USDC", "BTC", "ETH", "EUR"),
+			Format: concat,
+		},
+		exchange.Bybitf: {
+			Quotes: quotes(exchange.InstrumentLinearPerp, "USDT", "USDC"),
+			Format: bybitLinear,
+		},
+		exchange.Kraken: {
+			Quotes:   quotes(exchange.InstrumentSpot, "USD", "USDT", "USDC", "EUR", "GBP", "BTC"),
+			QuoteMap: map[string]string{"USDT": "USD"},
+			Format:   separated("/"),
+		},
+		exchange.OKX: {
+			Quotes: quotes(exchange.InstrumentSpot, "USDT", "USDC", "BTC", "ETH", "EUR"),
+			Format: separated("-"),
+		},
+		exchange.OKXf: {
+			Quotes: merge(
+				quotes(exchange.InstrumentLinearPerp, "USDT", "USDC"),
+				quotes(exchange.InstrumentInversePerp, "USD"),
+			),
+			Format: func(base, quote string) string { return base + "-" + quote + "-SWAP" },
+		},
+		exchange.Coinbase: {
+			Quotes:   quotes(exchange.InstrumentSpot, "USD", "USDC", "USDT", "EUR", "GBP", "BTC"),
+			QuoteMap: map[string]string{"USDT": "USD"},
+			Format:   separated("-"),
+		},
+		exchange.Asterdexf: {
+			Quotes: quotes(exchange.InstrumentLinearPerp, "USDT"),
+			Format: concat,
+		},
+		exchange.BingX: {
+			Quotes: quotes(exchange.InstrumentSpot, "USDT", "USDC"),
+			Format: separated("-"),
+		},
+		exchange.BingXf: {
+			Quotes: quotes(exchange.InstrumentLinearPerp, "USDT"),
+			Format: separated("-"),
+		},
+		exchange.Hyperliquidf: {
+			// Perps are named by coin alone and all settle in USDC
+			Quotes:   quotes(exchange.InstrumentLinearPerp, "USDC"),
+			QuoteMap: map[string]string{"USDT": "USDC", "USD": "USDC"},
+			Format:   func(base, _ string) string { return base },
+		},
+	}
+}
+
+// quotes lists quote assets that share a market type
+func quotes(instType exchange.InstrumentType, assets ...string) map[string]exchange.InstrumentType {
+	listed := make(map[string]exchange.InstrumentType, len(assets))
+	for _, asset := range assets {
+		listed[asset] = instType
+	}
+	return listed
+}
+
+// merge combines quote listings of different market types
+func merge(listings ...map[string]exchange.InstrumentType) map[string]exchange.InstrumentType {
+	merged := make(map[string]exchange.InstrumentType)
+	for _, listing := range listings {
+		for asset, instType := range listing {
+			merged[asset] = instType
+		}
+	}
+	return merged
+}
+
+// concat formats BTCUSDT
+func concat(base, quote string) string {
+	return base + quote
+}
+
+// separated formats symbols with a separator between base and quote (BTC-USDT, BTC/USD)
+func separated(sep string) func(base, quote string) string {
+	return func(base, quote string) string {
+		return base + sep + quote
+	}
+}
+
+// bybitLinear formats Bybit linear contracts; USDC perpetuals are named BTCPERP
+func bybitLinear(base, quote string) string {
+	if quote == "USDC" {
+		return base + "PERP"
+	}
+	return base + quote
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// DepthBand is a distance from the mid price, in basis points, within which liquidity is measured
+type DepthBand float64
+
+// DefaultDepthBands are measured unless configured otherwise
+var DefaultDepthBands = []DepthBand{Percent(0.5), Percent(2), Percent(10)}
+
+// Bps returns a band of n basis points
+func Bps(n float64) DepthBand {
+	return DepthBand(n)
+}
+
+// Percent returns a band of n percent
+func Percent(n float64) DepthBand {
+	// Scale in decimal so 0.1% comes out as exactly 10bps
+	bps, _ := decimal.NewFromFloat(n).Mul(decimal.NewFromInt(100)).Float64()
+	return DepthBand(bps)
+}
+
+// Fraction returns the band as a fraction of the mid price
+func (b DepthBand) Fraction() decimal.Decimal {
+	return decimal.NewFromFloat(float64(b)).Div(decimal.NewFromInt(10000))
+}
+
+// String formats bands under 1% in basis points and wider ones in percent ("25bps", "2%")
+func (b DepthBand) String() string {
+	if b < 100 {
+		return strconv.FormatFloat(float64(b), 'f', -1, 64) + "bps"
+	}
+	return strconv.FormatFloat(float64(b)/100, 'f', -1, 64) + "%"
+}
+
+// DepthLiquidity is the liquidity resting within one band of the mid price
+type DepthLiquidity struct {
+	Band          DepthBand
+	BidQty        decimal.Decimal // Base asset units
+	AskQty        decimal.Decimal
+	DeltaQty      decimal.Decimal // BidQty - AskQty (positive = more bids)
+	BidNotional   decimal.Decimal // Quote value (sum of price × base size)
+	AskNotional   decimal.Decimal
+	DeltaNotional decimal.Decimal // BidNotional - AskNotional
+}
@@ -44,23 +44,48 @@ type Stats struct {
 	BestAsk         decimal.Decimal
 	Spread          decimal.Decimal
 
-	// Liquidity depth metrics (in base asset units)
-	BidLiquidity05Pct decimal.Decimal // Total bid size within 0.5% of mid
-	AskLiquidity05Pct decimal.Decimal // Total ask size within 0.5% of mid
-	BidLiquidity2Pct  decimal.Decimal // Total bid size within 2% of mid
-	AskLiquidity2Pct  decimal.Decimal // Total ask size within 2% of mid
-	BidLiquidity10Pct decimal.Decimal // Total bid size within 10% of mid
-	AskLiquidity10Pct decimal.Decimal // Total ask size within 10% of mid
-
-	// Liquidity imbalance (positive = more bids, negative = more asks)
-	DeltaLiquidity05Pct decimal.Decimal // BidLiquidity05Pct - AskLiquidity05Pct
-	DeltaLiquidity2Pct  decimal.Decimal // BidLiquidity2Pct - AskLiquidity2Pct
-	DeltaLiquidity10Pct decimal.Decimal // BidLiquidity10Pct - AskLiquidity10Pct
+	// Liquidity within each configured band of the mid price, narrowest first
+	Depth []DepthLiquidity
 
 	// Total quantities across all price levels
 	TotalBidsQty decimal.Decimal // Sum of all bid quantities
 	TotalAsksQty decimal.Decimal // Sum of all ask quantities
 	TotalDelta   decimal.Decimal // TotalBidsQty - TotalAsksQty (positive = more bids)
+
+	// Total quote notional across all price levels
+	TotalBidsNotional  decimal.Decimal
+	TotalAsksNotional  decimal.Decimal
+	TotalNotionalDelta decimal.Decimal // TotalBidsNotional - TotalAsksNotional
+
+	// Short-horizon signals
+	Microprice         decimal.Decimal // Top-of-book mid weighted toward the thinner side
+	WeightedMid        decimal.Decimal // Size-weighted average price of the top levels of both sides
+	TopBidsQty         decimal.Decimal // Base size of the top 10 bid levels
+	TopAsksQty         decimal.Decimal // Base size of the top 10 ask levels
+	TopImbalance       decimal.Decimal // (TopBidsQty - TopAsksQty) / (TopBidsQty + TopAsksQty), from -1 to 1
+	OrderFlowImbalance decimal.Decimal // Net size added at the top of book over the last 10s, base units (positive = buying pressure)
+}
+
+// ConvertQuote returns the stats with prices and notionals converted at rate (units of the
+// new quote per unit of the current one). Base-unit sizes are unaffected.
+func (s Stats) ConvertQuote(rate decimal.Decimal) Stats {
+	for _, v := range []*decimal.Decimal{
+		&s.BestBid, &s.BestAsk, &s.Spread, &s.Microprice, &s.WeightedMid,
+		&s.TotalBidsNotional, &s.TotalAsksNotional, &s.TotalNotionalDelta,
+	} {
+		*v = v.Mul(rate)
+	}
+
+	// The depth slice is shared with the book's cached stats, so convert a copy
+	depth := make([]DepthLiquidity, len(s.Depth))
+	for i, d := range s.Depth {
+		d.BidNotional = d.BidNotional.Mul(rate)
+		d.AskNotional = d.AskNotional.Mul(rate)
+		d.DeltaNotional = d.DeltaNotional.Mul(rate)
+		depth[i] = d
+	}
+	s.Depth = depth
+	return s
 }
 
 // GetNextTickLevel returns the next tick level in the sequence
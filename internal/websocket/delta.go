@@ -0,0 +1,185 @@
+package websocket
+
+import (
+	"maps"
+	"time"
+
+	"orderbook/internal/types"
+
+	"github.com/gorilla/websocket"
+)
+
+// BookDeltaMessage carries the levels of a delta-mode book that changed since the message
+// numbered PrevSeq. A client whose latest seq for the symbol and exchange is not PrevSeq has
+// missed an update and should send resync; one that falls behind is also sent a new snapshot
+// unasked.
+type BookDeltaMessage struct {
+	Type      MessageType  `json:"type"`
+	Symbol    string       `json:"symbol,omitempty"`
+	Exchange  string       `json:"exchange"`
+	Seq       int64        `json:"seq"`
+	PrevSeq   int64        `json:"prevSeq"`
+	Bids      []PriceLevel `json:"bids"` // Changed levels; a quantity of "0" removes the level
+	Asks      []PriceLevel `json:"asks"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// streamKey identifies a delta stream. Clients with the same book parameters share a stream
+// and therefore every snapshot and delta of it.
+type streamKey struct {
+	symbol   string
+	exchange string
+	quote    string // Consolidated book only; venue books are in their own quote
+	tick     types.TickLevel
+	depth    int
+	interval time.Duration
+}
+
+// bookStream is the latest state of one delta stream (push loop only)
+type bookStream struct {
+	seq   int64
+	bids  []PriceLevel
+	asks  []PriceLevel
+	delta *BookDeltaMessage // Changes made by the latest advance, nil if there were none
+	slot  time.Time         // Slot of the latest advance
+}
+
+// streamPosition is the seq of a stream a client was last sent. The stream is kept so a client
+// is never taken as up to date with a pruned stream's successor.
+type streamPosition struct {
+	stream *bookStream
+	seq    int64
+}
+
+// advance moves the stream to book once per slot, recording the changed levels as a delta
+func (st *bookStream) advance(book OrderbookMessage, slot time.Time) {
+	if !st.slot.Before(slot) {
+		return
+	}
+	st.slot = slot
+
+	bids := diffLevels(st.bids, book.Bids)
+	asks := diffLevels(st.asks, book.Asks)
+	st.bids, st.asks = book.Bids, book.Asks
+	if len(bids) == 0 && len(asks) == 0 {
+		st.delta = nil
+		return
+	}
+
+	st.seq++
+	st.delta = &BookDeltaMessage{
+		Type:      MessageTypeBookDelta,
+		Symbol:    book.Symbol,
+		Exchange:  book.Exchange,
+		Seq:       st.seq,
+		PrevSeq:   st.seq - 1,
+		Bids:      bids,
+		Asks:      asks,
+		Timestamp: book.Timestamp,
+	}
+}
+
+// snapshot returns the full book at the stream's current seq
+func (st *bookStream) snapshot(key streamKey, timestamp int64) OrderbookMessage {
+	return OrderbookMessage{
+		Type:      MessageTypeOrderbook,
+		Symbol:    key.symbol,
+		Exchange:  key.exchange,
+		Seq:       st.seq,
+		Bids:      st.bids,
+		Asks:      st.asks,
+		Timestamp: timestamp,
+	}
+}
+
+// diffLevels returns the levels of next that are new or changed since prev, followed by the
+// levels of prev that are gone with a quantity of "0". Cumulative sums are left out, as every
+// change would shift all of them.
+func diffLevels(prev, next []PriceLevel) []PriceLevel {
+	old := make(map[string]PriceLevel, len(prev))
+	for _, level := range prev {
+		old[level.Price] = level
+	}
+
+	changes := []PriceLevel{} // Encoded as [] rather than null for an unchanged side
+	for _, level := range next {
+		before, ok := old[level.Price]
+		delete(old, level.Price)
+		if ok && before.Quantity == level.Quantity && maps.Equal(before.Venues, level.Venues) {
+			continue
+		}
+		changes = append(changes, PriceLevel{Price: level.Price, Quantity: level.Quantity, Venues: level.Venues})
+	}
+	for _, level := range prev {
+		if _, gone := old[level.Price]; gone {
+			changes = append(changes, PriceLevel{Price: level.Price, Quantity: "0"})
+		}
+	}
+	return changes
+}
+
+// appendBook adds a client's book update for the exchange and parameters of key. Snapshot
+// clients get the full book; delta clients get what changed since the seq they hold, or a
+// snapshot if they hold none or missed a delta. Must be called with the client's subMux held.
+func (s *Server) appendBook(batch []*websocket.PreparedMessage, frames frameCache, client *Client, sub *subscription, key streamKey, slot time.Time, timestamp int64, build func() OrderbookMessage) []*websocket.PreparedMessage {
+	if sub.mode != ModeDelta {
+		return append(batch, frames.get(frameKey{kind: MessageTypeOrderbook, symbol: key.symbol, exchange: key.exchange, quote: key.quote, tick: key.tick, depth: key.depth}, client.encoding, func() interface{} {
+			return build()
+		}))
+	}
+
+	stream, ok := s.streams[key]
+	if !ok {
+		stream = &bookStream{seq: 1} // Seq 1 is the empty book
+		s.streams[key] = stream
+	}
+	if stream.slot.Before(slot) {
+		stream.advance(build(), slot)
+	}
+
+	if client.positions == nil {
+		client.positions = make(map[streamKey]streamPosition)
+	}
+	held := client.positions[key]
+	client.positions[key] = streamPosition{stream: stream, seq: stream.seq}
+
+	frame := frameKey{symbol: key.symbol, exchange: key.exchange, quote: key.quote, tick: key.tick, depth: key.depth, interval: key.interval}
+	switch {
+	case held.stream != stream:
+		// Fall through to a snapshot
+	case held.seq == stream.seq:
+		return batch
+	case stream.delta != nil && held.seq == stream.delta.PrevSeq:
+		frame.kind = MessageTypeBookDelta
+		return append(batch, frames.get(frame, client.encoding, func() interface{} {
+			return *stream.delta
+		}))
+	}
+
+	frame.kind = MessageTypeOrderbook
+	return append(batch, frames.get(frame, client.encoding, func() interface{} {
+		return stream.snapshot(key, timestamp)
+	}))
+}
+
+// resync makes the next update of a client's delta streams for a symbol and exchange a
+// snapshot. An empty symbol or exchange matches all of them.
+func (c *Client) resync(symbol, exchange string) {
+	c.subMux.Lock()
+	defer c.subMux.Unlock()
+
+	for key := range c.positions {
+		if (symbol == "" || key.symbol == symbol) && (exchange == "" || key.exchange == exchange) {
+			delete(c.positions, key)
+		}
+	}
+}
+
+// pruneStreams drops delta streams no client has been updated from for a minute
+func (s *Server) pruneStreams(now time.Time) {
+	for key, stream := range s.streams {
+		if now.Sub(stream.slot) > time.Minute {
+			delete(s.streams, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"orderbook/internal/fx"
+	"orderbook/internal/types"
+)
+
+func TestDiffLevels(t *testing.T) {
+	prev := []PriceLevel{
+		{Price: "100", Quantity: "1", Cumulative: "1"},
+		{Price: "99", Quantity: "2", Cumulative: "3"},
+		{Price: "98", Quantity: "3", Cumulative: "6"},
+	}
+	next := []PriceLevel{
+		{Price: "101", Quantity: "4", Cumulative: "4"}, // New
+		{Price: "100", Quantity: "1", Cumulative: "5"}, // Unchanged, only the cumulative moved
+		{Price: "99", Quantity: "5", Cumulative: "10"}, // Changed
+	}
+
+	changes := diffLevels(prev, next)
+
+	expected := []PriceLevel{
+		{Price: "101", Quantity: "4"},
+		{Price: "99", Quantity: "5"},
+		{Price: "98", Quantity: "0"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.Price != expected[i].Price || change.Quantity != expected[i].Quantity || change.Cumulative != "" {
+			t.Errorf("Change %d: expected %+v, got %+v", i, expected[i], change)
+		}
+	}
+
+	venuesMoved := []PriceLevel{{Price: "100", Quantity: "1", Venues: map[string]string{"binancef": "1"}}}
+	venuesNow := []PriceLevel{{Price: "100", Quantity: "1", Venues: map[string]string{"bybitf": "1"}}}
+	if changes := diffLevels(venuesMoved, venuesNow); len(changes) != 1 {
+		t.Errorf("Expected a change in venue breakdown to be a change, got %+v", changes)
+	}
+}
+
+func TestAppendBookDeltaMode(t *testing.T) {
+	s := &Server{streams: make(map[streamKey]*bookStream)}
+	client := &Client{sub: newSubscription("BTC/USDT", fx.USD)}
+	client.sub.mode = ModeDelta
+	key := streamKey{symbol: "BTC/USDT", exchange: "binancef", tick: types.Tick1, depth: defaultBookDepth, interval: defaultUpdateInterval}
+
+	book := OrderbookMessage{
+		Exchange: "binancef",
+		Bids:     []PriceLevel{{Price: "100", Quantity: "1"}},
+		Asks:     []PriceLevel{{Price: "101", Quantity: "1"}},
+	}
+	start := time.Unix(1_700_000_000, 0)
+
+	// push advances the stream to book at the i-th slot and returns the kinds of message sent
+	push := func(i int) []MessageType {
+		frames := make(frameCache)
+		batch := s.appendBook(nil, frames, client, &client.sub, key, start.Add(time.Duration(i)*client.sub.interval), 0, func() OrderbookMessage {
+			return book
+		})
+		var kinds []MessageType
+		for key := range frames {
+			kinds = append(kinds, key.kind)
+		}
+		if len(batch) != len(kinds) {
+			t.Fatalf("Expected one message per frame, got %d messages for %v", len(batch), kinds)
+		}
+		return kinds
+	}
+
+	if kinds := push(1); len(kinds) != 1 || kinds[0] != MessageTypeOrderbook {
+		t.Fatalf("Expected a snapshot first, got %v", kinds)
+	}
+	if kinds := push(2); len(kinds) != 0 {
+		t.Errorf("Expected nothing for an unchanged book, got %v", kinds)
+	}
+
+	book.Bids = []PriceLevel{{Price: "100", Quantity: "2"}}
+	if kinds := push(3); len(kinds) != 1 || kinds[0] != MessageTypeBookDelta {
+		t.Errorf("Expected a delta for a changed level, got %v", kinds)
+	}
+	stream := s.streams[key]
+	if stream.delta.Seq != 3 || stream.delta.PrevSeq != 2 {
+		t.Errorf("Expected delta 2 -> 3, got %d -> %d", stream.delta.PrevSeq, stream.delta.Seq)
+	}
+
+	// The client misses a slot in which the book changes, so the next delta does not apply
+	book.Asks = nil
+	stream.advance(book, start.Add(4*client.sub.interval))
+	book.Bids = nil
+	if kinds := push(5); len(kinds) != 1 || kinds[0] != MessageTypeOrderbook {
+		t.Errorf("Expected a snapshot after a missed delta, got %v", kinds)
+	}
+
+	client.resync("", "binancef")
+	if kinds := push(6); len(kinds) != 1 || kinds[0] != MessageTypeOrderbook {
+		t.Errorf("Expected a snapshot after resync, got %v", kinds)
+	}
+}
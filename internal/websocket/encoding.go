@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoding is a wire format, negotiated through the websocket subprotocol. Both carry the same
+// messages with the same field names; decimals stay strings so no precision is lost.
+type Encoding string
+
+const (
+	EncodingJSON    Encoding = "json"    // Text frames; also used when the client asks for no subprotocol
+	EncodingMsgpack Encoding = "msgpack" // Binary frames of MessagePack maps
+)
+
+// subprotocols are offered to clients, preferred first when a client accepts several
+var subprotocols = []string{string(EncodingMsgpack), string(EncodingJSON)}
+
+// negotiatedEncoding returns the encoding of the subprotocol agreed on during the upgrade
+func negotiatedEncoding(conn *websocket.Conn) Encoding {
+	if Encoding(conn.Subprotocol()) == EncodingMsgpack {
+		return EncodingMsgpack
+	}
+	return EncodingJSON
+}
+
+// messageType returns the websocket frame type the encoding is sent in
+func (e Encoding) messageType() int {
+	if e == EncodingMsgpack {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
+
+// contentType returns the MIME type of the encoding for HTTP responses
+func (e Encoding) contentType() string {
+	if e == EncodingMsgpack {
+		return "application/msgpack"
+	}
+	return "application/json"
+}
+
+// marshal encodes a message
+func (e Encoding) marshal(msg interface{}) ([]byte, error) {
+	if e != EncodingMsgpack {
+		return json.Marshal(msg)
+	}
+
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json") // Same field names as the JSON encoding
+	enc.UseCompactInts(true)
+	if err := enc.Encode(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalClientMessage decodes a client request, MessagePack if sent as a binary frame
+func unmarshalClientMessage(messageType int, data []byte, msg *ClientMessage) error {
+	if messageType != websocket.BinaryMessage {
+		return json.Unmarshal(data, msg)
+	}
+
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(msg)
+}
+
+// frame is a message shared by many clients, encoded at most once per encoding
+type frame struct {
+	msg      interface{}
+	prepared map[Encoding]*websocket.PreparedMessage
+}
+
+// newFrame wraps a message to be sent to several clients
+func newFrame(msg interface{}) *frame {
+	return &frame{msg: msg, prepared: make(map[Encoding]*websocket.PreparedMessage, 1)}
+}
+
+// encode returns the message in the given encoding, or nil if it cannot be encoded
+func (f *frame) encode(encoding Encoding) (*websocket.PreparedMessage, error) {
+	if prepared, ok := f.prepared[encoding]; ok {
+		return prepared, nil
+	}
+
+	data, err := encoding.marshal(f.msg)
+	if err != nil {
+		f.prepared[encoding] = nil
+		return nil, err
+	}
+	prepared, err := websocket.NewPreparedMessage(encoding.messageType(), data)
+	f.prepared[encoding] = prepared
+	return prepared, err
+}
@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgpackUsesJSONFieldNames(t *testing.T) {
+	data, err := EncodingMsgpack.marshal(OrderbookMessage{
+		Type:     MessageTypeOrderbook,
+		Exchange: "binancef",
+		Bids:     []PriceLevel{{Price: "100.5", Quantity: "2", Cumulative: "2"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := msgpack.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded["exchange"] != "binancef" {
+		t.Errorf("Expected exchange binancef, got %v", decoded["exchange"])
+	}
+	if _, ok := decoded["seq"]; ok {
+		t.Error("Expected omitempty fields to be left out")
+	}
+	bids, ok := decoded["bids"].([]interface{})
+	if !ok || len(bids) != 1 || bids[0].(map[string]interface{})["price"] != "100.5" {
+		t.Errorf("Expected decimals to stay strings, got %v", decoded["bids"])
+	}
+}
+
+func TestUnmarshalClientMessage(t *testing.T) {
+	packed, err := EncodingMsgpack.marshal(ClientMessage{Type: "subscribe", Exchanges: []string{"bybitf"}, Depth: 50})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		messageType int
+		data        []byte
+	}{
+		{"json text frame", websocket.TextMessage, []byte(`{"type":"subscribe","exchanges":["bybitf"],"depth":50}`)},
+		{"msgpack binary frame", websocket.BinaryMessage, packed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg ClientMessage
+			if err := unmarshalClientMessage(tt.messageType, tt.data, &msg); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if msg.Type != "subscribe" || len(msg.Exchanges) != 1 || msg.Exchanges[0] != "bybitf" || msg.Depth != 50 {
+				t.Errorf("Unexpected message %+v", msg)
+			}
+		})
+	}
+}
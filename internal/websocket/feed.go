@@ -0,0 +1,414 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"orderbook/internal/aggregation"
+	"orderbook/internal/consolidated"
+	"orderbook/internal/derivatives"
+	"orderbook/internal/exchange"
+	"orderbook/internal/fx"
+	"orderbook/internal/history"
+	"orderbook/internal/instruments"
+	"orderbook/internal/orderbook"
+	"orderbook/internal/symbols"
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+// Feed is one monitored symbol: the books of the venues streaming it and everything derived
+// from them. Each symbol runs with its own venues, so feeds are added to and removed from a
+// running server without disturbing the others.
+type Feed struct {
+	symbol      string           // Canonical, e.g. "BTC/USDT"
+	books       *orderbook.Books // Venues add their book once it is initialized
+	derivatives *derivatives.Store
+	instruments *instruments.Registry
+	history     *history.Store
+	rates       *fx.Rates // Derived from this symbol's venues only
+	marketsMux  sync.RWMutex
+	symbolMsg   *SymbolMessage            // Markets of the symbol, nil if unknown (guarded by marketsMux)
+	venueQuotes map[string]symbols.Symbol // Market each venue streams (guarded by marketsMux)
+	tickMux     sync.RWMutex
+	tickLevels  []types.TickLevel  // Ladder last sent to clients (guarded by tickMux)
+	trades      map[string][]Trade // Trades received since the last push, per exchange
+	tradesMux   sync.Mutex
+	done        chan struct{} // Closed once the feed is removed from the server
+}
+
+// NewFeed creates the feed of a symbol with no venue books yet. The venues of the symbol add
+// theirs to Books as they connect.
+func NewFeed(symbol symbols.Symbol, derivativesStore *derivatives.Store, registry *instruments.Registry, statsHistory *history.Store) *Feed {
+	return &Feed{
+		symbol:      symbol.String(),
+		books:       orderbook.NewBooks(),
+		derivatives: derivativesStore,
+		instruments: registry,
+		history:     statsHistory,
+		rates:       fx.NewRates(),
+		venueQuotes: make(map[string]symbols.Symbol),
+		tickLevels:  types.AvailableTickLevels,
+		trades:      make(map[string][]Trade),
+		done:        make(chan struct{}),
+	}
+}
+
+// Symbol returns the canonical symbol of the feed
+func (f *Feed) Symbol() string {
+	return f.symbol
+}
+
+// Books returns the venue books of the symbol, keyed by exchange
+func (f *Feed) Books() *orderbook.Books {
+	return f.books
+}
+
+// SetMarkets records the market each venue streams for the symbol, and why the others do not
+// stream it. Feeds without markets, e.g. during replay, take every venue as quoted in the
+// reference quote.
+func (f *Feed) SetMarkets(markets []symbols.Market, unsupported map[exchange.ExchangeName]error) {
+	msg := &SymbolMessage{
+		Type:      MessageTypeSymbol,
+		Symbol:    f.symbol,
+		Markets:   make(map[string]string, len(markets)),
+		Timestamp: time.Now().UnixMilli(),
+	}
+	venueQuotes := make(map[string]symbols.Symbol, len(markets))
+	for _, market := range markets {
+		msg.Markets[string(market.Exchange)] = market.Native
+		venueQuotes[string(market.Exchange)] = market.Symbol
+	}
+	if len(unsupported) > 0 {
+		msg.Unsupported = make(map[string]string, len(unsupported))
+		for name, err := range unsupported {
+			msg.Unsupported[string(name)] = err.Error()
+		}
+	}
+
+	f.marketsMux.Lock()
+	f.symbolMsg = msg
+	f.venueQuotes = venueQuotes
+	f.marketsMux.Unlock()
+}
+
+// markets returns the symbol message of the feed, nil if its markets are unknown
+func (f *Feed) markets() *SymbolMessage {
+	f.marketsMux.RLock()
+	defer f.marketsMux.RUnlock()
+	return f.symbolMsg
+}
+
+// PublishTrade queues an executed trade for clients subscribed to the trades channel
+func (f *Feed) PublishTrade(trade *exchange.Trade) {
+	f.tradesMux.Lock()
+	defer f.tradesMux.Unlock()
+
+	name := string(trade.Exchange)
+	pending := f.trades[name]
+	if len(pending) >= maxPendingTrades {
+		pending = pending[1:]
+	}
+	f.trades[name] = append(pending, Trade{
+		ID:       trade.TradeID,
+		Price:    trade.Price,
+		Quantity: trade.Quantity,
+		Side:     string(trade.Side),
+		Time:     trade.Timestamp.UnixMilli(),
+	})
+}
+
+// takeTrades returns and clears the trades queued since the previous push
+func (f *Feed) takeTrades() map[string][]Trade {
+	f.tradesMux.Lock()
+	defer f.tradesMux.Unlock()
+
+	if len(f.trades) == 0 {
+		return nil
+	}
+	trades := f.trades
+	f.trades = make(map[string][]Trade)
+	return trades
+}
+
+// streamingBooks returns the books of the venues that are initialized
+func (f *Feed) streamingBooks() map[string]*orderbook.OrderBook {
+	venues := f.books.Snapshot()
+	books := make(map[string]*orderbook.OrderBook, len(venues))
+	for _, venue := range venues {
+		if venue.Book.IsInitialized() {
+			books[venue.Exchange] = venue.Book
+		}
+	}
+	return books
+}
+
+// venueQuote returns the quote asset an exchange trades the symbol in, empty if unknown
+func (f *Feed) venueQuote(exchange string) string {
+	f.marketsMux.RLock()
+	defer f.marketsMux.RUnlock()
+	return f.venueQuotes[exchange].Quote
+}
+
+// conversionRate returns the factor converting an exchange's prices into refQuote. Venues
+// whose market is unknown are taken as already quoted in it.
+func (f *Feed) conversionRate(exchange, refQuote string) (decimal.Decimal, bool) {
+	f.marketsMux.RLock()
+	market, ok := f.venueQuotes[exchange]
+	f.marketsMux.RUnlock()
+
+	if !ok {
+		return decimal.NewFromInt(1), true
+	}
+	return f.rates.Rate(market.Quote, refQuote)
+}
+
+// consolidatedIn returns the merged book of every venue with prices converted into quote.
+// Venues whose quote cannot be converted are left out.
+func (f *Feed) consolidatedIn(quote string) *consolidated.Book {
+	book := consolidated.New(f.books)
+	book.SetPriceConverter(func(exchange string) (decimal.Decimal, bool) {
+		return f.conversionRate(exchange, quote)
+	})
+	return book
+}
+
+// updateRates derives the stablecoin rates from the mid price of every running venue
+func (f *Feed) updateRates() {
+	f.marketsMux.RLock()
+	observations := make([]fx.Observation, 0, len(f.venueQuotes))
+	for exchangeName, market := range f.venueQuotes {
+		ob, ok := f.books.Get(exchangeName)
+		if !ok || !ob.IsInitialized() {
+			continue
+		}
+		stats := ob.GetStats()
+		if stats.BestBid.IsZero() || stats.BestAsk.IsZero() {
+			continue
+		}
+		observations = append(observations, fx.Observation{
+			Quote: market.Quote,
+			Mid:   stats.BestBid.Add(stats.BestAsk).Div(decimal.NewFromInt(2)),
+			Spot:  market.Type == exchange.InstrumentSpot,
+		})
+	}
+	f.marketsMux.RUnlock()
+
+	f.rates.Update(observations, time.Now())
+}
+
+// ladder returns the tick ladder last sent to clients
+func (f *Feed) ladder() []types.TickLevel {
+	f.tickMux.RLock()
+	defer f.tickMux.RUnlock()
+	return f.tickLevels
+}
+
+// currentTickLevels derives the tick ladder from the coarsest venue tick and the consolidated
+// mid price, falling back to the fixed levels until instrument metadata and prices are known
+func (f *Feed) currentTickLevels() []types.TickLevel {
+	tickSize, ok := f.instruments.CoarsestTick()
+	if !ok {
+		return types.AvailableTickLevels
+	}
+
+	stats := f.consolidatedIn(fx.USD).Stats()
+	if stats.BestBid.IsZero() || stats.BestAsk.IsZero() {
+		return types.AvailableTickLevels
+	}
+	mid := stats.BestBid.Add(stats.BestAsk).Div(decimal.NewFromInt(2))
+	return aggregation.TickLevels(tickSize, mid)
+}
+
+// AddFeed starts serving a symbol. Clients following it are sent its markets and tick ladder,
+// and every client the new list of symbols.
+func (s *Server) AddFeed(feed *Feed) {
+	s.feedsMux.Lock()
+	if replaced, ok := s.feeds[feed.symbol]; ok {
+		close(replaced.done)
+	} else {
+		s.feedOrder = append(s.feedOrder, feed.symbol)
+	}
+	s.feeds[feed.symbol] = feed
+	s.feedsMux.Unlock()
+
+	go s.recordHistory(feed)
+	log.Printf("Serving %s", feed.symbol)
+
+	s.broadcast <- outbound{msg: s.symbolsMessage(time.Now().UnixMilli())}
+
+	s.clientsMux.RLock()
+	for client := range s.clients {
+		if client.follows(feed.symbol) {
+			go s.sendFeedState(client, feed, true)
+		}
+	}
+	s.clientsMux.RUnlock()
+}
+
+// RemoveFeed stops serving a symbol. Clients following it stop receiving its updates and every
+// client is sent the new list of symbols.
+func (s *Server) RemoveFeed(symbol symbols.Symbol) {
+	s.feedsMux.Lock()
+	feed, ok := s.feeds[symbol.String()]
+	if ok {
+		delete(s.feeds, feed.symbol)
+		s.feedOrder = slices.DeleteFunc(s.feedOrder, func(name string) bool { return name == feed.symbol })
+	}
+	s.feedsMux.Unlock()
+
+	if !ok {
+		return
+	}
+	close(feed.done)
+	log.Printf("Stopped serving %s", feed.symbol)
+
+	s.broadcast <- outbound{msg: s.symbolsMessage(time.Now().UnixMilli())}
+}
+
+// feed returns the feed of a canonical symbol, nil if it is not monitored
+func (s *Server) feed(symbol string) *Feed {
+	s.feedsMux.RLock()
+	defer s.feedsMux.RUnlock()
+	return s.feeds[symbol]
+}
+
+// feedList returns every feed in the order they were added
+func (s *Server) feedList() []*Feed {
+	s.feedsMux.RLock()
+	defer s.feedsMux.RUnlock()
+
+	feeds := make([]*Feed, 0, len(s.feedOrder))
+	for _, symbol := range s.feedOrder {
+		feeds = append(feeds, s.feeds[symbol])
+	}
+	return feeds
+}
+
+// followedFeeds returns the feeds a client follows, in the order they were added
+func (s *Server) followedFeeds(client *Client) []*Feed {
+	feeds := s.feedList()
+	followed := feeds[:0]
+	for _, feed := range feeds {
+		if client.follows(feed.symbol) {
+			followed = append(followed, feed)
+		}
+	}
+	return followed
+}
+
+// lookupFeed returns the feed of a symbol named in a request, or of the default symbol if none
+// is named
+func (s *Server) lookupFeed(name string) (*Feed, error) {
+	symbol, err := parseSymbol(name)
+	if err != nil {
+		return nil, err
+	}
+	if symbol == "" {
+		symbol = s.defaultSymbol()
+		if symbol == "" {
+			return nil, fmt.Errorf("no symbol is monitored")
+		}
+	}
+
+	feed := s.feed(symbol)
+	if feed == nil {
+		return nil, fmt.Errorf("%s is not monitored", symbol)
+	}
+	return feed, nil
+}
+
+// defaultSymbol returns the symbol added first, which new clients follow, empty if there is none
+func (s *Server) defaultSymbol() string {
+	s.feedsMux.RLock()
+	defer s.feedsMux.RUnlock()
+
+	if len(s.feedOrder) == 0 {
+		return ""
+	}
+	return s.feedOrder[0]
+}
+
+// ladders returns the tick ladder of every feed
+func (s *Server) ladders() map[string][]types.TickLevel {
+	feeds := s.feedList()
+	ladders := make(map[string][]types.TickLevel, len(feeds))
+	for _, feed := range feeds {
+		ladders[feed.symbol] = feed.ladder()
+	}
+	return ladders
+}
+
+// symbolsMessage lists the monitored symbols, the default first
+func (s *Server) symbolsMessage(timestamp int64) SymbolsMessage {
+	s.feedsMux.RLock()
+	defer s.feedsMux.RUnlock()
+
+	return SymbolsMessage{Type: MessageTypeSymbols, Symbols: slices.Clone(s.feedOrder), Timestamp: timestamp}
+}
+
+// sendFeedState sends a client the tick ladder of a feed, preceded by its markets if
+// withMarkets is set and they are known
+func (s *Server) sendFeedState(client *Client, feed *Feed, withMarkets bool) {
+	if symbolMsg := feed.markets(); withMarkets && symbolMsg != nil {
+		s.sendToClient(client, symbolMsg)
+	}
+
+	ladder := feed.ladder()
+	client.subMux.Lock()
+	tick := client.sub.tickFor(feed.symbol, ladder)
+	client.subMux.Unlock()
+	s.sendToClient(client, newTickLevelsMessage(feed.symbol, ladder, tick, time.Now().UnixMilli()))
+}
+
+// requestSymbol asks the runtime to start or stop monitoring a symbol, after checking the
+// request can be granted
+func (s *Server) requestSymbol(name string, remove bool) error {
+	if s.symbolRequests == nil {
+		return fmt.Errorf("symbols cannot be changed on this server")
+	}
+	symbol, err := symbols.Parse(name)
+	if err != nil {
+		return err
+	}
+
+	running := s.feed(symbol.String()) != nil
+	switch {
+	case remove && !running:
+		return fmt.Errorf("%s is not monitored", symbol)
+	case !remove && running:
+		return fmt.Errorf("%s is already monitored", symbol)
+	case !remove && s.symbolLimit > 0 && len(s.feedList()) >= s.symbolLimit:
+		return fmt.Errorf("at most %d symbols can be monitored at once", s.symbolLimit)
+	}
+
+	select {
+	case s.symbolRequests <- SymbolRequest{Symbol: name, Remove: remove}:
+		return nil
+	default:
+		return fmt.Errorf("too many pending symbol requests, try again shortly")
+	}
+}
+
+// follows reports whether the client receives anything of a canonical symbol
+func (c *Client) follows(symbol string) bool {
+	c.subMux.Lock()
+	defer c.subMux.Unlock()
+	return c.sub.follows(symbol)
+}
+
+// parseSymbol returns the canonical form of a symbol named in a request, empty if none is named
+func parseSymbol(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	symbol, err := symbols.Parse(name)
+	if err != nil {
+		return "", err
+	}
+	return symbol.String(), nil
+}
@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"orderbook/internal/derivatives"
+	"orderbook/internal/history"
+	"orderbook/internal/instruments"
+	"orderbook/internal/symbols"
+)
+
+func newTestFeed(symbol string) *Feed {
+	return NewFeed(symbols.MustParse(symbol), derivatives.NewStore(), instruments.NewRegistry(), history.NewStore(time.Second, time.Minute))
+}
+
+func TestAddAndRemoveFeeds(t *testing.T) {
+	s := NewServer("0", nil)
+	btc, eth := newTestFeed("BTCUSDT"), newTestFeed("ETHUSDT")
+	s.AddFeed(btc)
+	s.AddFeed(eth)
+
+	if symbols := s.symbolsMessage(0).Symbols; !slices.Equal(symbols, []string{"BTC/USDT", "ETH/USDT"}) {
+		t.Errorf("Expected symbols in the order they were added, got %v", symbols)
+	}
+	if s.defaultSymbol() != "BTC/USDT" {
+		t.Errorf("Expected the first symbol to be the default, got %q", s.defaultSymbol())
+	}
+
+	s.RemoveFeed(symbols.MustParse("BTC/USDT"))
+
+	select {
+	case <-btc.done:
+	default:
+		t.Error("Expected a removed feed to be stopped")
+	}
+	if s.defaultSymbol() != "ETH/USDT" {
+		t.Errorf("Expected the next symbol to become the default, got %q", s.defaultSymbol())
+	}
+	if _, err := s.lookupFeed("BTCUSDT"); err == nil {
+		t.Error("Expected a removed symbol not to be found")
+	}
+	if feed, err := s.lookupFeed(""); err != nil || feed != eth {
+		t.Errorf("Expected the default feed, got %v", err)
+	}
+}
+
+func TestRequestSymbol(t *testing.T) {
+	requests := make(chan SymbolRequest, 1)
+	s := NewServer("0", requests)
+	s.SetSymbolLimit(2)
+	s.AddFeed(newTestFeed("BTCUSDT"))
+
+	tests := []struct {
+		name    string
+		symbol  string
+		remove  bool
+		wantErr bool
+	}{
+		{name: "add", symbol: "ETHUSDT"},
+		{name: "remove", symbol: "BTC/USDT", remove: true},
+		{name: "add a monitored symbol", symbol: "BTC-USDT", wantErr: true},
+		{name: "remove a symbol not monitored", symbol: "SOLUSDT", remove: true, wantErr: true},
+		{name: "invalid symbol", symbol: "BTC", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.requestSymbol(tt.symbol, tt.remove)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if request := <-requests; request.Symbol != tt.symbol || request.Remove != tt.remove {
+				t.Errorf("Unexpected request %+v", request)
+			}
+		})
+	}
+
+	s.AddFeed(newTestFeed("ETHUSDT"))
+	if err := s.requestSymbol("SOLUSDT", false); err == nil {
+		t.Error("Expected symbols beyond the limit to be refused")
+	}
+	if err := NewServer("0", nil).requestSymbol("SOLUSDT", false); err == nil {
+		t.Error("Expected a server without a runtime to refuse symbol requests")
+	}
+}
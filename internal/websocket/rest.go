@@ -0,0 +1,350 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"orderbook/internal/consolidated"
+	"orderbook/internal/history"
+	"orderbook/internal/types"
+)
+
+// SymbolsResponse lists every monitored symbol with the market each venue streams, served by
+// /api/symbols
+type SymbolsResponse struct {
+	Symbols   []SymbolMessage `json:"symbols"` // Default symbol first
+	Timestamp int64           `json:"timestamp"`
+}
+
+// ExchangesResponse lists every venue known for a symbol, served by /api/exchanges
+type ExchangesResponse struct {
+	Symbol    string           `json:"symbol,omitempty"`
+	Exchanges []ExchangeStatus `json:"exchanges"` // In name order
+	Timestamp int64            `json:"timestamp"`
+}
+
+// ExchangeStatus is the state of one venue
+type ExchangeStatus struct {
+	Exchange   string `json:"exchange"`
+	Status     string `json:"status"`               // "streaming", "connecting" or "unsupported"
+	Market     string `json:"market,omitempty"`     // Venue symbol
+	Reason     string `json:"reason,omitempty"`     // Why an unsupported venue is not streaming the symbol
+	LastUpdate int64  `json:"lastUpdate,omitempty"` // Unix milliseconds of the latest book event
+}
+
+// StatsResponse is the /api/stats response when no exchange is selected
+type StatsResponse struct {
+	Symbol    string         `json:"symbol"`
+	Stats     []StatsMessage `json:"stats"` // Venues in name order, then consolidated
+	Timestamp int64          `json:"timestamp"`
+}
+
+// handleSymbols lists the monitored symbols: /api/symbols
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	timestamp := time.Now().UnixMilli()
+	feeds := s.feedList()
+
+	response := SymbolsResponse{Symbols: make([]SymbolMessage, 0, len(feeds)), Timestamp: timestamp}
+	for _, feed := range feeds {
+		symbolMsg := feed.markets()
+		if symbolMsg == nil {
+			symbolMsg = &SymbolMessage{Type: MessageTypeSymbol, Symbol: feed.symbol, Markets: map[string]string{}, Timestamp: timestamp}
+		}
+		response.Symbols = append(response.Symbols, *symbolMsg)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleExchanges lists every venue of a symbol with whether it is streaming:
+// /api/exchanges?symbol=ETHUSDT
+func (s *Server) handleExchanges(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.requestFeed(w, r)
+	if !ok {
+		return
+	}
+
+	statuses := make(map[string]ExchangeStatus)
+	response := ExchangesResponse{Symbol: feed.symbol, Timestamp: time.Now().UnixMilli()}
+	if symbolMsg := feed.markets(); symbolMsg != nil {
+		for name, market := range symbolMsg.Markets {
+			statuses[name] = ExchangeStatus{Exchange: name, Status: "connecting", Market: market}
+		}
+		for name, reason := range symbolMsg.Unsupported {
+			statuses[name] = ExchangeStatus{Exchange: name, Status: "unsupported", Reason: reason}
+		}
+	}
+	for _, venue := range feed.books.Snapshot() {
+		status := statuses[venue.Exchange]
+		status.Exchange = venue.Exchange
+		status.Status = "connecting"
+		if venue.Book.IsInitialized() {
+			status.Status = "streaming"
+			if lastEvent := venue.Book.GetStats().LastEventTime; !lastEvent.IsZero() {
+				status.LastUpdate = lastEvent.UnixMilli()
+			}
+		}
+		statuses[venue.Exchange] = status
+	}
+
+	response.Exchanges = make([]ExchangeStatus, 0, len(statuses))
+	for _, status := range statuses {
+		response.Exchanges = append(response.Exchanges, status)
+	}
+	sort.Slice(response.Exchanges, func(i, j int) bool {
+		return response.Exchanges[i].Exchange < response.Exchanges[j].Exchange
+	})
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleBook serves one venue's book, or the consolidated book, aggregated to a tick:
+// /api/book?symbol=BTCUSDT&exchange=binancef&tick=10&depth=50
+func (s *Server) handleBook(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.requestFeed(w, r)
+	if !ok {
+		return
+	}
+	quote, ok := s.requestQuote(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	exchangeName := query.Get("exchange")
+
+	ladder := feed.ladder()
+	sub := newSubscription(feed.symbol, quote)
+	msg := ClientMessage{Type: "subscribe", Symbol: feed.symbol}
+	if value := query.Get("tick"); value != "" {
+		tick, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid tick: %v", err))
+			return
+		}
+		msg.Tick = tick
+	}
+	if value := query.Get("depth"); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth == 0 {
+			writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid depth %q", value))
+			return
+		}
+		msg.Depth = depth
+	}
+	// Validated the same way as a websocket subscription
+	if err := sub.subscribe(msg, map[string][]types.TickLevel{feed.symbol: ladder}); err != nil {
+		writeHTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	tick := sub.tickFor(feed.symbol, ladder)
+
+	timestamp := time.Now().UnixMilli()
+	if exchangeName == consolidated.ExchangeName {
+		writeResponse(w, r, http.StatusOK, s.buildConsolidatedOrderbookMessage(feed, quote, tick, sub.depth, timestamp))
+		return
+	}
+
+	ob, ok := feed.books.Get(exchangeName)
+	if !ok || !ob.IsInitialized() {
+		writeHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("exchange %q is not available", exchangeName))
+		return
+	}
+	writeResponse(w, r, http.StatusOK, s.buildOrderbookMessage(feed.symbol, exchangeName, ob, tick, sub.depth, timestamp))
+}
+
+// handleStats serves the stats of one exchange of a symbol, or of all of them if none is named:
+// /api/stats?symbol=BTCUSDT&exchange=binancef&quote=USDT
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.requestFeed(w, r)
+	if !ok {
+		return
+	}
+	quote, ok := s.requestQuote(w, r)
+	if !ok {
+		return
+	}
+	exchangeName := r.URL.Query().Get("exchange")
+	timestamp := time.Now().UnixMilli()
+	feed.updateRates()
+
+	if exchangeName == consolidated.ExchangeName {
+		writeResponse(w, r, http.StatusOK, s.buildConsolidatedStatsMessage(feed, quote, timestamp))
+		return
+	}
+	if exchangeName != "" {
+		ob, ok := feed.books.Get(exchangeName)
+		if !ok || !ob.IsInitialized() {
+			writeHTTPError(w, r, http.StatusNotFound, fmt.Sprintf("exchange %q is not available", exchangeName))
+			return
+		}
+		writeResponse(w, r, http.StatusOK, s.buildStatsMessage(feed, exchangeName, ob, quote, timestamp))
+		return
+	}
+
+	venues := feed.books.Snapshot()
+	response := StatsResponse{Symbol: feed.symbol, Stats: make([]StatsMessage, 0, len(venues)+1), Timestamp: timestamp}
+	for _, venue := range venues {
+		if venue.Book.IsInitialized() {
+			response.Stats = append(response.Stats, s.buildStatsMessage(feed, venue.Exchange, venue.Book, quote, timestamp))
+		}
+	}
+	if len(response.Stats) > 0 {
+		response.Stats = append(response.Stats, s.buildConsolidatedStatsMessage(feed, quote, timestamp))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleImpact runs a market-impact calculation, like the calc_impact websocket request:
+// /api/impact?symbol=BTCUSDT&exchange=binancef&side=buy&size=5&unit=base
+func (s *Server) handleImpact(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	size, err := strconv.ParseFloat(query.Get("size"), 64)
+	if err != nil {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid size %q", query.Get("size")))
+		return
+	}
+
+	impact, err := s.calculateImpact(ClientMessage{
+		Type:     "calc_impact",
+		Symbol:   query.Get("symbol"),
+		Exchange: query.Get("exchange"),
+		Side:     query.Get("side"),
+		Size:     size,
+		Unit:     query.Get("unit"),
+	})
+	if err != nil {
+		writeHTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeResponse(w, r, http.StatusOK, impact)
+}
+
+// handleHistory serves recorded stats of one exchange of a symbol, either whole samples or a
+// single metric:
+// /api/history?symbol=BTCUSDT&exchange=binancef&metric=deltaQty&band=2%25&from=<unix ms>&to=<unix ms>
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.requestFeed(w, r)
+	if !ok {
+		return
+	}
+	quote, ok := s.requestQuote(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	exchangeName := query.Get("exchange")
+	if exchangeName == "" {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("exchange is required, one of %v", feed.history.Exchanges()))
+		return
+	}
+	from, err := parseMillis(query.Get("from"))
+	if err != nil {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid from: %v", err))
+		return
+	}
+	to, err := parseMillis(query.Get("to"))
+	if err != nil {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid to: %v", err))
+		return
+	}
+
+	samples := feed.history.Range(exchangeName, from, to)
+
+	metric := query.Get("metric")
+	if metric == "" {
+		writeResponse(w, r, http.StatusOK, newHistoryMessage(feed, exchangeName, samples, quote, time.Now().UnixMilli()))
+		return
+	}
+	if !slices.Contains(history.Metrics(), metric) {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("unknown metric %q, one of %v", metric, history.Metrics()))
+		return
+	}
+
+	series := HistorySeries{
+		Symbol:   feed.symbol,
+		Exchange: exchangeName,
+		Metric:   metric,
+		Band:     query.Get("band"),
+		Points:   make([]HistoryPoint, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		stats, _ := sampleStats(feed, sample, quote)
+		value, err := history.Metric(stats, metric, series.Band)
+		if err != nil {
+			writeHTTPError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		series.Points = append(series.Points, HistoryPoint{Time: sample.Time.UnixMilli(), Value: value.String()})
+	}
+	writeResponse(w, r, http.StatusOK, series)
+}
+
+// requestFeed returns the feed of the symbol a request names, or of the default symbol if it
+// names none, responding with an error if that symbol is not monitored
+func (s *Server) requestFeed(w http.ResponseWriter, r *http.Request) (*Feed, bool) {
+	feed, err := s.lookupFeed(r.URL.Query().Get("symbol"))
+	if err != nil {
+		writeHTTPError(w, r, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	return feed, true
+}
+
+// requestQuote returns the reference quote named by the request's quote parameter, or the
+// default one if it has none, writing an error response if prices cannot be converted into it
+func (s *Server) requestQuote(w http.ResponseWriter, r *http.Request) (string, bool) {
+	quote := r.URL.Query().Get("quote")
+	if quote == "" {
+		return s.referenceQuote(), true
+	}
+	if !s.knownQuote(quote) {
+		writeHTTPError(w, r, http.StatusBadRequest, fmt.Sprintf("no conversion rate for %q", quote))
+		return "", false
+	}
+	return quote, true
+}
+
+// parseMillis parses a unix millisecond timestamp, leaving an empty one as the zero time
+func parseMillis(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// responseEncoding picks MessagePack for requests that accept it, JSON otherwise
+func responseEncoding(r *http.Request) Encoding {
+	if strings.Contains(r.Header.Get("Accept"), "application/msgpack") {
+		return EncodingMsgpack
+	}
+	return EncodingJSON
+}
+
+// writeResponse encodes a response body in the encoding the request accepts
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, body interface{}) {
+	encoding := responseEncoding(r)
+	data, err := encoding.marshal(body)
+	if err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "error encoding response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", encoding.contentType())
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeHTTPError responds with an error body
+func writeHTTPError(w http.ResponseWriter, r *http.Request, status int, errMsg string) {
+	writeResponse(w, r, status, map[string]string{"error": errMsg})
+}
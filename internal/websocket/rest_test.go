@@ -0,0 +1,138 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"orderbook/internal/derivatives"
+	"orderbook/internal/exchange"
+	"orderbook/internal/history"
+	"orderbook/internal/instruments"
+	"orderbook/internal/orderbook"
+	"orderbook/internal/symbols"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	ob := orderbook.New()
+	err := ob.LoadSnapshot(&exchange.Snapshot{
+		Exchange:  "binancef",
+		Bids:      []exchange.PriceLevel{{Price: "100", Quantity: "1"}, {Price: "99.5", Quantity: "2"}, {Price: "98", Quantity: "3"}},
+		Asks:      []exchange.PriceLevel{{Price: "101", Quantity: "1"}, {Price: "102", Quantity: "2"}},
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	ob.ProcessBufferedEvents()
+
+	feed := NewFeed(symbols.MustParse("BTCUSDT"), derivatives.NewStore(), instruments.NewRegistry(), history.NewStore(time.Second, time.Minute))
+	feed.Books().Set("binancef", ob)
+	feed.Books().Set("bybitf", orderbook.New())
+	s := NewServer("0", nil)
+	s.AddFeed(feed)
+	return s
+}
+
+func TestRESTEndpoints(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		url     string
+		status  int
+	}{
+		{"symbols", s.handleSymbols, "/api/symbols", http.StatusOK},
+		{"exchanges", s.handleExchanges, "/api/exchanges", http.StatusOK},
+		{"exchanges of a symbol", s.handleExchanges, "/api/exchanges?symbol=BTC/USDT", http.StatusOK},
+		{"book of a symbol not monitored", s.handleBook, "/api/book?symbol=ETHUSDT&exchange=binancef", http.StatusNotFound},
+		{"book", s.handleBook, "/api/book?exchange=binancef&tick=1&depth=5", http.StatusOK},
+		{"consolidated book", s.handleBook, "/api/book?exchange=consolidated", http.StatusOK},
+		{"book of a connecting venue", s.handleBook, "/api/book?exchange=bybitf", http.StatusNotFound},
+		{"book tick outside the ladder", s.handleBook, "/api/book?exchange=binancef&tick=3", http.StatusBadRequest},
+		{"book depth too large", s.handleBook, "/api/book?exchange=binancef&depth=1000", http.StatusBadRequest},
+		{"stats", s.handleStats, "/api/stats?exchange=binancef", http.StatusOK},
+		{"all stats", s.handleStats, "/api/stats", http.StatusOK},
+		{"stats in another quote", s.handleStats, "/api/stats?exchange=consolidated&quote=USDT", http.StatusOK},
+		{"stats in an unknown quote", s.handleStats, "/api/stats?quote=JPY", http.StatusBadRequest},
+		{"impact", s.handleImpact, "/api/impact?exchange=binancef&side=buy&size=2", http.StatusOK},
+		{"impact without size", s.handleImpact, "/api/impact?exchange=binancef&side=buy", http.StatusBadRequest},
+		{"history without exchange", s.handleHistory, "/api/history", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Expected a JSON response, got %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestRESTBook(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	s.handleBook(w, httptest.NewRequest(http.MethodGet, "/api/book?exchange=binancef&tick=1&depth=2", nil))
+
+	var book OrderbookMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &book); err != nil {
+		t.Fatalf("Failed to decode book: %v", err)
+	}
+	// 99.5 rounds down into the 99 bucket at tick 1, and depth 2 cuts off 98
+	if len(book.Bids) != 2 || book.Bids[0].Price != "100" || book.Bids[1].Quantity != "2" {
+		t.Errorf("Unexpected bids %+v", book.Bids)
+	}
+	if len(book.Asks) != 2 || book.Asks[1].Cumulative != "3" {
+		t.Errorf("Unexpected asks %+v", book.Asks)
+	}
+	if book.Symbol != "BTC/USDT" {
+		t.Errorf("Expected the default symbol, got %q", book.Symbol)
+	}
+}
+
+func TestRESTExchanges(t *testing.T) {
+	s := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/exchanges", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	w := httptest.NewRecorder()
+	s.handleExchanges(w, req)
+
+	if w.Header().Get("Content-Type") != "application/msgpack" {
+		t.Fatalf("Expected a MessagePack response, got %q", w.Header().Get("Content-Type"))
+	}
+
+	var response struct {
+		Exchanges []struct {
+			Exchange string `msgpack:"exchange"`
+			Status   string `msgpack:"status"`
+		} `msgpack:"exchanges"`
+	}
+	if err := msgpack.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := map[string]string{"binancef": "streaming", "bybitf": "connecting"}
+	if len(response.Exchanges) != len(expected) {
+		t.Fatalf("Expected %d exchanges, got %+v", len(expected), response.Exchanges)
+	}
+	for _, status := range response.Exchanges {
+		if expected[status.Exchange] != status.Status {
+			t.Errorf("Expected %s to be %s, got %s", status.Exchange, expected[status.Exchange], status.Status)
+		}
+	}
+}
@@ -2,14 +2,23 @@ package websocket
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
-	"sort"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"orderbook/internal/aggregation"
+	"orderbook/internal/analytics"
+	"orderbook/internal/arbitrage"
+	"orderbook/internal/consolidated"
+	"orderbook/internal/exchange"
+	"orderbook/internal/fx"
+	"orderbook/internal/history"
 	"orderbook/internal/orderbook"
+	"orderbook/internal/symbols"
 	"orderbook/internal/types"
 
 	"github.com/gorilla/websocket"
@@ -19,80 +28,281 @@ import (
 type MessageType string
 
 const (
-	MessageTypeOrderbook MessageType = "orderbook"
-	MessageTypeStats     MessageType = "stats"
+	MessageTypeOrderbook   MessageType = "orderbook"
+	MessageTypeStats       MessageType = "stats"
+	MessageTypeImpact      MessageType = "impact"
+	MessageTypeError       MessageType = "error"
+	MessageTypeArbitrage   MessageType = "arbitrage"
+	MessageTypeDerivatives MessageType = "derivatives"
+	MessageTypeTickLevels  MessageType = "tick_levels"
+	MessageTypeSymbol      MessageType = "symbol"
+	MessageTypeSymbols     MessageType = "symbols"
+	MessageTypeHistory     MessageType = "history"
+	MessageTypeTrades      MessageType = "trades"
+	MessageTypeBookDelta   MessageType = "orderbook_delta"
 )
 
 // ClientMessage represents messages sent from client to server
 type ClientMessage struct {
-	Type   string  `json:"type"`
-	Tick   float64 `json:"tick,omitempty"`
-	Symbol string  `json:"symbol,omitempty"`
+	Type      string   `json:"type"`
+	ID        string   `json:"id,omitempty"` // Echoed back in replies to request-style messages
+	Tick      float64  `json:"tick,omitempty"`
+	Symbol    string   `json:"symbol,omitempty"` // Symbol a request applies to, the client's own if it follows one
+	Exchange  string   `json:"exchange,omitempty"`
+	Side      string   `json:"side,omitempty"` // "buy" or "sell" (calc_impact)
+	Size      float64  `json:"size,omitempty"`
+	Unit      string   `json:"unit,omitempty"`      // "base" or "quote" (calc_impact), defaults to base
+	Quote     string   `json:"quote,omitempty"`     // Reference currency (set_quote)
+	Exchanges []string `json:"exchanges,omitempty"` // subscribe/unsubscribe, "*" for every exchange
+	Symbols   []string `json:"symbols,omitempty"`   // subscribe/unsubscribe, "*" for every symbol
+	Channels  []string `json:"channels,omitempty"`  // subscribe/unsubscribe: "book", "stats", "trades"
+	Depth     int      `json:"depth,omitempty"`     // Book levels per side (subscribe)
+	Interval  int      `json:"interval,omitempty"`  // Milliseconds between book and stats updates (subscribe)
+	Mode      string   `json:"mode,omitempty"`      // "snapshot" or "delta" (subscribe)
 }
 
 type OrderbookMessage struct {
 	Type      MessageType  `json:"type"`
+	Symbol    string       `json:"symbol,omitempty"`
 	Exchange  string       `json:"exchange"`
+	Seq       int64        `json:"seq,omitempty"` // Delta mode only, see BookDeltaMessage
 	Bids      []PriceLevel `json:"bids"`
 	Asks      []PriceLevel `json:"asks"`
 	Timestamp int64        `json:"timestamp"`
 }
 
 type StatsMessage struct {
-	Type                 MessageType `json:"type"`
-	Exchange             string      `json:"exchange"`
-	BestBid              string      `json:"bestBid"`
-	BestAsk              string      `json:"bestAsk"`
-	MidPrice             string      `json:"midPrice"`
-	Spread               string      `json:"spread"`
-	BidLiquidity05Pct    string      `json:"bidLiquidity05Pct"`
-	AskLiquidity05Pct    string      `json:"askLiquidity05Pct"`
-	DeltaLiquidity05Pct  string      `json:"deltaLiquidity05Pct"`
-	BidLiquidity2Pct     string      `json:"bidLiquidity2Pct"`
-	AskLiquidity2Pct     string      `json:"askLiquidity2Pct"`
-	DeltaLiquidity2Pct   string      `json:"deltaLiquidity2Pct"`
-	BidLiquidity10Pct    string      `json:"bidLiquidity10Pct"`
-	AskLiquidity10Pct    string      `json:"askLiquidity10Pct"`
-	DeltaLiquidity10Pct  string      `json:"deltaLiquidity10Pct"`
-	TotalBidsQty         string      `json:"totalBidsQty"`
-	TotalAsksQty         string      `json:"totalAsksQty"`
-	TotalDelta           string      `json:"totalDelta"`
-	Timestamp            int64       `json:"timestamp"`
+	Type               MessageType       `json:"type"`
+	Symbol             string            `json:"symbol,omitempty"` // Left out of history samples
+	Exchange           string            `json:"exchange"`
+	BestBid            string            `json:"bestBid"`
+	BestAsk            string            `json:"bestAsk"`
+	MidPrice           string            `json:"midPrice"`
+	Spread             string            `json:"spread"`
+	Depth              []DepthBand       `json:"depth"` // Narrowest band first
+	TotalBidsQty       string            `json:"totalBidsQty"`
+	TotalAsksQty       string            `json:"totalAsksQty"`
+	TotalDelta         string            `json:"totalDelta"`
+	TotalBidsNotional  string            `json:"totalBidsNotional"`
+	TotalAsksNotional  string            `json:"totalAsksNotional"`
+	TotalNotionalDelta string            `json:"totalNotionalDelta"`
+	Microprice         string            `json:"microprice"`
+	WeightedMid        string            `json:"weightedMid"`
+	TopBidsQty         string            `json:"topBidsQty"`
+	TopAsksQty         string            `json:"topAsksQty"`
+	TopImbalance       string            `json:"topImbalance"`              // -1 (all asks) to 1 (all bids) over the top 10 levels
+	OrderFlowImbalance string            `json:"orderFlowImbalance"`        // Base units over the last 10s
+	Quote              string            `json:"quote,omitempty"`           // Quote asset the venue trades in
+	ReferenceQuote     string            `json:"referenceQuote,omitempty"`  // Quote the prices are shown in
+	ConversionRate     string            `json:"conversionRate,omitempty"`  // ReferenceQuote per unit of Quote; omitted if unconverted
+	ConversionRates    map[string]string `json:"conversionRates,omitempty"` // Per venue quote, consolidated stats only
+	Timestamp          int64             `json:"timestamp"`
+}
+
+// DepthBand is the liquidity within one distance from the mid price
+type DepthBand struct {
+	Band          string  `json:"band"` // e.g. "25bps" or "2%"
+	Bps           float64 `json:"bps"`
+	BidQty        string  `json:"bidQty"`
+	AskQty        string  `json:"askQty"`
+	DeltaQty      string  `json:"deltaQty"`
+	BidNotional   string  `json:"bidNotional"`
+	AskNotional   string  `json:"askNotional"`
+	DeltaNotional string  `json:"deltaNotional"`
+}
+
+// TradesMessage carries the trades an exchange reported since the previous one, oldest first
+type TradesMessage struct {
+	Type      MessageType `json:"type"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Exchange  string      `json:"exchange"`
+	Trades    []Trade     `json:"trades"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// Trade is one executed trade, priced in the venue's own quote
+type Trade struct {
+	ID       string `json:"id"`
+	Price    string `json:"price"`
+	Quantity string `json:"quantity"` // Base asset units
+	Side     string `json:"side"`     // Aggressor side, "buy" or "sell"
+	Time     int64  `json:"time"`
+}
+
+// HistoryMessage carries the recorded stats of one exchange, oldest first. It backfills clients
+// on connect and is the /api/history response when no metric is selected.
+type HistoryMessage struct {
+	Type      MessageType    `json:"type"`
+	Symbol    string         `json:"symbol,omitempty"`
+	Exchange  string         `json:"exchange"`
+	Samples   []StatsMessage `json:"samples"` // Each stamped with the time it was recorded
+	Timestamp int64          `json:"timestamp"`
+}
+
+// HistorySeries is a single metric of one exchange over time, served by /api/history
+type HistorySeries struct {
+	Symbol   string         `json:"symbol,omitempty"`
+	Exchange string         `json:"exchange"`
+	Metric   string         `json:"metric"`
+	Band     string         `json:"band,omitempty"` // Depth metrics only
+	Points   []HistoryPoint `json:"points"`
+}
+
+// HistoryPoint is one value of a HistorySeries
+type HistoryPoint struct {
+	Time  int64  `json:"t"` // Unix milliseconds
+	Value string `json:"v"`
+}
+
+// ImpactMessage is the reply to a calc_impact request
+type ImpactMessage struct {
+	Type           MessageType `json:"type"`
+	ID             string      `json:"id,omitempty"`
+	Symbol         string      `json:"symbol,omitempty"`
+	Exchange       string      `json:"exchange"`
+	Side           string      `json:"side"`
+	Unit           string      `json:"unit"`
+	RequestedSize  string      `json:"requestedSize"`
+	FilledBase     string      `json:"filledBase"`
+	FilledQuote    string      `json:"filledQuote"`
+	UnfilledSize   string      `json:"unfilledSize"`
+	MidPrice       string      `json:"midPrice"`
+	AvgPrice       string      `json:"avgPrice"`
+	WorstPrice     string      `json:"worstPrice"`
+	SlippageBps    string      `json:"slippageBps"`
+	LevelsConsumed int         `json:"levelsConsumed"`
+	Timestamp      int64       `json:"timestamp"`
+}
+
+// ArbitrageMessage announces a cross-venue opportunity opening or closing
+type ArbitrageMessage struct {
+	Type         MessageType `json:"type"`
+	Symbol       string      `json:"symbol,omitempty"`
+	Event        string      `json:"event"` // "opened" or "closed"
+	BuyExchange  string      `json:"buyExchange"`
+	SellExchange string      `json:"sellExchange"`
+	BuyPrice     string      `json:"buyPrice"`
+	SellPrice    string      `json:"sellPrice"`
+	Size         string      `json:"size"`
+	GrossBps     string      `json:"grossBps"`
+	NetBps       string      `json:"netBps"`
+	MaxNetBps    string      `json:"maxNetBps"`
+	MaxSize      string      `json:"maxSize"`
+	OpenedAt     int64       `json:"openedAt"`
+	DurationMs   int64       `json:"durationMs"`
+	Timestamp    int64       `json:"timestamp"`
+}
+
+// DerivativesMessage carries funding, mark/index price and open interest for a perpetual exchange
+type DerivativesMessage struct {
+	Type            MessageType `json:"type"`
+	Symbol          string      `json:"symbol,omitempty"`
+	Exchange        string      `json:"exchange"`
+	FundingRate     string      `json:"fundingRate"` // Per funding interval, as a fraction
+	NextFundingTime int64       `json:"nextFundingTime"`
+	MarkPrice       string      `json:"markPrice"`
+	IndexPrice      string      `json:"indexPrice"`
+	OpenInterest    string      `json:"openInterest"` // Base asset units
+	UpdatedAt       int64       `json:"updatedAt"`    // When the venue was last polled
+	Timestamp       int64       `json:"timestamp"`
+}
+
+// TickLevelsMessage lists the aggregation ticks a client may select with set_tick for a symbol
+type TickLevelsMessage struct {
+	Type      MessageType `json:"type"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Levels    []float64   `json:"levels"`
+	Current   float64     `json:"current"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// SymbolMessage reports which market each venue streams for a symbol, and why the others are
+// not streaming it. Clients receive it for the symbols they follow.
+type SymbolMessage struct {
+	Type        MessageType       `json:"type"`
+	Symbol      string            `json:"symbol"`
+	Markets     map[string]string `json:"markets"`               // Exchange -> venue symbol
+	Unsupported map[string]string `json:"unsupported,omitempty"` // Exchange -> reason
+	Timestamp   int64             `json:"timestamp"`
+}
+
+// SymbolsMessage lists the monitored symbols, the default first. Every client receives it on
+// connect and whenever a symbol is added or removed.
+type SymbolsMessage struct {
+	Type      MessageType `json:"type"`
+	Symbols   []string    `json:"symbols"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// SymbolRequest asks the runtime to start or stop monitoring a symbol
+type SymbolRequest struct {
+	Symbol string // As the client sent it, e.g. "ETHUSDT"
+	Remove bool
+}
+
+// ErrorMessage reports a failed client request back to that client only
+type ErrorMessage struct {
+	Type      MessageType `json:"type"`
+	ID        string      `json:"id,omitempty"`
+	Request   string      `json:"request"`
+	Error     string      `json:"error"`
+	Timestamp int64       `json:"timestamp"`
 }
 
 type PriceLevel struct {
-	Price      string `json:"price"`
-	Quantity   string `json:"quantity"`
-	Cumulative string `json:"cumulative"`
+	Price      string            `json:"price"`
+	Quantity   string            `json:"quantity"`
+	Cumulative string            `json:"cumulative,omitempty"` // Omitted in deltas
+	Venues     map[string]string `json:"venues,omitempty"`     // Per-venue quantity, consolidated book only
 }
 
-// Client wraps a WebSocket connection with a write mutex
+// Client wraps a WebSocket connection with a write mutex and what the client subscribed to
 type Client struct {
 	conn      *websocket.Conn
 	writeMux  sync.Mutex
+	encoding  Encoding // Negotiated on connect
+	sub       subscription
+	subMux    sync.Mutex
+	sending   atomic.Bool                  // A push is still being written; the next one is skipped
+	positions map[streamKey]streamPosition // Delta streams the client holds (guarded by subMux)
 }
 
 type Server struct {
-	orderbooks   map[string]*orderbook.OrderBook
-	port         string
-	upgrader     websocket.Upgrader
-	clients      map[*Client]bool
-	clientsMux   sync.RWMutex
-	broadcast    chan interface{}
-	aggregator   *aggregation.Aggregator
-	tickMux      sync.RWMutex
-	symbolChange chan string
-}
-
-func NewServer(orderbooks map[string]*orderbook.OrderBook, port string, symbolChange chan string) *Server {
+	port           string
+	upgrader       websocket.Upgrader
+	clients        map[*Client]bool
+	clientsMux     sync.RWMutex
+	broadcast      chan outbound
+	symbolRequests chan<- SymbolRequest // Nil if symbols cannot be added or removed, e.g. during replay
+	feeds          map[string]*Feed     // By canonical symbol (guarded by feedsMux)
+	feedOrder      []string             // Symbols in the order they were added; the first is the default (guarded by feedsMux)
+	feedsMux       sync.RWMutex
+	symbolLimit    int    // Most symbols monitored at once, unlimited if zero
+	refQuote       string // Quote new clients see stats and the consolidated book in (guarded by quoteMux)
+	quoteMux       sync.RWMutex
+	backfill       time.Duration             // History sent to clients on connect, none if zero
+	streams        map[streamKey]*bookStream // Delta-mode book streams (push loop only)
+}
+
+// outbound is a message for every client, or only for those following symbol if it is set
+type outbound struct {
+	symbol string
+	msg    interface{}
+}
+
+// NewServer creates a server without symbols; feeds are added with AddFeed. Symbol requests
+// from clients are sent to symbolRequests, and refused if it is nil.
+func NewServer(port string, symbolRequests chan<- SymbolRequest) *Server {
 	return &Server{
-		orderbooks:   orderbooks,
-		port:         port,
-		clients:      make(map[*Client]bool),
-		broadcast:    make(chan interface{}, 100),
-		aggregator:   aggregation.New(types.Tick1), // Default to 1.0 tick
-		symbolChange: symbolChange,
+		port:           port,
+		clients:        make(map[*Client]bool),
+		broadcast:      make(chan outbound, 100),
+		symbolRequests: symbolRequests,
+		feeds:          make(map[string]*Feed),
+		refQuote:       fx.USD,
+		streams:        make(map[streamKey]*bookStream),
 		upgrader: websocket.Upgrader{
+			Subprotocols: subprotocols,
 			CheckOrigin: func(r *http.Request) bool {
 				return true
 			},
@@ -103,6 +313,12 @@ func NewServer(orderbooks map[string]*orderbook.OrderBook, port string, symbolCh
 func (s *Server) Start() error {
 	http.HandleFunc("/ws", s.handleWebSocket)
 	http.HandleFunc("/health", s.handleHealth)
+	http.HandleFunc("/api/symbols", s.handleSymbols)
+	http.HandleFunc("/api/exchanges", s.handleExchanges)
+	http.HandleFunc("/api/book", s.handleBook)
+	http.HandleFunc("/api/stats", s.handleStats)
+	http.HandleFunc("/api/impact", s.handleImpact)
+	http.HandleFunc("/api/history", s.handleHistory)
 
 	go s.broadcastMessages()
 	go s.startDataPush()
@@ -129,13 +345,17 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := &Client{conn: conn}
+	client := &Client{conn: conn, encoding: negotiatedEncoding(conn), sub: newSubscription(s.defaultSymbol(), s.referenceQuote())}
 
 	s.clientsMux.Lock()
 	s.clients[client] = true
 	s.clientsMux.Unlock()
 
-	log.Printf("New WebSocket client connected from %s", r.RemoteAddr)
+	log.Printf("New WebSocket client connected from %s (%s)", r.RemoteAddr, client.encoding)
+
+	s.sendToClient(client, s.symbolsMessage(time.Now().UnixMilli()))
+	s.sendSymbolState(client, true)
+	s.sendBackfill(client)
 
 	// Start ping/pong keepalive
 	done := make(chan struct{})
@@ -160,18 +380,18 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 
 	for {
-		_, message, err := conn.ReadMessage()
+		messageType, message, err := conn.ReadMessage()
 		if err != nil {
 			break
 		}
 
 		var clientMsg ClientMessage
-		if err := json.Unmarshal(message, &clientMsg); err != nil {
+		if err := unmarshalClientMessage(messageType, message, &clientMsg); err != nil {
 			log.Printf("Error parsing client message: %v", err)
 			continue
 		}
 
-		s.handleClientMessage(clientMsg)
+		s.handleClientMessage(client, clientMsg)
 	}
 }
 
@@ -197,49 +417,298 @@ func (s *Server) keepalive(client *Client, done chan struct{}) {
 	}
 }
 
-func (s *Server) handleClientMessage(msg ClientMessage) {
+func (s *Server) handleClientMessage(client *Client, msg ClientMessage) {
 	switch msg.Type {
 	case "set_tick":
-		s.setTickLevel(msg.Tick)
+		s.setTickLevel(client, msg)
+	case "subscribe", "unsubscribe":
+		s.handleSubscription(client, msg)
+	case "resync":
+		symbol, err := parseSymbol(msg.Symbol)
+		if err != nil {
+			s.sendError(client, msg, err.Error())
+			return
+		}
+		client.resync(symbol, msg.Exchange)
+	case "calc_impact":
+		s.handleCalcImpact(client, msg)
+	case "set_quote":
+		s.setQuote(client, msg)
 	case "change_symbol":
 		if msg.Symbol != "" {
-			log.Printf("Symbol change request: %s", msg.Symbol)
-			s.symbolChange <- msg.Symbol
+			s.changeSymbol(client, msg)
+		}
+	case "add_symbol", "remove_symbol":
+		if err := s.requestSymbol(msg.Symbol, msg.Type == "remove_symbol"); err != nil {
+			s.sendError(client, msg, err.Error())
 		}
 	default:
 		log.Printf("Unknown message type: %s", msg.Type)
 	}
 }
 
-func (s *Server) setTickLevel(tick float64) {
-	tickLevel := types.TickLevel(tick)
+// setTickLevel changes the aggregation tick of one client's books
+func (s *Server) setTickLevel(client *Client, msg ClientMessage) {
+	s.handleSubscription(client, ClientMessage{Type: "subscribe", ID: msg.ID, Tick: msg.Tick})
+}
 
-	// Validate tick level
-	validTick := false
-	for _, available := range types.AvailableTickLevels {
-		if available == tickLevel {
-			validTick = true
-			break
+// setQuote changes the reference quote of one client's stats and consolidated book
+func (s *Server) setQuote(client *Client, msg ClientMessage) {
+	if msg.Quote == "" {
+		s.sendError(client, msg, "quote is required")
+		return
+	}
+	s.handleSubscription(client, ClientMessage{Type: "subscribe", ID: msg.ID, Quote: msg.Quote})
+}
+
+// handleSubscription applies a subscribe or unsubscribe request and confirms the tick in use
+// for each symbol the client follows
+func (s *Server) handleSubscription(client *Client, msg ClientMessage) {
+	if msg.Type == "subscribe" && msg.Quote != "" && !s.knownQuote(msg.Quote) {
+		s.sendError(client, msg, fmt.Sprintf("no conversion rate for %q", msg.Quote))
+		return
+	}
+	ladders := s.ladders()
+
+	client.subMux.Lock()
+	var err error
+	if msg.Type == "unsubscribe" {
+		err = client.sub.unsubscribe(msg)
+	} else {
+		err = client.sub.subscribe(msg, ladders)
+	}
+	client.subMux.Unlock()
+
+	if err != nil {
+		s.sendError(client, msg, err.Error())
+		return
+	}
+	s.sendSymbolState(client, false)
+}
+
+// changeSymbol moves a single-symbol client to another symbol, starting it if it is not
+// monitored yet. Other clients and symbols are not affected.
+func (s *Server) changeSymbol(client *Client, msg ClientMessage) {
+	symbol, err := symbols.Parse(msg.Symbol)
+	if err != nil {
+		s.sendError(client, msg, err.Error())
+		return
+	}
+	if s.feed(symbol.String()) == nil {
+		if err := s.requestSymbol(msg.Symbol, false); err != nil {
+			s.sendError(client, msg, err.Error())
+			return
+		}
+	}
+	log.Printf("Client changed symbol to %s", symbol)
+
+	client.subMux.Lock()
+	client.sub.symbols = map[string]bool{symbol.String(): true}
+	client.subMux.Unlock()
+
+	// A symbol still starting is announced once its feed is added
+	s.sendSymbolState(client, true)
+}
+
+// handleCalcImpact runs a market-impact calculation and replies to the requesting client only
+func (s *Server) handleCalcImpact(client *Client, msg ClientMessage) {
+	impact, err := s.calculateImpact(msg)
+	if err != nil {
+		s.sendError(client, msg, err.Error())
+		return
+	}
+	s.sendToClient(client, impact)
+}
+
+// calculateImpact runs a market-impact calculation against one venue's book of msg.Symbol, or
+// of the default symbol if none is named
+func (s *Server) calculateImpact(msg ClientMessage) (ImpactMessage, error) {
+	feed, err := s.lookupFeed(msg.Symbol)
+	if err != nil {
+		return ImpactMessage{}, err
+	}
+	ob, ok := feed.books.Get(msg.Exchange)
+	if !ok || !ob.IsInitialized() {
+		return ImpactMessage{}, fmt.Errorf("exchange %q is not available", msg.Exchange)
+	}
+
+	unit := analytics.SizeUnit(msg.Unit)
+	if unit == "" {
+		unit = analytics.UnitBase
+	}
+
+	result, err := analytics.CalculateImpact(ob, analytics.Side(msg.Side), decimal.NewFromFloat(msg.Size), unit)
+	if err != nil {
+		return ImpactMessage{}, err
+	}
+
+	return ImpactMessage{
+		Type:           MessageTypeImpact,
+		ID:             msg.ID,
+		Symbol:         feed.symbol,
+		Exchange:       msg.Exchange,
+		Side:           string(result.Side),
+		Unit:           string(result.Unit),
+		RequestedSize:  result.RequestedSize.String(),
+		FilledBase:     result.FilledBase.String(),
+		FilledQuote:    result.FilledQuote.String(),
+		UnfilledSize:   result.UnfilledSize.String(),
+		MidPrice:       result.MidPrice.String(),
+		AvgPrice:       result.AvgPrice.String(),
+		WorstPrice:     result.WorstPrice.String(),
+		SlippageBps:    result.SlippageBps.StringFixed(2),
+		LevelsConsumed: result.LevelsConsumed,
+		Timestamp:      time.Now().UnixMilli(),
+	}, nil
+}
+
+// sendBackfill sends a newly connected client the recent stats history of every exchange of
+// the symbols it follows
+func (s *Server) sendBackfill(client *Client) {
+	if s.backfill <= 0 {
+		return
+	}
+
+	client.subMux.Lock()
+	quote := client.sub.quote
+	client.subMux.Unlock()
+
+	now := time.Now()
+	for _, feed := range s.followedFeeds(client) {
+		for _, exchangeName := range feed.history.Exchanges() {
+			samples := feed.history.Range(exchangeName, now.Add(-s.backfill), time.Time{})
+			if len(samples) == 0 {
+				continue
+			}
+			s.sendToClient(client, newHistoryMessage(feed, exchangeName, samples, quote, now.UnixMilli()))
 		}
 	}
+}
+
+// sendSymbolState sends a client the tick ladder of every symbol it follows, preceded by the
+// symbol's markets if withMarkets is set
+func (s *Server) sendSymbolState(client *Client, withMarkets bool) {
+	for _, feed := range s.followedFeeds(client) {
+		s.sendFeedState(client, feed, withMarkets)
+	}
+}
+
+// sendError replies to a single client with an error for the given request
+func (s *Server) sendError(client *Client, msg ClientMessage, errMsg string) {
+	s.sendToClient(client, ErrorMessage{
+		Type:      MessageTypeError,
+		ID:        msg.ID,
+		Request:   msg.Type,
+		Error:     errMsg,
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
 
-	if !validTick {
-		log.Printf("Invalid tick level: %f, using default", tick)
+// sendToClient writes a message to a single client
+func (s *Server) sendToClient(client *Client, msg interface{}) {
+	data, err := client.encoding.marshal(msg)
+	if err != nil {
+		log.Printf("Error encoding message: %v", err)
 		return
 	}
 
-	s.tickMux.Lock()
-	s.aggregator.SetTickLevel(tickLevel)
-	s.tickMux.Unlock()
+	client.writeMux.Lock()
+	defer client.writeMux.Unlock()
+
+	client.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := client.conn.WriteMessage(client.encoding.messageType(), data); err != nil {
+		log.Printf("Error writing to client: %v", err)
+	}
+}
+
+// SetReferenceQuote sets the currency stats and the consolidated book are shown in for clients
+// connecting from now on; each client can change its own with set_quote. Any quote a running
+// venue trades in, or a dollar stablecoin, can be chosen.
+func (s *Server) SetReferenceQuote(quote string) error {
+	if !s.knownQuote(quote) {
+		return fmt.Errorf("no conversion rate for %q", quote)
+	}
+
+	s.quoteMux.Lock()
+	s.refQuote = quote
+	s.quoteMux.Unlock()
+
+	log.Printf("Default reference quote set to: %s", quote)
+	return nil
+}
+
+// knownQuote reports whether prices can be converted into quote
+func (s *Server) knownQuote(quote string) bool {
+	if fx.NewRates().Known(quote) {
+		return true
+	}
+	for _, feed := range s.feedList() {
+		if feed.rates.Known(quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHistoryBackfill sets how much recent stats history is sent to clients when they connect
+func (s *Server) SetHistoryBackfill(window time.Duration) {
+	s.backfill = window
+}
+
+// SetSymbolLimit sets how many symbols can be monitored at once; clients cannot add more
+func (s *Server) SetSymbolLimit(limit int) {
+	s.symbolLimit = limit
+}
 
-	log.Printf("Tick level changed to: %f", tick)
+// referenceQuote returns the quote new clients see stats and the consolidated book in
+func (s *Server) referenceQuote() string {
+	s.quoteMux.RLock()
+	defer s.quoteMux.RUnlock()
+	return s.refQuote
+}
+
+// ForwardArbitrageEvents sends the detector events of a symbol to the clients following it
+// until the channel closes
+func (s *Server) ForwardArbitrageEvents(symbol symbols.Symbol, events <-chan arbitrage.Event) {
+	go func() {
+		for event := range events {
+			opp := event.Opportunity
+			s.broadcast <- outbound{symbol: symbol.String(), msg: ArbitrageMessage{
+				Type:         MessageTypeArbitrage,
+				Symbol:       symbol.String(),
+				Event:        string(event.Type),
+				BuyExchange:  opp.BuyExchange,
+				SellExchange: opp.SellExchange,
+				BuyPrice:     opp.BuyPrice.String(),
+				SellPrice:    opp.SellPrice.String(),
+				Size:         opp.Size.String(),
+				GrossBps:     opp.GrossBps.StringFixed(2),
+				NetBps:       opp.NetBps.StringFixed(2),
+				MaxNetBps:    opp.MaxNetBps.StringFixed(2),
+				MaxSize:      opp.MaxSize.String(),
+				OpenedAt:     opp.OpenedAt.UnixMilli(),
+				DurationMs:   opp.Duration.Milliseconds(),
+				Timestamp:    time.Now().UnixMilli(),
+			}}
+		}
+	}()
 }
 
 func (s *Server) broadcastMessages() {
-	for msg := range s.broadcast {
+	for out := range s.broadcast {
+		shared := newFrame(out.msg)
+
 		s.clientsMux.RLock()
 		// Send to each client concurrently to prevent one slow/zombie client from blocking others
 		for client := range s.clients {
+			if out.symbol != "" && !client.follows(out.symbol) {
+				continue
+			}
+			prepared, err := shared.encode(client.encoding)
+			if err != nil {
+				log.Printf("Error encoding message: %v", err)
+				continue
+			}
 			go func(c *Client) {
 				// Use write mutex to prevent concurrent writes to same connection
 				c.writeMux.Lock()
@@ -247,7 +716,7 @@ func (s *Server) broadcastMessages() {
 
 				// Set write deadline to prevent blocking indefinitely
 				c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-				err := c.conn.WriteJSON(msg)
+				err := c.conn.WritePreparedMessage(prepared)
 				if err != nil {
 					log.Printf("Error writing to client: %v", err)
 					c.conn.Close()
@@ -261,83 +730,259 @@ func (s *Server) broadcastMessages() {
 	}
 }
 
+// feedPush is what one push sends of a feed
+type feedPush struct {
+	feed   *Feed
+	books  map[string]*orderbook.OrderBook // Initialized venues
+	trades map[string][]Trade
+}
+
+// startDataPush sends every client the book and stats it subscribed to once its interval has
+// passed, and the trades received since the previous push, for every symbol it follows
 func (s *Server) startDataPush() {
-	ticker := time.NewTicker(200 * time.Millisecond)
+	ticker := time.NewTicker(minUpdateInterval)
 	defer ticker.Stop()
 
 	lastLogTime := time.Now()
 
-	for range ticker.C {
+	for now := range ticker.C {
 		s.clientsMux.RLock()
-		clientCount := len(s.clients)
+		clients := make([]*Client, 0, len(s.clients))
+		for client := range s.clients {
+			clients = append(clients, client)
+		}
 		s.clientsMux.RUnlock()
 
 		// Log client count every 5 seconds for debugging
 		if time.Since(lastLogTime) > 5*time.Second {
-			log.Printf("📊 Broadcasting: %d clients connected", clientCount)
+			log.Printf("📊 Broadcasting: %d clients connected", len(clients))
 			lastLogTime = time.Now()
 		}
 
-		if clientCount == 0 {
+		feeds := s.feedList()
+		pushes := make([]feedPush, 0, len(feeds))
+		for _, feed := range feeds {
+			pushes = append(pushes, feedPush{feed: feed, trades: feed.takeTrades()})
+		}
+		if len(clients) == 0 {
 			continue
 		}
 
-		timestamp := time.Now().UnixMilli()
+		timestamp := now.UnixMilli()
+		streaming := false
+		for i := range pushes {
+			push := &pushes[i]
+			push.feed.updateRates()
+			push.books = push.feed.streamingBooks()
+			if len(push.books) > 0 {
+				streaming = true
+				s.syncTickLevels(push.feed, clients, timestamp)
+			}
+		}
 
-		for exchangeName, ob := range s.orderbooks {
-			if !ob.IsInitialized() {
+		// Clients with the same parameters share one encoded message
+		frames := make(frameCache)
+		for _, client := range clients {
+			// A client still writing its previous push skips this one rather than queueing behind
+			// it. It may miss trades, and gets a snapshot of any delta stream it fell behind on.
+			if client.sending.Load() {
 				continue
 			}
 
-			orderbookMsg := s.buildOrderbookMessage(exchangeName, ob, timestamp)
-			s.broadcast <- orderbookMsg
+			var batch []*websocket.PreparedMessage
+			client.subMux.Lock()
+			slot, due := client.sub.due(now)
+			if due && streaming {
+				client.sub.lastSlot = slot
+				for _, push := range pushes {
+					batch = s.appendUpdates(batch, frames, client, push.feed, push.books, slot, timestamp)
+				}
+			}
+			sub := client.sub
+			client.subMux.Unlock()
 
-			statsMsg := s.buildStatsMessage(exchangeName, ob, timestamp)
-			s.broadcast <- statsMsg
+			for _, push := range pushes {
+				symbol := push.feed.symbol
+				for exchangeName, exchangeTrades := range push.trades {
+					if !sub.wants(ChannelTrades, exchangeName, symbol) {
+						continue
+					}
+					batch = append(batch, frames.get(frameKey{kind: MessageTypeTrades, symbol: symbol, exchange: exchangeName}, client.encoding, func() interface{} {
+						return TradesMessage{Type: MessageTypeTrades, Symbol: symbol, Exchange: exchangeName, Trades: exchangeTrades, Timestamp: timestamp}
+					}))
+				}
+			}
+			s.sendFrames(client, batch)
 		}
+		s.pruneStreams(now)
 	}
 }
 
-func (s *Server) buildOrderbookMessage(exchange string, ob *orderbook.OrderBook, timestamp int64) OrderbookMessage {
-	bidsMap := ob.GetBids()
-	asksMap := ob.GetAsks()
+// appendUpdates adds the book, stats and derivatives messages of a feed a client subscribed to,
+// for every streaming venue and the merged view of all of them as a virtual exchange. Must be
+// called with the client's subMux held.
+func (s *Server) appendUpdates(batch []*websocket.PreparedMessage, frames frameCache, client *Client, feed *Feed, books map[string]*orderbook.OrderBook, slot time.Time, timestamp int64) []*websocket.PreparedMessage {
+	sub := &client.sub
+	symbol := feed.symbol
+	if !sub.follows(symbol) || len(books) == 0 {
+		return batch
+	}
+	tick := sub.tickFor(symbol, feed.ladder())
+	quote := sub.quote
+
+	for exchangeName, ob := range books {
+		if sub.wants(ChannelBook, exchangeName, symbol) {
+			key := streamKey{symbol: symbol, exchange: exchangeName, tick: tick, depth: sub.depth, interval: sub.interval}
+			batch = s.appendBook(batch, frames, client, sub, key, slot, timestamp, func() OrderbookMessage {
+				return s.buildOrderbookMessage(symbol, exchangeName, ob, tick, sub.depth, timestamp)
+			})
+		}
+		if sub.wants(ChannelStats, exchangeName, symbol) {
+			batch = append(batch, frames.get(frameKey{kind: MessageTypeStats, symbol: symbol, exchange: exchangeName, quote: quote}, client.encoding, func() interface{} {
+				return s.buildStatsMessage(feed, exchangeName, ob, quote, timestamp)
+			}))
+			if info, ok := feed.derivatives.Get(exchangeName); ok {
+				batch = append(batch, frames.get(frameKey{kind: MessageTypeDerivatives, symbol: symbol, exchange: exchangeName}, client.encoding, func() interface{} {
+					return newDerivativesMessage(symbol, info, timestamp)
+				}))
+			}
+		}
+	}
 
-	// Convert maps to slices of types.PriceLevel
-	bidLevels := make([]types.PriceLevel, 0, len(bidsMap))
-	for _, bid := range bidsMap {
-		bidLevels = append(bidLevels, bid)
+	if sub.wants(ChannelBook, consolidated.ExchangeName, symbol) {
+		key := streamKey{symbol: symbol, exchange: consolidated.ExchangeName, quote: quote, tick: tick, depth: sub.depth, interval: sub.interval}
+		batch = s.appendBook(batch, frames, client, sub, key, slot, timestamp, func() OrderbookMessage {
+			return s.buildConsolidatedOrderbookMessage(feed, quote, tick, sub.depth, timestamp)
+		})
+	}
+	if sub.wants(ChannelStats, consolidated.ExchangeName, symbol) {
+		batch = append(batch, frames.get(frameKey{kind: MessageTypeStats, symbol: symbol, exchange: consolidated.ExchangeName, quote: quote}, client.encoding, func() interface{} {
+			return s.buildConsolidatedStatsMessage(feed, quote, timestamp)
+		}))
 	}
+	return batch
+}
+
+// frameKey identifies a message by everything it is built from
+type frameKey struct {
+	kind     MessageType
+	symbol   string
+	exchange string
+	quote    string          // Stats and the consolidated book only
+	tick     types.TickLevel // Books only
+	depth    int             // Books only
+	interval time.Duration   // Delta-mode books only
+}
+
+// frameCache holds the messages of one push, each built once and encoded at most once per
+// encoding
+type frameCache map[frameKey]*frame
 
-	askLevels := make([]types.PriceLevel, 0, len(asksMap))
-	for _, ask := range asksMap {
-		askLevels = append(askLevels, ask)
+// get returns the message for key in the given encoding, building it on first use. It returns
+// nil if the message cannot be encoded.
+func (c frameCache) get(key frameKey, encoding Encoding, build func() interface{}) *websocket.PreparedMessage {
+	f, ok := c[key]
+	if !ok {
+		f = newFrame(build())
+		c[key] = f
 	}
 
-	// Apply aggregation
-	s.tickMux.RLock()
-	aggregatedBids := s.aggregator.AggregateBids(bidLevels)
-	aggregatedAsks := s.aggregator.AggregateAsks(askLevels)
-	s.tickMux.RUnlock()
+	prepared, err := f.encode(encoding)
+	if err != nil {
+		log.Printf("Error encoding %s message for %s %s: %v", key.kind, key.symbol, key.exchange, err)
+	}
+	return prepared
+}
 
-	// Sort bids by price descending (highest first)
-	sort.Slice(aggregatedBids, func(i, j int) bool {
-		return aggregatedBids[i].Price.GreaterThan(aggregatedBids[j].Price)
-	})
+// sendFrames writes one push to a client in the background
+func (s *Server) sendFrames(client *Client, frames []*websocket.PreparedMessage) {
+	if len(frames) == 0 || !client.sending.CompareAndSwap(false, true) {
+		return
+	}
 
-	// Sort asks by price ascending (lowest first)
-	sort.Slice(aggregatedAsks, func(i, j int) bool {
-		return aggregatedAsks[i].Price.LessThan(aggregatedAsks[j].Price)
-	})
+	go func() {
+		defer client.sending.Store(false)
 
-	// Limit depth to top 20 levels per side to reduce WebSocket message size
-	// Frontend only displays ~20 levels anyway, so sending more is wasteful
-	maxDepth := 20
-	if len(aggregatedBids) > maxDepth {
-		aggregatedBids = aggregatedBids[:maxDepth]
+		client.writeMux.Lock()
+		defer client.writeMux.Unlock()
+
+		for _, frame := range frames {
+			if frame == nil {
+				continue
+			}
+			client.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := client.conn.WritePreparedMessage(frame); err != nil {
+				log.Printf("Error writing to client: %v", err)
+				client.conn.Close()
+				s.clientsMux.Lock()
+				delete(s.clients, client)
+				s.clientsMux.Unlock()
+				return
+			}
+		}
+	}()
+}
+
+// recordHistory samples the stats of every venue of a feed and its consolidated book at the
+// history resolution, whether or not clients are connected, until the feed is removed. Venues
+// are recorded in their own quote and the consolidated book in USD; samples are converted into
+// each reader's quote when served.
+func (s *Server) recordHistory(feed *Feed) {
+	ticker := time.NewTicker(feed.history.Resolution())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			feed.updateRates()
+
+			books := feed.streamingBooks()
+			for exchangeName, ob := range books {
+				feed.history.Record(exchangeName, feed.venueQuote(exchangeName), ob.GetStats(), now)
+			}
+
+			if len(books) > 0 {
+				feed.history.Record(consolidated.ExchangeName, fx.USD, feed.consolidatedIn(fx.USD).Stats(), now)
+			}
+		case <-feed.done:
+			return
+		}
 	}
-	if len(aggregatedAsks) > maxDepth {
-		aggregatedAsks = aggregatedAsks[:maxDepth]
+}
+
+// syncTickLevels rebuilds the tick ladder of a feed, e.g. once its instruments are loaded, and
+// tells the clients following it when the ladder changes
+func (s *Server) syncTickLevels(feed *Feed, clients []*Client, timestamp int64) {
+	levels := feed.currentTickLevels()
+
+	feed.tickMux.Lock()
+	changed := !slices.Equal(levels, feed.tickLevels)
+	feed.tickLevels = levels
+	feed.tickMux.Unlock()
+
+	if !changed {
+		return
 	}
+	log.Printf("Tick levels of %s changed to %v", feed.symbol, levels)
+
+	for _, client := range clients {
+		client.subMux.Lock()
+		follows := client.sub.follows(feed.symbol)
+		tick := client.sub.tickFor(feed.symbol, levels)
+		client.subMux.Unlock()
+
+		if follows {
+			go s.sendToClient(client, newTickLevelsMessage(feed.symbol, levels, tick, timestamp))
+		}
+	}
+}
+
+// buildOrderbookMessage builds the top depth levels per side of a venue, aggregated to tick
+func (s *Server) buildOrderbookMessage(symbol, exchange string, ob *orderbook.OrderBook, tick types.TickLevel, depth int, timestamp int64) OrderbookMessage {
+	// Aggregate straight from the sorted book, stopping once depth buckets are filled
+	aggregator := aggregation.New(tick)
+	aggregatedBids := aggregator.AggregateSortedBids(ob.WalkBids, depth)
+	aggregatedAsks := aggregator.AggregateSortedAsks(ob.WalkAsks, depth)
 
 	// Convert bids to wire format with cumulative sums
 	bids := make([]PriceLevel, 0, len(aggregatedBids))
@@ -365,6 +1010,7 @@ func (s *Server) buildOrderbookMessage(exchange string, ob *orderbook.OrderBook,
 
 	return OrderbookMessage{
 		Type:      MessageTypeOrderbook,
+		Symbol:    symbol,
 		Exchange:  exchange,
 		Bids:      bids,
 		Asks:      asks,
@@ -372,28 +1018,217 @@ func (s *Server) buildOrderbookMessage(exchange string, ob *orderbook.OrderBook,
 	}
 }
 
-func (s *Server) buildStatsMessage(exchange string, ob *orderbook.OrderBook, timestamp int64) StatsMessage {
+// buildConsolidatedOrderbookMessage builds the cross-venue ladder of a feed in a reference
+// quote with per-level venue breakdown
+func (s *Server) buildConsolidatedOrderbookMessage(feed *Feed, quote string, tick types.TickLevel, depth int, timestamp int64) OrderbookMessage {
+	ladderBids, ladderAsks := feed.consolidatedIn(quote).Ladder(aggregation.New(tick), depth)
+
+	return OrderbookMessage{
+		Type:      MessageTypeOrderbook,
+		Symbol:    feed.symbol,
+		Exchange:  consolidated.ExchangeName,
+		Bids:      consolidatedLevelsToWire(ladderBids),
+		Asks:      consolidatedLevelsToWire(ladderAsks),
+		Timestamp: timestamp,
+	}
+}
+
+// consolidatedLevelsToWire converts consolidated levels to wire format with cumulative sums
+func consolidatedLevelsToWire(levels []consolidated.Level) []PriceLevel {
+	wire := make([]PriceLevel, 0, len(levels))
+	cumulative := decimal.Zero
+	for _, level := range levels {
+		cumulative = cumulative.Add(level.Quantity)
+
+		venues := make(map[string]string, len(level.Venues))
+		for _, venue := range level.Venues {
+			venues[venue.Exchange] = venue.Quantity.String()
+		}
+
+		wire = append(wire, PriceLevel{
+			Price:      level.Price.String(),
+			Quantity:   level.Quantity.String(),
+			Cumulative: cumulative.String(),
+			Venues:     venues,
+		})
+	}
+	return wire
+}
+
+// quoteInfo describes the currency a venue's stats are shown in
+type quoteInfo struct {
+	quote    string          // Quote asset the venue trades in, empty if unknown
+	refQuote string          // Quote the prices are shown in
+	rate     decimal.Decimal // Applied conversion rate, zero if unconverted
+}
+
+// venueStats returns a venue's stats with prices in refQuote when a rate is known
+func (s *Server) venueStats(feed *Feed, exchange string, ob *orderbook.OrderBook, refQuote string) (types.Stats, quoteInfo) {
 	stats := ob.GetStats()
 
+	feed.marketsMux.RLock()
+	market, known := feed.venueQuotes[exchange]
+	feed.marketsMux.RUnlock()
+
+	if !known {
+		return stats, quoteInfo{}
+	}
+
+	rate, ok := feed.rates.Rate(market.Quote, refQuote)
+	if !ok {
+		return stats, quoteInfo{quote: market.Quote, refQuote: market.Quote}
+	}
+	return stats.ConvertQuote(rate), quoteInfo{quote: market.Quote, refQuote: refQuote, rate: rate}
+}
+
+// buildStatsMessage builds a venue's stats with prices in refQuote when a rate is known
+func (s *Server) buildStatsMessage(feed *Feed, exchange string, ob *orderbook.OrderBook, refQuote string, timestamp int64) StatsMessage {
+	stats, info := s.venueStats(feed, exchange, ob, refQuote)
+
+	msg := newStatsMessage(exchange, stats, timestamp)
+	msg.Symbol = feed.symbol
+	msg.Quote = info.quote
+	msg.ReferenceQuote = info.refQuote
+	if !info.rate.IsZero() {
+		msg.ConversionRate = info.rate.String()
+	}
+	return msg
+}
+
+// buildConsolidatedStatsMessage builds the merged stats of a feed in refQuote, listing the rate
+// applied to each venue quote
+func (s *Server) buildConsolidatedStatsMessage(feed *Feed, refQuote string, timestamp int64) StatsMessage {
+	msg := newStatsMessage(consolidated.ExchangeName, feed.consolidatedIn(refQuote).Stats(), timestamp)
+	msg.Symbol = feed.symbol
+
+	feed.marketsMux.RLock()
+	quotes := make(map[string]bool, len(feed.venueQuotes))
+	for _, market := range feed.venueQuotes {
+		quotes[market.Quote] = true
+	}
+	feed.marketsMux.RUnlock()
+
+	if len(quotes) == 0 {
+		return msg
+	}
+
+	msg.Quote = refQuote
+	msg.ReferenceQuote = refQuote
+	msg.ConversionRates = make(map[string]string, len(quotes))
+	for quote := range quotes {
+		if rate, ok := feed.rates.Rate(quote, refQuote); ok {
+			msg.ConversionRates[quote] = rate.String()
+		}
+	}
+	return msg
+}
+
+// newStatsMessage converts orderbook statistics to wire format
+func newStatsMessage(exchange string, stats types.Stats, timestamp int64) StatsMessage {
 	return StatsMessage{
-		Type:                 MessageTypeStats,
-		Exchange:             exchange,
-		BestBid:              stats.BestBid.String(),
-		BestAsk:              stats.BestAsk.String(),
-		MidPrice:             stats.BestBid.Add(stats.BestAsk).Div(decimal.NewFromInt(2)).String(),
-		Spread:               stats.Spread.String(),
-		BidLiquidity05Pct:    stats.BidLiquidity05Pct.String(),
-		AskLiquidity05Pct:    stats.AskLiquidity05Pct.String(),
-		DeltaLiquidity05Pct:  stats.DeltaLiquidity05Pct.String(),
-		BidLiquidity2Pct:     stats.BidLiquidity2Pct.String(),
-		AskLiquidity2Pct:     stats.AskLiquidity2Pct.String(),
-		DeltaLiquidity2Pct:   stats.DeltaLiquidity2Pct.String(),
-		BidLiquidity10Pct:    stats.BidLiquidity10Pct.String(),
-		AskLiquidity10Pct:    stats.AskLiquidity10Pct.String(),
-		DeltaLiquidity10Pct:  stats.DeltaLiquidity10Pct.String(),
-		TotalBidsQty:         stats.TotalBidsQty.String(),
-		TotalAsksQty:         stats.TotalAsksQty.String(),
-		TotalDelta:           stats.TotalDelta.String(),
-		Timestamp:            timestamp,
+		Type:               MessageTypeStats,
+		Exchange:           exchange,
+		BestBid:            stats.BestBid.String(),
+		BestAsk:            stats.BestAsk.String(),
+		MidPrice:           stats.BestBid.Add(stats.BestAsk).Div(decimal.NewFromInt(2)).String(),
+		Spread:             stats.Spread.String(),
+		Depth:              newDepthBands(stats.Depth),
+		TotalBidsQty:       stats.TotalBidsQty.String(),
+		TotalAsksQty:       stats.TotalAsksQty.String(),
+		TotalDelta:         stats.TotalDelta.String(),
+		TotalBidsNotional:  stats.TotalBidsNotional.String(),
+		TotalAsksNotional:  stats.TotalAsksNotional.String(),
+		TotalNotionalDelta: stats.TotalNotionalDelta.String(),
+		Microprice:         stats.Microprice.String(),
+		WeightedMid:        stats.WeightedMid.String(),
+		TopBidsQty:         stats.TopBidsQty.String(),
+		TopAsksQty:         stats.TopAsksQty.String(),
+		TopImbalance:       stats.TopImbalance.StringFixed(4),
+		OrderFlowImbalance: stats.OrderFlowImbalance.String(),
+		Timestamp:          timestamp,
+	}
+}
+
+// sampleStats returns a recorded sample's stats with prices in refQuote when a rate is known
+func sampleStats(feed *Feed, sample history.Sample, refQuote string) (types.Stats, quoteInfo) {
+	if sample.Quote == "" {
+		return sample.Stats, quoteInfo{}
+	}
+	rate, ok := feed.rates.Rate(sample.Quote, refQuote)
+	if !ok {
+		return sample.Stats, quoteInfo{quote: sample.Quote, refQuote: sample.Quote}
+	}
+	return sample.Stats.ConvertQuote(rate), quoteInfo{quote: sample.Quote, refQuote: refQuote, rate: rate}
+}
+
+// newHistoryMessage converts recorded samples to wire format with prices in refQuote when a
+// rate is known
+func newHistoryMessage(feed *Feed, exchange string, samples []history.Sample, refQuote string, timestamp int64) HistoryMessage {
+	wire := make([]StatsMessage, 0, len(samples))
+	for _, sample := range samples {
+		stats, info := sampleStats(feed, sample, refQuote)
+		msg := newStatsMessage(exchange, stats, sample.Time.UnixMilli())
+		msg.Quote = info.quote
+		msg.ReferenceQuote = info.refQuote
+		if !info.rate.IsZero() {
+			msg.ConversionRate = info.rate.String()
+		}
+		wire = append(wire, msg)
+	}
+	return HistoryMessage{
+		Type:      MessageTypeHistory,
+		Symbol:    feed.symbol,
+		Exchange:  exchange,
+		Samples:   wire,
+		Timestamp: timestamp,
+	}
+}
+
+// newDepthBands converts depth band liquidity to wire format
+func newDepthBands(depth []types.DepthLiquidity) []DepthBand {
+	bands := make([]DepthBand, 0, len(depth))
+	for _, d := range depth {
+		bands = append(bands, DepthBand{
+			Band:          d.Band.String(),
+			Bps:           float64(d.Band),
+			BidQty:        d.BidQty.String(),
+			AskQty:        d.AskQty.String(),
+			DeltaQty:      d.DeltaQty.String(),
+			BidNotional:   d.BidNotional.String(),
+			AskNotional:   d.AskNotional.String(),
+			DeltaNotional: d.DeltaNotional.String(),
+		})
+	}
+	return bands
+}
+
+// newDerivativesMessage converts perpetual contract data to wire format
+func newDerivativesMessage(symbol string, info exchange.DerivativesInfo, timestamp int64) DerivativesMessage {
+	return DerivativesMessage{
+		Type:            MessageTypeDerivatives,
+		Symbol:          symbol,
+		Exchange:        string(info.Exchange),
+		FundingRate:     info.FundingRate,
+		NextFundingTime: info.NextFundingTime.UnixMilli(),
+		MarkPrice:       info.MarkPrice,
+		IndexPrice:      info.IndexPrice,
+		OpenInterest:    info.OpenInterest,
+		UpdatedAt:       info.Timestamp.UnixMilli(),
+		Timestamp:       timestamp,
+	}
+}
+
+// newTickLevelsMessage converts the tick ladder of a symbol to wire format
+func newTickLevelsMessage(symbol string, levels []types.TickLevel, current types.TickLevel, timestamp int64) TickLevelsMessage {
+	wire := make([]float64, len(levels))
+	for i, level := range levels {
+		wire[i] = float64(level)
+	}
+	return TickLevelsMessage{
+		Type:      MessageTypeTickLevels,
+		Symbol:    symbol,
+		Levels:    wire,
+		Current:   float64(current),
+		Timestamp: timestamp,
 	}
 }
@@ -0,0 +1,84 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"orderbook/internal/consolidated"
+	"orderbook/internal/fx"
+	"orderbook/internal/symbols"
+	"orderbook/internal/types"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestReferenceQuoteIsPerClient(t *testing.T) {
+	s := newTestServer(t)
+	feed := s.feed("BTC/USDT")
+	feed.SetMarkets([]symbols.Market{{Exchange: "binancef", Symbol: symbols.MustParse("BTCUSDT"), Native: "BTCUSDT"}}, nil)
+	feed.updateRates()
+
+	dollars := &Client{encoding: EncodingJSON, sub: newSubscription("BTC/USDT", fx.USD)}
+	tethers := &Client{encoding: EncodingJSON, sub: newSubscription("BTC/USDT", fx.USD)}
+	if err := tethers.sub.subscribe(ClientMessage{Quote: "USDT"}, nil); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	frames := make(frameCache)
+	slot := time.Unix(1_700_000_000, 0)
+	for _, client := range []*Client{dollars, tethers} {
+		s.appendUpdates(nil, frames, client, feed, feed.streamingBooks(), slot, 0)
+	}
+
+	for _, quote := range []string{fx.USD, "USDT"} {
+		f, ok := frames[frameKey{kind: MessageTypeStats, symbol: "BTC/USDT", exchange: consolidated.ExchangeName, quote: quote}]
+		if !ok {
+			t.Fatalf("Expected consolidated stats in %s", quote)
+		}
+		if msg := f.msg.(StatsMessage); msg.ReferenceQuote != quote {
+			t.Errorf("Expected stats in %s, got %s", quote, msg.ReferenceQuote)
+		}
+	}
+	if s.referenceQuote() != fx.USD {
+		t.Errorf("Expected the default quote to stay %s, got %s", fx.USD, s.referenceQuote())
+	}
+}
+
+func TestHistoryConvertedWhenServed(t *testing.T) {
+	s := newTestServer(t)
+	feed := s.feed("BTC/USDT")
+	feed.rates.Update([]fx.Observation{
+		{Quote: fx.USD, Mid: decimal.NewFromInt(100), Spot: true},
+		{Quote: "USDT", Mid: decimal.NewFromInt(200), Spot: true},
+	}, time.Now())
+
+	stats := types.Stats{BestBid: decimal.NewFromInt(100), BestAsk: decimal.NewFromInt(102)}
+	feed.history.Record("binancef", "USDT", stats, time.Now())
+	samples := feed.history.Range("binancef", time.Time{}, time.Time{})
+
+	tests := []struct {
+		quote   string
+		bestBid string
+	}{
+		{"USDT", "100"},
+		{fx.USD, "50"},
+		{"JPY", "100"}, // No rate, served as recorded
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quote, func(t *testing.T) {
+			msg := newHistoryMessage(feed, "binancef", samples, tt.quote, 0)
+			if len(msg.Samples) != 1 {
+				t.Fatalf("Expected 1 sample, got %d", len(msg.Samples))
+			}
+			sample := msg.Samples[0]
+			if sample.BestBid != tt.bestBid || sample.Quote != "USDT" {
+				t.Errorf("Expected best bid %s in USDT, got %s in %s", tt.bestBid, sample.BestBid, sample.Quote)
+			}
+		})
+	}
+
+	if recorded := feed.history.Range("binancef", time.Time{}, time.Time{}); !recorded[0].Stats.BestBid.Equal(stats.BestBid) {
+		t.Errorf("Expected the recorded sample to stay native, got best bid %s", recorded[0].Stats.BestBid)
+	}
+}
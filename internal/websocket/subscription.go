@@ -0,0 +1,275 @@
+package websocket
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"orderbook/internal/symbols"
+	"orderbook/internal/types"
+)
+
+// Channel is a per-exchange stream a client can subscribe to
+type Channel string
+
+const (
+	ChannelBook   Channel = "book"
+	ChannelStats  Channel = "stats" // Also carries derivatives messages
+	ChannelTrades Channel = "trades"
+)
+
+// Mode is how books are streamed to a client
+type Mode string
+
+const (
+	ModeSnapshot Mode = "snapshot" // A full book on every update
+	ModeDelta    Mode = "delta"    // A full book with a sequence number, then only the changed levels
+)
+
+// Wildcard subscribes to every exchange or symbol
+const Wildcard = "*"
+
+const (
+	defaultTick           = types.Tick1 // Moved inside each symbol's ladder
+	defaultBookDepth      = 20          // The frontend displays about 20 levels per side
+	maxBookDepth          = 200
+	defaultUpdateInterval = 200 * time.Millisecond
+	minUpdateInterval     = 100 * time.Millisecond // Also the period of the push loop
+	maxPendingTrades      = 1000                   // Per exchange between pushes; the oldest are dropped
+)
+
+// subscription is what a single client receives and at which parameters
+type subscription struct {
+	exchanges map[string]bool // Exchange names, or Wildcard
+	symbols   map[string]bool // Canonical symbols ("BTC/USDT"), or Wildcard
+	channels  map[Channel]bool
+	ticks     map[string]types.TickLevel // Per canonical symbol; others use defaultTick
+	depth     int                        // Book levels per side
+	interval  time.Duration              // Between book and stats updates; trades are sent as they arrive
+	mode      Mode
+	quote     string    // Reference quote of stats and the consolidated book
+	lastSlot  time.Time // Interval slot of the latest update
+}
+
+// newSubscription returns the default subscription of a new client: book and stats of every
+// exchange streaming symbol, or every symbol if it is empty, in the given reference quote. With
+// the default symbol, this is what every client received when a single symbol ran at a time.
+func newSubscription(symbol, quote string) subscription {
+	if symbol == "" {
+		symbol = Wildcard
+	}
+	return subscription{
+		exchanges: map[string]bool{Wildcard: true},
+		symbols:   map[string]bool{symbol: true},
+		channels:  map[Channel]bool{ChannelBook: true, ChannelStats: true},
+		depth:     defaultBookDepth,
+		interval:  defaultUpdateInterval,
+		mode:      ModeSnapshot,
+		quote:     quote,
+	}
+}
+
+// wants reports whether a channel of an exchange streaming symbol should reach the client
+func (sub *subscription) wants(channel Channel, exchange, symbol string) bool {
+	return sub.channels[channel] &&
+		(sub.exchanges[Wildcard] || sub.exchanges[exchange]) &&
+		sub.follows(symbol)
+}
+
+// follows reports whether the client receives anything of symbol
+func (sub *subscription) follows(symbol string) bool {
+	return sub.symbols[Wildcard] || sub.symbols[symbol]
+}
+
+// tickFor returns the client's tick for symbol, moved inside the symbol's ladder, e.g. after
+// the ladder is rebuilt around a much lower price
+func (sub *subscription) tickFor(symbol string, ladder []types.TickLevel) types.TickLevel {
+	tick, ok := sub.ticks[symbol]
+	if !ok {
+		tick = defaultTick
+	}
+	if tick < ladder[0] {
+		return ladder[0]
+	}
+	if tick > ladder[len(ladder)-1] {
+		return ladder[len(ladder)-1]
+	}
+	return tick
+}
+
+// due reports whether the client's next book and stats update is due, and the slot it fills.
+// Slots are aligned to multiples of the interval, so clients at the same interval are updated
+// in the same push and can share its messages.
+func (sub *subscription) due(now time.Time) (time.Time, bool) {
+	// Allow some jitter so a ticker firing at the interval is never skipped
+	slot := now.Add(minUpdateInterval / 2).Truncate(sub.interval)
+	return slot, slot.After(sub.lastSlot)
+}
+
+// subscribe replaces every dimension named in msg, leaving the others as they are. The tick
+// applies to msg.Symbol, or else to every symbol subscribed to, and is validated against the
+// ladder of each; ladders holds those of the running symbols. The quote is validated by the
+// caller, which knows the conversion rates.
+func (sub *subscription) subscribe(msg ClientMessage, ladders map[string][]types.TickLevel) error {
+	next := *sub
+
+	if msg.Exchanges != nil {
+		next.exchanges = toSet(msg.Exchanges)
+	}
+	if msg.Symbols != nil {
+		canonical, err := canonicalSymbols(msg.Symbols)
+		if err != nil {
+			return err
+		}
+		next.symbols = toSet(canonical)
+	}
+	if msg.Channels != nil {
+		channels, err := parseChannels(msg.Channels)
+		if err != nil {
+			return err
+		}
+		next.channels = toSet(channels)
+	}
+	if msg.Tick != 0 {
+		targets, err := tickSymbols(msg.Symbol, next.symbols, ladders)
+		if err != nil {
+			return err
+		}
+		tick := types.TickLevel(msg.Tick)
+		next.ticks = make(map[string]types.TickLevel, len(sub.ticks)+len(targets))
+		maps.Copy(next.ticks, sub.ticks)
+		for _, symbol := range targets {
+			ladder, ok := ladders[symbol]
+			if !ok {
+				ladder = types.AvailableTickLevels // Until the symbol's instruments and prices are known
+			}
+			if !slices.Contains(ladder, tick) {
+				return fmt.Errorf("tick %v is not one of %v for %s", msg.Tick, ladder, symbol)
+			}
+			next.ticks[symbol] = tick
+		}
+	}
+	if msg.Depth != 0 {
+		if msg.Depth < 1 || msg.Depth > maxBookDepth {
+			return fmt.Errorf("depth must be between 1 and %d", maxBookDepth)
+		}
+		next.depth = msg.Depth
+	}
+	if msg.Interval != 0 {
+		interval := time.Duration(msg.Interval) * time.Millisecond
+		if interval < minUpdateInterval {
+			return fmt.Errorf("interval must be at least %dms", minUpdateInterval.Milliseconds())
+		}
+		next.interval = interval
+	}
+	if msg.Quote != "" {
+		next.quote = msg.Quote
+	}
+	switch Mode(msg.Mode) {
+	case "":
+	case ModeSnapshot, ModeDelta:
+		next.mode = Mode(msg.Mode)
+	default:
+		return fmt.Errorf("unknown mode %q", msg.Mode)
+	}
+
+	*sub = next
+	return nil
+}
+
+// unsubscribe removes the exchanges, symbols and channels named in msg. Removing Wildcard
+// stops everything not subscribed to by name.
+func (sub *subscription) unsubscribe(msg ClientMessage) error {
+	canonical, err := canonicalSymbols(msg.Symbols)
+	if err != nil {
+		return err
+	}
+	channels, err := parseChannels(msg.Channels)
+	if err != nil {
+		return err
+	}
+
+	// Sets are replaced rather than modified, so copies taken by the push loop stay intact
+	sub.exchanges = maps.Clone(sub.exchanges)
+	for _, name := range msg.Exchanges {
+		delete(sub.exchanges, name)
+	}
+	sub.symbols = maps.Clone(sub.symbols)
+	for _, symbol := range canonical {
+		delete(sub.symbols, symbol)
+	}
+	sub.channels = maps.Clone(sub.channels)
+	for _, channel := range channels {
+		delete(sub.channels, channel)
+	}
+	return nil
+}
+
+// tickSymbols returns the symbols a tick change applies to: the one named, or else every
+// symbol subscribed to, with Wildcard standing for every running symbol
+func tickSymbols(named string, subscribed map[string]bool, ladders map[string][]types.TickLevel) ([]string, error) {
+	if named != "" {
+		symbol, err := symbols.Parse(named)
+		if err != nil {
+			return nil, err
+		}
+		return []string{symbol.String()}, nil
+	}
+
+	targets := make(map[string]bool, len(subscribed))
+	for symbol := range subscribed {
+		if symbol != Wildcard {
+			targets[symbol] = true
+		}
+	}
+	if subscribed[Wildcard] {
+		for symbol := range ladders {
+			targets[symbol] = true
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no symbol to set the tick of")
+	}
+	return slices.Sorted(maps.Keys(targets)), nil
+}
+
+// canonicalSymbols parses symbols in any accepted notation, passing Wildcard through
+func canonicalSymbols(names []string) ([]string, error) {
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == Wildcard {
+			canonical = append(canonical, name)
+			continue
+		}
+		symbol, err := symbols.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		canonical = append(canonical, symbol.String())
+	}
+	return canonical, nil
+}
+
+// parseChannels validates channel names
+func parseChannels(names []string) ([]Channel, error) {
+	channels := make([]Channel, 0, len(names))
+	for _, name := range names {
+		channel := Channel(name)
+		switch channel {
+		case ChannelBook, ChannelStats, ChannelTrades:
+			channels = append(channels, channel)
+		default:
+			return nil, fmt.Errorf("unknown channel %q", name)
+		}
+	}
+	return channels, nil
+}
+
+func toSet[T comparable](items []T) map[T]bool {
+	set := make(map[T]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
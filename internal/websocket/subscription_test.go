@@ -0,0 +1,176 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"orderbook/internal/fx"
+	"orderbook/internal/types"
+)
+
+func TestSubscriptionDefaults(t *testing.T) {
+	sub := newSubscription("BTC/USDT", fx.USD)
+
+	if !sub.wants(ChannelBook, "binancef", "BTC/USDT") || !sub.wants(ChannelStats, "consolidated", "BTC/USDT") {
+		t.Error("Expected a new client to receive book and stats of every exchange")
+	}
+	if sub.wants(ChannelBook, "binancef", "ETH/USDT") {
+		t.Error("Expected a new client to follow only the default symbol")
+	}
+	if sub.wants(ChannelTrades, "binancef", "BTC/USDT") {
+		t.Error("Expected trades to require a subscription")
+	}
+
+	everything := newSubscription("", fx.USD)
+	if !everything.wants(ChannelBook, "binancef", "ETH/USDT") {
+		t.Error("Expected a client without a default symbol to follow every symbol")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ladder := []types.TickLevel{types.Tick1, types.Tick10}
+	ladders := map[string][]types.TickLevel{"BTC/USDT": ladder, "SOL/USDT": {0.01, 0.1}}
+
+	tests := []struct {
+		name    string
+		msg     ClientMessage
+		check   func(sub subscription) bool
+		wantErr bool
+	}{
+		{
+			name: "exchanges replace the wildcard",
+			msg:  ClientMessage{Exchanges: []string{"bybitf"}},
+			check: func(sub subscription) bool {
+				return sub.wants(ChannelBook, "bybitf", "BTC/USDT") && !sub.wants(ChannelBook, "binancef", "BTC/USDT")
+			},
+		},
+		{
+			name: "symbols in any notation",
+			msg:  ClientMessage{Symbols: []string{"ETHUSDT"}},
+			check: func(sub subscription) bool {
+				return sub.wants(ChannelStats, "binancef", "ETH/USDT") && !sub.wants(ChannelStats, "binancef", "BTC/USDT")
+			},
+		},
+		{
+			name: "channels replace the defaults",
+			msg:  ClientMessage{Channels: []string{"trades"}},
+			check: func(sub subscription) bool {
+				return sub.wants(ChannelTrades, "binancef", "BTC/USDT") && !sub.wants(ChannelBook, "binancef", "BTC/USDT")
+			},
+		},
+		{
+			name: "tick, depth and interval",
+			msg:  ClientMessage{Tick: 10, Depth: 50, Interval: 1000},
+			check: func(sub subscription) bool {
+				return sub.tickFor("BTC/USDT", ladder) == types.Tick10 && sub.depth == 50 && sub.interval == time.Second
+			},
+		},
+		{
+			name: "tick of a named symbol",
+			msg:  ClientMessage{Symbols: []string{Wildcard}, Symbol: "SOLUSDT", Tick: 0.1},
+			check: func(sub subscription) bool {
+				return sub.tickFor("SOL/USDT", ladders["SOL/USDT"]) == 0.1 && sub.tickFor("BTC/USDT", ladder) == types.Tick1
+			},
+		},
+		{
+			name:  "delta mode",
+			msg:   ClientMessage{Mode: "delta"},
+			check: func(sub subscription) bool { return sub.mode == ModeDelta },
+		},
+		{
+			name:  "quote",
+			msg:   ClientMessage{Quote: "USDT"},
+			check: func(sub subscription) bool { return sub.quote == "USDT" && sub.depth == defaultBookDepth },
+		},
+		{name: "tick outside the ladder", msg: ClientMessage{Tick: 100}, wantErr: true},
+		{name: "tick outside the ladder of one symbol", msg: ClientMessage{Symbols: []string{Wildcard}, Tick: 10}, wantErr: true},
+		{name: "depth too large", msg: ClientMessage{Depth: maxBookDepth + 1}, wantErr: true},
+		{name: "interval too short", msg: ClientMessage{Interval: 10}, wantErr: true},
+		{name: "unknown channel", msg: ClientMessage{Channels: []string{"candles"}}, wantErr: true},
+		{name: "unknown mode", msg: ClientMessage{Mode: "diff"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := newSubscription("BTC/USDT", fx.USD)
+			before := sub
+
+			err := sub.subscribe(tt.msg, ladders)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if len(sub.ticks) != len(before.ticks) || sub.depth != before.depth || len(sub.symbols) != len(before.symbols) {
+					t.Error("Expected a rejected subscribe to leave the subscription unchanged")
+				}
+				return
+			}
+			if !tt.check(sub) {
+				t.Errorf("Unexpected subscription %+v", sub)
+			}
+		})
+	}
+}
+
+func TestTickForClampsToLadder(t *testing.T) {
+	sub := newSubscription("ETH/USDT", fx.USD)
+	if err := sub.subscribe(ClientMessage{Tick: 100}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The ladder is rebuilt around a much lower price
+	ladder := []types.TickLevel{0.01, 0.1, 1}
+	if tick := sub.tickFor("ETH/USDT", ladder); tick != 1 {
+		t.Errorf("Expected the tick to move to the top of the ladder, got %v", tick)
+	}
+	if tick := sub.tickFor("SOL/USDT", []types.TickLevel{5, 10}); tick != 5 {
+		t.Errorf("Expected the default tick to move to the bottom of the ladder, got %v", tick)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	sub := newSubscription("BTC/USDT", fx.USD)
+	if err := sub.subscribe(ClientMessage{Exchanges: []string{"binancef", "bybitf"}, Channels: []string{"book", "trades"}}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	taken := sub
+
+	if err := sub.unsubscribe(ClientMessage{Exchanges: []string{"bybitf"}, Channels: []string{"book"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if sub.wants(ChannelTrades, "bybitf", "BTC/USDT") || sub.wants(ChannelBook, "binancef", "BTC/USDT") {
+		t.Error("Expected bybitf and the book channel to be removed")
+	}
+	if !sub.wants(ChannelTrades, "binancef", "BTC/USDT") {
+		t.Error("Expected binancef trades to remain")
+	}
+	if !taken.wants(ChannelBook, "bybitf", "BTC/USDT") {
+		t.Error("Expected a copy taken before unsubscribing to be unaffected")
+	}
+}
+
+func TestFrameCacheBuildsOnce(t *testing.T) {
+	frames := make(frameCache)
+	builds := 0
+	build := func() interface{} {
+		builds++
+		return TickLevelsMessage{Type: MessageTypeTickLevels}
+	}
+
+	key := frameKey{kind: MessageTypeOrderbook, exchange: "binancef", tick: types.Tick1, depth: 20}
+	first := frames.get(key, EncodingJSON, build)
+	second := frames.get(key, EncodingJSON, build)
+	packed := frames.get(key, EncodingMsgpack, build)
+	frames.get(frameKey{kind: MessageTypeOrderbook, exchange: "binancef", tick: types.Tick10, depth: 20}, EncodingJSON, build)
+
+	if first == nil || first != second {
+		t.Error("Expected the same encoded message for the same parameters")
+	}
+	if packed == nil || packed == first {
+		t.Error("Expected a separate message per encoding")
+	}
+	if builds != 2 {
+		t.Errorf("Expected 2 builds for 2 distinct parameter sets, got %d", builds)
+	}
+}